@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"sort"
 	"strings"
@@ -17,6 +18,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // ImageCmd holds image command
@@ -25,6 +27,7 @@ type ImageCmd struct {
 	Inspect ImageInspectCmd `cmd:"" help:"Display information of an image in database."`
 	Remove  ImageRemoveCmd  `cmd:"" help:"Remove an image manifest from database."`
 	Prune   ImagePruneCmd   `cmd:"" help:"Remove all manifests from the database."`
+	Diff    ImageDiffCmd    `cmd:"" help:"Compare the manifest of an image in database with the remote one."`
 }
 
 // ImageListCmd holds image list command
@@ -66,7 +69,7 @@ func (s *ImageListCmd) Run(_ *Context) error {
 	t.SetOutputMirror(os.Stdout)
 	t.AppendHeader(table.Row{"Name", "Manifests Count", "Latest Tag", "Latest Created", "Latest Digest"})
 	for _, image := range il.Images {
-		t.AppendRow(table.Row{image.Name, image.ManifestsCount, image.Latest.Tag, image.Latest.Created.AsTime().Format(time.RFC3339), image.Latest.Digest})
+		t.AppendRow(table.Row{image.Name, image.ManifestsCount, image.Latest.Tag, createdDate(image.Latest.Created), image.Latest.Digest})
 	}
 	t.AppendFooter(table.Row{"Total", len(il.Images)})
 	t.Render()
@@ -111,7 +114,7 @@ func (s *ImageInspectCmd) Run(_ *Context) error {
 	t.SetOutputMirror(os.Stdout)
 	t.AppendHeader(table.Row{"Tag", "Created", "Digest"})
 	for _, image := range ii.Image.Manifests {
-		t.AppendRow(table.Row{image.Tag, image.Created.AsTime().Format(time.RFC3339), image.Digest})
+		t.AppendRow(table.Row{image.Tag, createdDate(image.Created), image.Digest})
 	}
 	t.AppendFooter(table.Row{"Total", len(ii.Image.Manifests)})
 	t.Render()
@@ -146,7 +149,7 @@ func (s *ImageRemoveCmd) Run(_ *Context) error {
 	t.AppendHeader(table.Row{"Tag", "Created", "Digest", "Size"})
 	var totalSize int64
 	for _, image := range removed.Manifests {
-		t.AppendRow(table.Row{image.Tag, image.Created.AsTime().Format(time.RFC3339), image.Digest, units.HumanSize(float64(image.Size))})
+		t.AppendRow(table.Row{image.Tag, createdDate(image.Created), image.Digest, units.HumanSize(float64(image.Size))})
 		totalSize += image.Size
 	}
 	t.AppendFooter(table.Row{"Total", fmt.Sprintf("%d (%s)", len(removed.Manifests), units.HumanSize(float64(totalSize)))})
@@ -209,7 +212,7 @@ func (s *ImagePruneCmd) Run(_ *Context) error {
 	var totalManifest int
 	for _, image := range removed.Images {
 		for _, manifest := range image.Manifests {
-			t.AppendRow(table.Row{manifest.Tag, manifest.Created.AsTime().Format(time.RFC3339), manifest.Digest, units.HumanSize(float64(manifest.Size))})
+			t.AppendRow(table.Row{manifest.Tag, createdDate(manifest.Created), manifest.Digest, units.HumanSize(float64(manifest.Size))})
 			totalSize += manifest.Size
 		}
 		totalManifest += len(image.Manifests)
@@ -219,3 +222,98 @@ func (s *ImagePruneCmd) Run(_ *Context) error {
 
 	return nil
 }
+
+// ImageDiffCmd holds image diff command
+type ImageDiffCmd struct {
+	Image         string `name:"image" required:"" help:"Image to compare."`
+	Raw           bool   `name:"raw" default:"false" help:"JSON output."`
+	GRPCAuthority string `name:"grpc-authority" default:"127.0.0.1:42286" help:"Link to Diun gRPC server."`
+}
+
+func (s *ImageDiffCmd) Run(_ *Context) error {
+	conn, err := grpc.NewClient(s.GRPCAuthority, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	imageSvc := pb.NewImageServiceClient(conn)
+
+	diff, err := imageSvc.ImageDiff(context.Background(), &pb.ImageDiffRequest{
+		Name: s.Image,
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.Raw {
+		b, _ := protojson.Marshal(diff)
+		fmt.Println(string(pretty.Pretty(b)))
+		return nil
+	}
+
+	mt := table.NewWriter()
+	mt.SetOutputMirror(os.Stdout)
+	mt.AppendHeader(table.Row{"", "Stored", "Remote"})
+	mt.AppendRow(table.Row{"Digest", diff.Stored.Digest, diff.Remote.Digest})
+	mt.AppendRow(table.Row{"Created", createdDate(diff.Stored.Created), createdDate(diff.Remote.Created)})
+	if diff.Stored.ArtifactType != "" || diff.Remote.ArtifactType != "" {
+		mt.AppendRow(table.Row{"Artifact type", diff.Stored.ArtifactType, diff.Remote.ArtifactType})
+	} else {
+		mt.AppendRow(table.Row{"Platform", diff.Stored.Platform, diff.Remote.Platform})
+	}
+	mt.AppendRow(table.Row{"Size", humanSize(diff.Stored.Size), humanSize(diff.Remote.Size)})
+	mt.Render()
+
+	ct := table.NewWriter()
+	ct.SetOutputMirror(os.Stdout)
+	ct.AppendHeader(table.Row{"Type", "Name", "From", "To"})
+	for _, layer := range diff.LayersAdded {
+		ct.AppendRow(table.Row{"layer", layer, "", "added"})
+	}
+	for _, layer := range diff.LayersRemoved {
+		ct.AppendRow(table.Row{"layer", layer, "removed", ""})
+	}
+	for _, change := range diff.Labels {
+		ct.AppendRow(table.Row{"label", change.Name, change.From, change.To})
+	}
+	for _, change := range diff.Annotations {
+		ct.AppendRow(table.Row{"annotation", change.Name, change.From, change.To})
+	}
+	for _, change := range diff.Env {
+		ct.AppendRow(table.Row{"env", change.Name, change.From, change.To})
+	}
+	for _, change := range []*pb.ImageDiffResponse_Change{diff.Entrypoint, diff.Cmd} {
+		if change != nil {
+			ct.AppendRow(table.Row{change.Name, "", change.From, change.To})
+		}
+	}
+	if ct.Length() == 0 {
+		fmt.Println("No changes")
+		return nil
+	}
+	if diff.SizeDelta != 0 {
+		sign := "+"
+		if diff.SizeDelta < 0 {
+			sign = "-"
+		}
+		ct.AppendFooter(table.Row{"Size", "", "", sign + units.HumanSize(math.Abs(float64(diff.SizeDelta)))})
+	}
+	ct.Render()
+
+	return nil
+}
+
+func humanSize(size int64) string {
+	if size == 0 {
+		return "unknown"
+	}
+	return units.HumanSize(float64(size))
+}
+
+func createdDate(created *timestamppb.Timestamp) string {
+	if created == nil {
+		return "unknown"
+	}
+	return created.AsTime().Format(time.RFC3339)
+}
@@ -3,14 +3,17 @@ package app
 import (
 	"context"
 	"net"
+	"os"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/crazy-max/cron/v3"
 	"github.com/crazy-max/diun/v4/internal/config"
+	"github.com/crazy-max/diun/v4/internal/cosign"
 	"github.com/crazy-max/diun/v4/internal/db"
 	"github.com/crazy-max/diun/v4/internal/grpc"
+	"github.com/crazy-max/diun/v4/internal/links"
 	"github.com/crazy-max/diun/v4/internal/logging"
 	"github.com/crazy-max/diun/v4/internal/metrics"
 	"github.com/crazy-max/diun/v4/internal/model"
@@ -23,6 +26,8 @@ import (
 	kubernetesPrd "github.com/crazy-max/diun/v4/internal/provider/kubernetes"
 	nomadPrd "github.com/crazy-max/diun/v4/internal/provider/nomad"
 	swarmPrd "github.com/crazy-max/diun/v4/internal/provider/swarm"
+	"github.com/crazy-max/diun/v4/internal/regauth"
+	"github.com/crazy-max/diun/v4/pkg/registry"
 	"github.com/dromara/carbon/v2"
 	"github.com/panjf2000/ants/v2"
 	"github.com/pkg/errors"
@@ -40,11 +45,18 @@ type Diun struct {
 	metrics       *metrics.Recorder
 	metricsServer *metrics.Server
 	notif         *notif.Client
+	limiters      map[string]registry.Limiter
+	regauths      map[string]regauth.Provider
+	certDirs      map[string]string
+	verifiers     map[string]*cosign.Verifier
+	links         *links.Client
 
 	cron   *cron.Cron
 	jobID  cron.EntryID
 	locker uint32
 	pool   *ants.PoolWithFunc
+	queue  *jobQueue
+	limits *rateLimits
 	wg     *sync.WaitGroup
 }
 
@@ -59,6 +71,53 @@ func New(meta model.Meta, cfg *config.Config, grpcAuthority string) (*Diun, erro
 		)),
 	}
 
+	diun.limiters = make(map[string]registry.Limiter)
+	for _, regopt := range cfg.RegOpts {
+		if limiter := registry.NewLimiter(regopt.MaxConcurrency); limiter != nil {
+			diun.limiters[regopt.Name] = limiter
+		}
+	}
+
+	diun.certDirs = make(map[string]string)
+	for _, regopt := range cfg.RegOpts {
+		if len(regopt.TLSCACertFiles) == 0 && regopt.TLSClientCert == "" {
+			continue
+		}
+		certDir, err := registry.NewCertDir(regopt.TLSCACertFiles, regopt.TLSClientCert, regopt.TLSClientKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot set up certificates for regopts %s", regopt.Name)
+		}
+		diun.certDirs[regopt.Name] = certDir
+	}
+
+	diun.regauths = make(map[string]regauth.Provider)
+	for _, regopt := range cfg.RegOpts {
+		prv, err := regauth.New(regopt, meta.UserAgent)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot create registry authentication for regopts %s", regopt.Name)
+		}
+		if prv != nil {
+			diun.regauths[regopt.Name] = prv
+		}
+	}
+
+	diun.verifiers = make(map[string]*cosign.Verifier)
+	for _, regopt := range cfg.RegOpts {
+		if regopt.Cosign == nil {
+			continue
+		}
+		verifier, err := newVerifier(regopt.Cosign, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot create cosign verifier for regopts %s", regopt.Name)
+		}
+		diun.verifiers[regopt.Name] = verifier
+	}
+
+	diun.links, err = links.New(cfg.Watch.Links)
+	if err != nil {
+		return nil, err
+	}
+
 	diun.notif, err = notif.New(cfg.Notif, meta)
 	if err != nil {
 		return nil, err
@@ -69,7 +128,7 @@ func New(meta model.Meta, cfg *config.Config, grpcAuthority string) (*Diun, erro
 		return nil, err
 	}
 
-	diun.grpc, err = grpc.New(grpcAuthority, diun.db, diun.notif)
+	diun.grpc, err = grpc.New(grpcAuthority, diun.db, diun.notif, diun.remoteManifest)
 	if err != nil {
 		return nil, err
 	}
@@ -149,9 +208,15 @@ func (di *Diun) Start(ctx context.Context) error {
 			}
 		}
 		di.grpc.Stop()
+		di.notif.Close()
 		if err := di.db.Close(); err != nil {
 			log.Warn().Err(err).Msg("Cannot close database")
 		}
+		for _, certDir := range di.certDirs {
+			if err := os.RemoveAll(certDir); err != nil {
+				log.Warn().Err(err).Msg("Cannot remove certificates directory")
+			}
+		}
 	}()
 
 	serverErrCh := make(chan error, 2)
@@ -221,11 +286,15 @@ func (di *Diun) Run() {
 	di.wg = new(sync.WaitGroup)
 	di.pool, _ = ants.NewPoolWithFunc(di.cfg.Watch.Workers, func(i interface{}) {
 		job := i.(model.Job)
-		entries.Add(di.runJob(job))
+		for _, entry := range di.runJob(job) {
+			entries.Add(entry)
+		}
 		di.wg.Done()
 	}, ants.WithLogger(new(logging.AntsLogger)))
 	defer di.pool.Release()
 
+	di.queue = newJobQueue()
+	di.limits = newRateLimits(*di.cfg.Watch.RateLimitThreshold)
 	provider.WalkJobs(di.createJob,
 		dockerPrd.New(di.cfg.Providers.Docker, di.cfg.Defaults),
 		swarmPrd.New(di.cfg.Providers.Swarm, di.cfg.Defaults),
@@ -235,8 +304,10 @@ func (di *Diun) Run() {
 		dockerfilePrd.New(di.cfg.Providers.Dockerfile, di.cfg.Defaults),
 		nomadPrd.New(di.cfg.Providers.Nomad, di.cfg.Defaults),
 	)
+	di.invokeJobs()
 
 	di.wg.Wait()
+	di.notif.Resolve(entries.Entries)
 	completedAt := time.Now()
 	if di.metrics != nil {
 		di.metrics.RecordRun(entries, completedAt.Sub(startedAt), completedAt)
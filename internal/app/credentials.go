@@ -0,0 +1,35 @@
+package app
+
+import (
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/regauth"
+	"go.podman.io/image/v5/pkg/docker/config"
+	"go.podman.io/image/v5/types"
+)
+
+// registryCredentials returns the credentials to use for a registry domain
+// along with the source that supplied them. Sources are looked up in the
+// following order: registry options username, cloud provider, credential
+// helper, auth file and finally the default Docker and Podman auth files.
+func (di *Diun) registryCredentials(reg *model.RegOpt, domain string, username string, password string) (types.DockerAuthConfig, string, error) {
+	if len(username) > 0 {
+		return types.DockerAuthConfig{
+			Username: username,
+			Password: password,
+		}, "regopts", nil
+	}
+	if prv, ok := di.regauths[reg.Name]; ok {
+		auth, err := prv.Credentials(domain)
+		return auth, prv.Name(), err
+	}
+	if reg.CredentialHelper != "" {
+		auth, err := regauth.FromCredentialHelper(reg.CredentialHelper, domain)
+		return auth, "credentialHelper", err
+	}
+	if reg.AuthFile != "" {
+		auth, err := regauth.FromAuthFile(reg.AuthFile, domain)
+		return auth, "authFile", err
+	}
+	auth, err := config.GetCredentials(nil, domain)
+	return auth, "default", err
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/regauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/types"
+)
+
+func TestRegistryCredentials(t *testing.T) {
+	authFile := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(authFile, fmt.Appendf(nil, `{"auths":{"registry.test":{"auth":%q}}}`,
+		base64.StdEncoding.EncodeToString([]byte("file:secret"))), 0o600))
+
+	di := &Diun{regauths: make(map[string]regauth.Provider)}
+
+	auth, source, err := di.registryCredentials(&model.RegOpt{Name: "myregistry", AuthFile: authFile}, "registry.test", "foo", "bar")
+	require.NoError(t, err)
+	assert.Equal(t, "regopts", source)
+	assert.Equal(t, types.DockerAuthConfig{Username: "foo", Password: "bar"}, auth)
+
+	auth, source, err = di.registryCredentials(&model.RegOpt{Name: "myregistry", AuthFile: authFile}, "registry.test", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "authFile", source)
+	assert.Equal(t, types.DockerAuthConfig{Username: "file", Password: "secret"}, auth)
+}
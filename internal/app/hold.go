@@ -0,0 +1,132 @@
+package app
+
+import (
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/db"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/rs/zerolog"
+)
+
+// minAge returns the minimum age of updates of an image, 0 if none
+func minAge(img model.Image) time.Duration {
+	if img.MinAge == nil || *img.MinAge <= 0 {
+		return 0
+	}
+	return *img.MinAge
+}
+
+// jobManifest returns the manifest last reported to the consumers of a job.
+// Consumers holding back updates keep their own manifest along with the held
+// update, as consumers with another minimum age may already have been
+// notified of a more recent one.
+func (di *Diun) jobManifest(job model.Job) (registry.Manifest, error) {
+	manifest, err := di.db.GetManifest(job.RegImage)
+	if err != nil || minAge(job.Image) == 0 {
+		return manifest, err
+	}
+	hold, err := di.db.GetHold(job.RegImage, minAge(job.Image))
+	if err != nil {
+		return registry.Manifest{}, err
+	}
+	if len(hold.Manifest.Name) > 0 {
+		return hold.Manifest, nil
+	}
+	return manifest, nil
+}
+
+// putJobManifest saves the manifest reported to the consumers of a job. The
+// manifest of the image is only moved forward by consumers holding back
+// updates if no other consumer already did.
+func (di *Diun) putJobManifest(job model.Job, dbManifest, manifest registry.Manifest) error {
+	age := minAge(job.Image)
+	if age == 0 {
+		return di.db.PutManifest(job.RegImage, manifest)
+	}
+
+	hold, err := di.db.GetHold(job.RegImage, age)
+	if err != nil {
+		return err
+	}
+	hold.Manifest = manifest
+	if err := di.db.PutHold(job.RegImage, age, hold); err != nil {
+		return err
+	}
+
+	stored, err := di.db.GetManifest(job.RegImage)
+	if err != nil {
+		return err
+	}
+	if len(stored.Name) > 0 && stored.Digest != dbManifest.Digest {
+		return nil
+	}
+	return di.db.PutManifest(job.RegImage, manifest)
+}
+
+// holdUpdate checks if an update is held back because it is younger than the
+// minimum age of the image. Held updates are persisted in the db while the
+// previous manifest is kept, so they are reported once they mature. The age
+// is computed from the creation date of the manifest, or from the date the
+// update has been found if unknown.
+func (di *Diun) holdUpdate(job model.Job, manifest registry.Manifest, update bool, sublog zerolog.Logger) (bool, error) {
+	age := minAge(job.Image)
+	if age == 0 {
+		return false, nil
+	}
+	hold, err := di.db.GetHold(job.RegImage, age)
+	if err != nil {
+		return false, err
+	}
+
+	if !update {
+		return false, releaseHold(di.db, job, hold)
+	}
+
+	if hold.Digest != manifest.Digest {
+		hold.Digest = manifest.Digest
+		hold.Since = time.Now().UTC()
+	}
+	until := createdAt(manifest, hold.Since).Add(age)
+	if !time.Now().Before(until) {
+		sublog.Debug().Msg("Minimum age reached, releasing update")
+		return false, releaseHold(di.db, job, hold)
+	}
+
+	if err := di.db.PutHold(job.RegImage, age, hold); err != nil {
+		return false, err
+	}
+	sublog.Info().Time("until", until).Msg("Update held back (minimum age not reached)")
+	return true, nil
+}
+
+// releaseHold clears the held update of an image if any
+func releaseHold(dbcli *db.Client, job model.Job, hold db.Hold) error {
+	if len(hold.Digest) == 0 {
+		return nil
+	}
+	age := minAge(job.Image)
+	if len(hold.Manifest.Name) == 0 {
+		return dbcli.DeleteHold(job.RegImage, age)
+	}
+	hold.Digest, hold.Since = "", time.Time{}
+	return dbcli.PutHold(job.RegImage, age, hold)
+}
+
+// createdAt returns the date a manifest has been created, or the fallback
+// date if unknown
+func createdAt(manifest registry.Manifest, fallback time.Time) time.Time {
+	if manifest.Created == nil || manifest.Created.IsZero() {
+		return fallback
+	}
+	return *manifest.Created
+}
+
+// tooOld checks if a manifest is older than the maximum age of an image.
+// Manifests without creation date are never too old.
+func tooOld(img model.Image, manifest registry.Manifest) bool {
+	if img.MaxAge == nil || *img.MaxAge <= 0 || manifest.Created == nil || manifest.Created.IsZero() {
+		return false
+	}
+	return time.Since(*manifest.Created) > *img.MaxAge
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldUpdate(t *testing.T) {
+	di := newTestDiun(t, "")
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "redis:7"})
+	require.NoError(t, err)
+	job := model.Job{
+		RegImage: image,
+		Image:    model.Image{MinAge: new(24 * time.Hour)},
+	}
+	manifest := registry.Manifest{
+		Digest:  digest.FromString("redis:7"),
+		Created: new(time.Now().Add(-time.Hour)),
+	}
+
+	held, err := di.holdUpdate(job, manifest, true, zerolog.Nop())
+	require.NoError(t, err)
+	assert.True(t, held)
+	hold, err := di.db.GetHold(image, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Digest, hold.Digest)
+
+	held, err = di.holdUpdate(job, manifest, true, zerolog.Nop())
+	require.NoError(t, err)
+	assert.True(t, held)
+	again, err := di.db.GetHold(image, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, hold.Since, again.Since, "hold must be kept for the same digest")
+
+	manifest.Created = new(time.Now().Add(-48 * time.Hour))
+	held, err = di.holdUpdate(job, manifest, true, zerolog.Nop())
+	require.NoError(t, err)
+	assert.False(t, held)
+	hold, err = di.db.GetHold(image, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, hold.Digest)
+
+	// the first time the update has been found is used without created date
+	manifest.Created = nil
+	held, err = di.holdUpdate(job, manifest, true, zerolog.Nop())
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	held, err = di.holdUpdate(job, manifest, false, zerolog.Nop())
+	require.NoError(t, err)
+	assert.False(t, held)
+	hold, err = di.db.GetHold(image, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, hold.Digest)
+}
+
+func TestJobManifestPerMinAge(t *testing.T) {
+	di := newTestDiun(t, "")
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "redis:7"})
+	require.NoError(t, err)
+	previous := registry.Manifest{Name: "docker.io/library/redis", Tag: "7", Digest: digest.FromString("redis:7")}
+	update := registry.Manifest{Name: "docker.io/library/redis", Tag: "7", Digest: digest.FromString("redis:7.1")}
+	day := model.Job{RegImage: image, Image: model.Image{MinAge: new(24 * time.Hour)}}
+	week := model.Job{RegImage: image, Image: model.Image{MinAge: new(7 * 24 * time.Hour)}}
+
+	require.NoError(t, di.putJobManifest(day, registry.Manifest{}, previous))
+	require.NoError(t, di.putJobManifest(week, registry.Manifest{}, previous))
+	stored, err := di.jobManifest(week)
+	require.NoError(t, err)
+	assert.Equal(t, previous.Digest, stored.Digest)
+
+	// update released for consumers with the shortest minimum age
+	require.NoError(t, di.putJobManifest(day, previous, update))
+	stored, err = di.jobManifest(day)
+	require.NoError(t, err)
+	assert.Equal(t, update.Digest, stored.Digest)
+	stored, err = di.db.GetManifest(image)
+	require.NoError(t, err)
+	assert.Equal(t, update.Digest, stored.Digest)
+
+	// still pending for consumers with a longer minimum age
+	stored, err = di.jobManifest(week)
+	require.NoError(t, err)
+	assert.Equal(t, previous.Digest, stored.Digest)
+
+	// the manifest of the image is not moved back by late consumers
+	require.NoError(t, di.putJobManifest(model.Job{RegImage: image}, update, update))
+	require.NoError(t, di.putJobManifest(week, previous, previous))
+	stored, err = di.db.GetManifest(image)
+	require.NoError(t, err)
+	assert.Equal(t, update.Digest, stored.Digest)
+}
+
+func TestTooOld(t *testing.T) {
+	img := model.Image{MaxAge: new(24 * time.Hour)}
+	assert.False(t, tooOld(img, registry.Manifest{Created: new(time.Now().Add(-time.Hour))}))
+	assert.True(t, tooOld(img, registry.Manifest{Created: new(time.Now().Add(-48 * time.Hour))}))
+	assert.False(t, tooOld(img, registry.Manifest{}))
+	assert.False(t, tooOld(model.Image{}, registry.Manifest{Created: new(time.Now().Add(-48 * time.Hour))}))
+}
@@ -4,16 +4,14 @@ import (
 	stderrors "errors"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"dario.cat/mergo"
 	"github.com/crazy-max/diun/v4/internal/matcher"
 	"github.com/crazy-max/diun/v4/internal/model"
-	"github.com/crazy-max/diun/v4/internal/secret"
 	"github.com/crazy-max/diun/v4/pkg/registry"
 	"github.com/rs/zerolog/log"
 	podmanmanifest "go.podman.io/image/v5/manifest"
-	"go.podman.io/image/v5/pkg/docker/config"
-	"go.podman.io/image/v5/types"
 )
 
 func (di *Diun) createJob(job model.Job) {
@@ -54,20 +52,13 @@ func (di *Diun) createJob(job model.Job) {
 		reg = (&model.RegOpt{}).GetDefaults()
 	}
 
-	regUser, err := secret.GetSecret(reg.Username, reg.UsernameFile)
-	if err != nil {
-		log.Warn().Err(err).Msgf("Cannot retrieve username secret for regopts %s", reg.Name)
-	}
-	regPassword, err := secret.GetSecret(reg.Password, reg.PasswordFile)
-	if err != nil {
-		log.Warn().Err(err).Msgf("Cannot retrieve password secret for regopts %s", reg.Name)
-	}
-
 	// Set defaults
 	if err := mergo.Merge(&job.Image, model.Image{
-		Platform:  model.ImagePlatform{},
-		WatchRepo: new(false),
-		MaxTags:   0,
+		Platform:     model.ImagePlatform{},
+		WatchRepo:    new(false),
+		MaxTags:      0,
+		MatchVariant: new(false),
+		Artifact:     new(false),
 	}); err != nil {
 		sublog.Error().Err(err).Msg("Cannot set default values")
 		return
@@ -87,65 +78,66 @@ func (di *Diun) createJob(job model.Job) {
 		}
 	}
 
-	var auth types.DockerAuthConfig
-	if len(regUser) > 0 {
-		auth = types.DockerAuthConfig{
-			Username: regUser,
-			Password: regPassword,
-		}
-	} else {
-		auth, err = config.GetCredentials(nil, job.RegImage.Domain)
-		if err != nil {
-			sublog.Warn().Err(err).Msg("Error seeking Docker credentials")
-		}
-	}
-
-	job.Registry, err = registry.New(registry.Options{
-		Auth:          auth,
-		Timeout:       *reg.Timeout,
-		InsecureTLS:   *reg.InsecureTLS,
-		UserAgent:     di.meta.UserAgent,
-		CompareDigest: *di.cfg.Watch.CompareDigest,
-		ImageOs:       job.Image.Platform.OS,
-		ImageArch:     job.Image.Platform.Arch,
-		ImageVariant:  job.Image.Platform.Variant,
-	})
+	job.Registry, err = di.registryClient(reg, job.RegImage, job.Image, sublog)
 	if err != nil {
 		sublog.Error().Err(err).Msg("Cannot create registry client")
 		return
 	}
 
-	di.wg.Add(1)
-	err = di.pool.Invoke(job)
+	job.Verifier, err = di.jobVerifier(reg, job.Image)
 	if err != nil {
-		sublog.Error().Err(err).Msgf("Invoking job")
+		sublog.Error().Err(err).Msg("Cannot create cosign verifier")
+		return
 	}
+	job.RequireSigned = reg.Cosign != nil && *reg.Cosign.RequireSigned
+
+	di.queueJob(job, reg.Name)
 
 	if !*job.Image.WatchRepo || len(job.RegImage.Domain) == 0 {
 		return
 	}
 
-	tags, err := job.Registry.Tags(registry.TagsOptions{
-		Image:   job.RegImage,
-		Max:     job.Image.MaxTags,
-		Sort:    job.Image.SortTags,
-		Include: job.Image.IncludeTags,
-		Exclude: job.Image.ExcludeTags,
-	})
-	if err != nil {
-		sublog.Error().Err(err).Msg("Cannot list tags from registry")
-		return
-	}
+	tagsKey := tagsKey(job.RegImage, job.Image, reg.Name)
+	tags, ok := di.queue.Tags(tagsKey)
+	if !ok {
+		tagsCache, err := di.db.GetTags(reg.Name, job.RegImage)
+		if err != nil {
+			sublog.Warn().Err(err).Msg("Cannot get cached tags from db")
+			tagsCache = &registry.TagsCache{}
+		}
+		tags, err = job.Registry.Tags(registry.TagsOptions{
+			Image:     job.RegImage,
+			Max:       job.Image.MaxTags,
+			Sort:      job.Image.SortTags,
+			SortRegex: job.Image.SortRegex,
+			Include:   job.Image.IncludeTags,
+			Exclude:   job.Image.ExcludeTags,
+			Variant:   *job.Image.MatchVariant,
+			Cache:     tagsCache,
+			CacheTTL:  *di.cfg.Watch.TagsCacheTTL,
+		})
+		if err != nil {
+			sublog.Error().Err(err).Msg("Cannot list tags from registry")
+			return
+		}
+		if err := di.db.PutTags(reg.Name, job.RegImage, tagsCache); err != nil {
+			sublog.Warn().Err(err).Msg("Cannot write cached tags to db")
+		}
+		di.queue.SetTags(tagsKey, tags)
 
-	log.Debug().Str("image", job.RegImage.String()).Msgf("%d tag(s) found in repository. %d will be analyzed (%d max, %d not included, %d excluded, %d artifact tags).",
-		tags.Total,
-		len(tags.List),
-		job.Image.MaxTags,
-		tags.NotIncluded,
-		tags.Excluded,
-		tags.Artifacts,
-	)
+		log.Debug().Str("image", job.RegImage.String()).Msgf("%d tag(s) found in repository. %d will be analyzed (%d max, %d not included, %d excluded, %d artifact tags, %d other variants).",
+			tags.Total,
+			len(tags.List),
+			job.Image.MaxTags,
+			tags.NotIncluded,
+			tags.Excluded,
+			tags.Artifacts,
+			tags.Variants,
+		)
+	}
 
+	// other tags of the repository are not run by the workload
+	job.Image.RunningDigest = ""
 	for _, tag := range tags.List {
 		if prvImage.Tag == tag {
 			continue
@@ -159,44 +151,135 @@ func (di *Diun) createJob(job model.Job) {
 			sublog.Error().Err(err).Msg("Cannot parse image (tag)")
 			continue
 		}
+		di.queueJob(job, reg.Name)
+	}
+}
+
+// queueJob adds a job to the run queue. Jobs sharing the same image reference,
+// platform and registry options are merged so the registry is only queried once.
+func (di *Diun) queueJob(job model.Job, regopt string) {
+	key := jobKey(job.RegImage, job.Image.Platform, regopt)
+	if job.HubLinkOverride != "" || job.Image.HubTpl != "" {
+		// the hub link of the notification is computed once per check
+		key += "|hub|" + job.HubLinkOverride + "|" + job.Image.HubTpl
+	}
+	if job.FirstCheck {
+		// first checks may not notify, depending on the watch settings
+		key += "|first"
+	}
+	if job.Image.CosignKey != "" {
+		// consumers verifying signatures with their own key cannot share
+		// the registry check
+		key += "|" + job.Image.CosignKey
+	}
+	if requireSigned(job, model.JobConsumer{Image: job.Image}) {
+		// unsigned updates are not saved until all consumers can be notified
+		key += "|require_signed"
+	}
+	if artifactMode(job.Image) {
+		// consumers watching OCI artifacts get a manifest where others skip
+		key += "|artifact|" + strings.Join(job.Image.ArtifactTypes, ";")
+	}
+	if age := minAge(job.Image); age > 0 {
+		// consumers holding back young updates keep the previous manifest
+		key += "|min_age|" + age.String()
+	}
+	if !di.queue.Add(key, job) {
+		log.Debug().
+			Str("provider", job.Provider).
+			Str("image", job.RegImage.String()).
+			Msg("Image already queued, sharing registry check")
+	}
+}
+
+// invokeJobs submits queued jobs to the workers pool
+func (di *Diun) invokeJobs() {
+	for _, job := range di.queue.Jobs() {
 		di.wg.Add(1)
-		err = di.pool.Invoke(job)
-		if err != nil {
-			sublog.Error().Err(err).Msgf("Invoking job (tag)")
+		if err := di.pool.Invoke(job); err != nil {
+			log.Error().Err(err).
+				Str("provider", job.Provider).
+				Str("image", job.RegImage.String()).
+				Msg("Invoking job")
+		}
+	}
+}
+
+func (di *Diun) runJob(job model.Job) (entries []model.NotifEntry) {
+	var consumers []model.JobConsumer
+	for _, consumer := range job.Consumers {
+		sublog := log.With().
+			Str("provider", consumer.Provider).
+			Str("image", job.RegImage.String()).
+			Logger()
+		if !matcher.IsIncluded(job.RegImage.Tag, consumer.Image.IncludeTags) {
+			sublog.Debug().Msg("Tag not included")
+		} else if matcher.IsExcluded(job.RegImage.Tag, consumer.Image.ExcludeTags) {
+			sublog.Debug().Msg("Tag excluded")
+		} else {
+			consumers = append(consumers, consumer)
+			continue
 		}
+		entries = append(entries, model.NotifEntry{
+			Status:        model.ImageStatusSkip,
+			Provider:      consumer.Provider,
+			Image:         job.RegImage,
+			Metadata:      consumer.Image.Metadata,
+			RunningDigest: consumer.Image.RunningDigest,
+		})
+	}
+	if len(consumers) == 0 {
+		return entries
+	}
+
+	entry := di.checkJob(job, consumers)
+	for _, consumer := range consumers {
+		consumerEntry := entry
+		consumerEntry.Provider = consumer.Provider
+		consumerEntry.Metadata = consumer.Image.Metadata
+		consumerEntry.RunningDigest = consumer.Image.RunningDigest
+		entries = append(entries, consumerEntry)
 	}
+	return entries
 }
 
-func (di *Diun) runJob(job model.Job) (entry model.NotifEntry) {
+func (di *Diun) checkJob(job model.Job, consumers []model.JobConsumer) (entry model.NotifEntry) {
 	var err error
 	entry = model.NotifEntry{
-		Status:   model.ImageStatusError,
-		Provider: job.Provider,
-		Image:    job.RegImage,
-		Metadata: job.Image.Metadata,
+		Status:        model.ImageStatusError,
+		Provider:      consumers[0].Provider,
+		Image:         job.RegImage,
+		Metadata:      consumers[0].Image.Metadata,
+		RunningDigest: consumers[0].Image.RunningDigest,
+	}
+	for _, consumer := range consumers {
+		entry.Consumers = append(entry.Consumers, model.NotifConsumer{
+			Provider:      consumer.Provider,
+			Metadata:      consumer.Image.Metadata,
+			RunningDigest: consumer.Image.RunningDigest,
+		})
 	}
 
 	sublog := log.With().
-		Str("provider", job.Provider).
+		Str("provider", entry.Provider).
 		Str("image", job.RegImage.String()).
 		Logger()
-
-	if !matcher.IsIncluded(job.RegImage.Tag, job.Image.IncludeTags) {
-		entry.Status = model.ImageStatusSkip
-		sublog.Debug().Msg("Tag not included")
-		return
-	} else if matcher.IsExcluded(job.RegImage.Tag, job.Image.ExcludeTags) {
-		entry.Status = model.ImageStatusSkip
-		sublog.Debug().Msg("Tag excluded")
-		return
+	if len(consumers) > 1 {
+		sublog = sublog.With().Int("consumers", len(consumers)).Logger()
 	}
 
-	dbManifest, err := di.db.GetManifest(job.RegImage)
+	dbManifest, err := di.jobManifest(job)
 	if err != nil {
 		sublog.Error().Err(err).Msg("Cannot get manifest from db")
 		return
 	}
 
+	if di.deferJob(job) {
+		entry.Status = model.ImageStatusSkip
+		sublog.Debug().Msg("Registry rate limit nearly exhausted, check deferred to next run")
+		return
+	}
+
 	var updated bool
 	entry.Manifest, updated, err = job.Registry.Manifest(job.RegImage, dbManifest)
 	if err != nil {
@@ -205,12 +288,24 @@ func (di *Diun) runJob(job model.Job) (entry model.NotifEntry) {
 			sublog.Debug().Err(err).Msg("Skipping non-image artifact")
 			return
 		}
+		if registry.IsTooManyRequests(err) {
+			di.exhaustRateLimit(job.RegImage.Domain)
+			entry.Status = model.ImageStatusSkip
+			sublog.Warn().Err(err).Msg("Registry rate limit exceeded, check deferred to next run")
+			return
+		}
 		sublog.Warn().Err(err).Msg("Cannot get remote manifest")
 		return
 	}
 
+	if updated || !*di.cfg.Watch.CompareDigest || entry.Manifest.Digest != dbManifest.Digest {
+		di.consumeRateLimit(job.RegImage.Domain)
+	}
+
 	if v, ok := entry.Manifest.Labels["org.opencontainers.image.url"]; ok {
 		entry.Image.HubLink = v
+	} else if v, ok := entry.Manifest.Annotations["org.opencontainers.image.url"]; ok {
+		entry.Image.HubLink = v
 	}
 	if job.HubLinkOverride != "" {
 		entry.Image.HubLink = job.HubLinkOverride
@@ -226,11 +321,31 @@ func (di *Diun) runJob(job model.Job) (entry model.NotifEntry) {
 		entry.Status = model.ImageStatusUnchange
 		sublog.Debug().Msg("No changes")
 	}
-	if entry.Status == model.ImageStatusUpdate || (entry.Status == model.ImageStatusNew && !job.FirstCheck) {
+	update := entry.Status == model.ImageStatusUpdate || (entry.Status == model.ImageStatusNew && !job.FirstCheck)
+	if held, err := di.holdUpdate(job, entry.Manifest, update, sublog); err != nil {
+		entry.Status = model.ImageStatusError
+		sublog.Error().Err(err).Msg("Cannot check held update")
+		return
+	} else if held {
+		entry.Status = model.ImageStatusSkip
+		return
+	}
+
+	if entry.Status != model.ImageStatusUnchange && job.Verifier != nil {
+		entry.Signature = di.verifySignature(job, entry.Manifest.Digest, sublog)
+	}
+	if update && requireSigned(job, consumers[0]) && (entry.Signature == nil || !entry.Signature.Verified) {
+		// the manifest is not saved so the update is checked again on next
+		// runs until its signature can be verified
+		entry.Status = model.ImageStatusSkip
+		sublog.Warn().Msg("Skipping notification (signature not verified)")
+		return
+	}
+	if update {
 		entry.MarkUpdateAvailable()
 	}
 
-	if err := di.db.PutManifest(job.RegImage, entry.Manifest); err != nil {
+	if err := di.putJobManifest(job, dbManifest, entry.Manifest); err != nil {
 		sublog.Error().Err(err).Msg("Cannot write manifest to db")
 		return
 	}
@@ -244,12 +359,52 @@ func (di *Diun) runJob(job model.Job) (entry model.NotifEntry) {
 		return
 	}
 
+	// Only notify consumers subscribed to this status
 	notifyOn := model.NotifyOn(entry.Status)
-	if !notifyOn.OneOf(job.Image.NotifyOn) {
-		sublog.Debug().Msgf("Skipping notification (%s not part of specified notify status)", entry.Status)
+	var notifConsumers []model.NotifConsumer
+	var unsigned, stale int
+	for i, consumer := range consumers {
+		if !notifyOn.OneOf(consumer.Image.NotifyOn) {
+			continue
+		}
+		if tooOld(consumer.Image, entry.Manifest) {
+			stale++
+			continue
+		}
+		if requireSigned(job, consumer) && (entry.Signature == nil || !entry.Signature.Verified) {
+			unsigned++
+			continue
+		}
+		notifConsumers = append(notifConsumers, entry.Consumers[i])
+	}
+	if unsigned > 0 {
+		sublog.Warn().Msg("Skipping notification (signature not verified)")
+	}
+	if stale > 0 {
+		sublog.Debug().Msg("Skipping notification (maximum age exceeded)")
+	}
+	if len(notifConsumers) == 0 {
+		if unsigned == 0 && stale == 0 {
+			sublog.Debug().Msgf("Skipping notification (%s not part of specified notify status)", entry.Status)
+		}
 		return
 	}
 
-	di.notif.Send(entry)
+	notifEntry := entry
+	if entry.Status == model.ImageStatusUpdate {
+		diff := registry.DiffManifests(dbManifest, entry.Manifest)
+		notifEntry.Diff = &diff
+	}
+	if notifEntry.Links, err = di.links.Links(dbManifest, entry.Manifest); err != nil {
+		sublog.Warn().Err(err).Msg("Cannot build source links")
+	}
+	if *di.cfg.Watch.SupplyChain {
+		notifEntry.SupplyChain = supplyChain(job, dbManifest, entry.Manifest, sublog)
+	}
+	notifEntry.Provider = notifConsumers[0].Provider
+	notifEntry.Metadata = notifConsumers[0].Metadata
+	notifEntry.RunningDigest = notifConsumers[0].RunningDigest
+	notifEntry.Consumers = notifConsumers
+	di.notif.Send(notifEntry)
 	return
 }
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+)
+
+// jobQueue groups jobs sharing the same registry check during a run so an
+// image referenced by several providers is only analyzed once.
+type jobQueue struct {
+	keys []string
+	jobs map[string]*model.Job
+	tags map[string]*registry.Tags
+}
+
+func newJobQueue() *jobQueue {
+	return &jobQueue{
+		jobs: make(map[string]*model.Job),
+		tags: make(map[string]*registry.Tags),
+	}
+}
+
+// Add queues a job or merges it as a consumer of an already queued job
+// with the same key. It returns false if the job has been merged.
+func (q *jobQueue) Add(key string, job model.Job) bool {
+	consumer := model.JobConsumer{
+		Provider: job.Provider,
+		Image:    job.Image,
+	}
+	if queued, ok := q.jobs[key]; ok {
+		for _, c := range queued.Consumers {
+			if reflect.DeepEqual(c, consumer) {
+				return false
+			}
+		}
+		queued.Consumers = append(queued.Consumers, consumer)
+		return false
+	}
+	job.Consumers = []model.JobConsumer{consumer}
+	q.keys = append(q.keys, key)
+	q.jobs[key] = &job
+	return true
+}
+
+// Jobs returns queued jobs in the order they were added
+func (q *jobQueue) Jobs() []model.Job {
+	jobs := make([]model.Job, 0, len(q.keys))
+	for _, key := range q.keys {
+		jobs = append(jobs, *q.jobs[key])
+	}
+	return jobs
+}
+
+// Tags returns the tags already listed for a key
+func (q *jobQueue) Tags(key string) (*registry.Tags, bool) {
+	tags, ok := q.tags[key]
+	return tags, ok
+}
+
+// SetTags stores the tags listed for a key
+func (q *jobQueue) SetTags(key string, tags *registry.Tags) {
+	q.tags[key] = tags
+}
+
+// jobKey returns the key identifying a registry check for an image
+func jobKey(image registry.Image, platform model.ImagePlatform, regopt string) string {
+	return strings.Join([]string{
+		image.String(),
+		fmt.Sprintf("%s/%s/%s", platform.OS, platform.Arch, platform.Variant),
+		regopt,
+	}, "|")
+}
+
+// tagsKey returns the key identifying a tags listing for an image
+func tagsKey(image registry.Image, opts model.Image, regopt string) string {
+	return strings.Join([]string{
+		fmt.Sprintf("%s/%s", image.Domain, image.Path),
+		regopt,
+		fmt.Sprintf("%d", opts.MaxTags),
+		string(opts.SortTags),
+		opts.SortRegex,
+		strings.Join(opts.IncludeTags, ";"),
+		strings.Join(opts.ExcludeTags, ";"),
+		tagsVariantKey(image, opts),
+	}, "|")
+}
+
+// tagsVariantKey returns the variant tags are filtered on, as images with
+// other tags of the same repository may compare against another variant
+func tagsVariantKey(image registry.Image, opts model.Image) string {
+	if opts.MatchVariant == nil || !*opts.MatchVariant {
+		return ""
+	}
+	if variant, ok := registry.TagVariant(image.Tag); ok {
+		return "variant:" + variant
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobQueueMergesConsumers(t *testing.T) {
+	redis, err := registry.ParseImage(registry.ParseImageOptions{Name: "redis:7"})
+	require.NoError(t, err)
+	redisFull, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/library/redis:7"})
+	require.NoError(t, err)
+	alpine, err := registry.ParseImage(registry.ParseImageOptions{Name: "alpine:3.19"})
+	require.NoError(t, err)
+
+	q := newJobQueue()
+	dockerJob := model.Job{
+		Provider: "docker",
+		Image:    model.Image{Name: "redis:7", Metadata: map[string]string{"ctn_names": "cache"}},
+		RegImage: redis,
+	}
+	fileJob := model.Job{
+		Provider: "file",
+		Image:    model.Image{Name: "docker.io/library/redis:7"},
+		RegImage: redisFull,
+	}
+	alpineJob := model.Job{
+		Provider: "docker",
+		Image:    model.Image{Name: "alpine:3.19"},
+		RegImage: alpine,
+	}
+
+	assert.True(t, q.Add(jobKey(dockerJob.RegImage, dockerJob.Image.Platform, ""), dockerJob))
+	assert.False(t, q.Add(jobKey(fileJob.RegImage, fileJob.Image.Platform, ""), fileJob))
+	assert.False(t, q.Add(jobKey(fileJob.RegImage, fileJob.Image.Platform, ""), fileJob))
+	assert.True(t, q.Add(jobKey(alpineJob.RegImage, alpineJob.Image.Platform, ""), alpineJob))
+
+	jobs := q.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "docker.io/library/redis:7", jobs[0].RegImage.String())
+	assert.Equal(t, []model.JobConsumer{
+		{Provider: "docker", Image: dockerJob.Image},
+		{Provider: "file", Image: fileJob.Image},
+	}, jobs[0].Consumers)
+	assert.Equal(t, "docker.io/library/alpine:3.19", jobs[1].RegImage.String())
+	assert.Len(t, jobs[1].Consumers, 1)
+}
+
+func TestJobKey(t *testing.T) {
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "redis:7"})
+	require.NoError(t, err)
+
+	amd64 := model.ImagePlatform{OS: "linux", Arch: "amd64"}
+	arm64 := model.ImagePlatform{OS: "linux", Arch: "arm64"}
+
+	assert.Equal(t, jobKey(image, amd64, "dockerhub"), jobKey(image, amd64, "dockerhub"))
+	assert.NotEqual(t, jobKey(image, amd64, "dockerhub"), jobKey(image, arm64, "dockerhub"))
+	assert.NotEqual(t, jobKey(image, amd64, "dockerhub"), jobKey(image, amd64, "mirror"))
+}
+
+func TestQueueJobSplitsNotificationSettings(t *testing.T) {
+	redis, err := registry.ParseImage(registry.ParseImageOptions{Name: "redis:7"})
+	require.NoError(t, err)
+
+	di := &Diun{queue: newJobQueue()}
+	di.queueJob(model.Job{Provider: "docker", Image: model.Image{Name: "redis:7"}, RegImage: redis}, "")
+	di.queueJob(model.Job{Provider: "file", Image: model.Image{Name: "redis:7"}, RegImage: redis}, "")
+	di.queueJob(model.Job{Provider: "kubernetes", Image: model.Image{Name: "redis:7", HubLink: "https://redis.io"}, RegImage: redis, HubLinkOverride: "https://redis.io"}, "")
+	di.queueJob(model.Job{Provider: "swarm", Image: model.Image{Name: "redis:7", HubTpl: "https://hub.foo.com/{{ .Path }}"}, RegImage: redis}, "")
+	di.queueJob(model.Job{Provider: "nomad", Image: model.Image{Name: "redis:7"}, RegImage: redis, FirstCheck: true}, "")
+	di.queueJob(model.Job{Provider: "dockerfile", Image: model.Image{Name: "redis:7", RequireSigned: new(true)}, RegImage: redis}, "")
+
+	jobs := di.queue.Jobs()
+	require.Len(t, jobs, 5)
+	assert.Len(t, jobs[0].Consumers, 2)
+	assert.Equal(t, "https://redis.io", jobs[1].HubLinkOverride)
+	assert.Equal(t, "https://hub.foo.com/{{ .Path }}", jobs[2].Image.HubTpl)
+	assert.True(t, jobs[3].FirstCheck)
+	assert.True(t, *jobs[4].Image.RequireSigned)
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/rs/zerolog/log"
+)
+
+// rateLimits tracks the pull budget of registries during a run so remaining
+// checks are deferred to the next run instead of failing once exhausted.
+type rateLimits struct {
+	mu        sync.Mutex
+	threshold int
+	limits    map[string]*registry.RateLimit
+	checked   map[string]bool
+	deferred  map[string]bool
+}
+
+func newRateLimits(threshold int) *rateLimits {
+	return &rateLimits{
+		threshold: threshold,
+		limits:    make(map[string]*registry.RateLimit),
+		checked:   make(map[string]bool),
+		deferred:  make(map[string]bool),
+	}
+}
+
+// deferJob checks if the job has to be deferred because the pull budget of its
+// registry is nearly exhausted.
+func (di *Diun) deferJob(job model.Job) bool {
+	rl := di.limits
+	if rl == nil {
+		return false
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	domain := job.RegImage.Domain
+	if !rl.checked[domain] {
+		rl.checked[domain] = true
+		limit, err := job.Registry.RateLimit(job.RegImage)
+		if err != nil {
+			log.Debug().Err(err).Str("registry", domain).Msg("Cannot retrieve registry rate limit")
+		} else if limit != nil {
+			log.Debug().
+				Str("registry", domain).
+				Int("limit", limit.Limit).
+				Int("remaining", limit.Remaining).
+				Str("window", limit.Window.String()).
+				Msg("Registry rate limit retrieved")
+			rl.limits[domain] = limit
+			di.metrics.RecordRateLimit(domain, limit)
+		}
+	}
+
+	limit, ok := rl.limits[domain]
+	if !ok {
+		return false
+	}
+	if limit.Exhausted(rl.threshold) {
+		if !rl.deferred[domain] {
+			rl.deferred[domain] = true
+			log.Warn().
+				Str("registry", domain).
+				Int("remaining", limit.Remaining).
+				Msg("Registry rate limit nearly exhausted, remaining checks deferred to next run")
+		}
+		return true
+	}
+	return false
+}
+
+// consumeRateLimit decrements the pull budget of a registry after a manifest
+// has been pulled.
+func (di *Diun) consumeRateLimit(domain string) {
+	rl := di.limits
+	if rl == nil {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if limit, ok := rl.limits[domain]; ok && limit.Remaining > 0 {
+		limit.Remaining--
+		di.metrics.RecordRateLimit(domain, limit)
+	}
+}
+
+// exhaustRateLimit marks the pull budget of a registry as exhausted for the
+// rest of the run after it rejected a request.
+func (di *Diun) exhaustRateLimit(domain string) {
+	rl := di.limits
+	if rl == nil {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.checked[domain] = true
+	rl.deferred[domain] = true
+	if limit, ok := rl.limits[domain]; ok {
+		limit.Remaining = 0
+		di.metrics.RecordRateLimit(domain, limit)
+		return
+	}
+	rl.limits[domain] = &registry.RateLimit{}
+}
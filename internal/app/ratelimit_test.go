@@ -0,0 +1,37 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeferJob(t *testing.T) {
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "redis:7"})
+	require.NoError(t, err)
+	job := model.Job{RegImage: image}
+
+	di := &Diun{limits: newRateLimits(2)}
+	di.limits.checked["docker.io"] = true
+	di.limits.limits["docker.io"] = &registry.RateLimit{Limit: 100, Remaining: 4}
+
+	assert.False(t, di.deferJob(job))
+	di.consumeRateLimit("docker.io")
+	assert.False(t, di.deferJob(job))
+	di.consumeRateLimit("docker.io")
+	assert.True(t, di.deferJob(job))
+	assert.Equal(t, 2, di.limits.limits["docker.io"].Remaining)
+}
+
+func TestExhaustRateLimit(t *testing.T) {
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "ghcr.io/crazy-max/diun:latest"})
+	require.NoError(t, err)
+	job := model.Job{RegImage: image}
+
+	di := &Diun{limits: newRateLimits(0)}
+	di.exhaustRateLimit("ghcr.io")
+	assert.True(t, di.deferJob(job))
+}
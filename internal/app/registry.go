@@ -0,0 +1,105 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.podman.io/image/v5/types"
+)
+
+// registryClient creates a registry client for an image with the given
+// registry options
+func (di *Diun) registryClient(reg *model.RegOpt, image registry.Image, img model.Image, sublog zerolog.Logger) (*registry.Client, error) {
+	regUser, err := secret.GetSecret(reg.Username, reg.UsernameFile)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Cannot retrieve username secret for regopts %s", reg.Name)
+	}
+	regPassword, err := secret.GetSecret(reg.Password, reg.PasswordFile)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Cannot retrieve password secret for regopts %s", reg.Name)
+	}
+
+	auth, authSource, err := di.registryCredentials(reg, image.Domain, regUser, regPassword)
+	if err != nil {
+		sublog.Warn().Err(err).Str("regopt", reg.Name).Str("source", authSource).Msg("Cannot retrieve registry credentials")
+	} else if auth != (types.DockerAuthConfig{}) {
+		sublog.Debug().Str("regopt", reg.Name).Str("source", authSource).Msg("Registry credentials retrieved")
+	}
+
+	var retry registry.RetryOptions
+	if reg.Retry != nil {
+		domain := image.Domain
+		retry = registry.RetryOptions{
+			Attempts: reg.Retry.Attempts,
+			Backoff:  *reg.Retry.Backoff,
+			Statuses: reg.Retry.Statuses,
+			OnRetry: func(attempt int, delay time.Duration, err error) {
+				log.Warn().Err(err).Str("registry", domain).Msgf("Registry request failed, retrying in %s (attempt %d/%d)", delay, attempt, reg.Retry.Attempts)
+				di.metrics.RecordRetry(domain)
+			},
+		}
+	}
+
+	return registry.New(registry.Options{
+		Auth:          auth,
+		Timeout:       *reg.Timeout,
+		InsecureTLS:   *reg.InsecureTLS,
+		CertDir:       di.certDirs[reg.Name],
+		Proxy:         reg.Proxy,
+		UserAgent:     di.meta.UserAgent,
+		CompareDigest: *di.cfg.Watch.CompareDigest,
+		ImageOs:       img.Platform.OS,
+		ImageArch:     img.Platform.Arch,
+		ImageVariant:  img.Platform.Variant,
+		Artifacts:     artifactMode(img),
+		ArtifactTypes: img.ArtifactTypes,
+		Limiter:       di.limiters[reg.Name],
+		Retry:         retry,
+		Mirrors:       reg.Mirrors,
+		OnMirrorError: func(mirror string, err error) {
+			sublog.Warn().Err(err).Str("mirror", mirror).Msg("Registry mirror failed, falling back")
+		},
+	})
+}
+
+// artifactMode checks if non-image OCI artifacts are watched for an image
+func artifactMode(img model.Image) bool {
+	return (img.Artifact != nil && *img.Artifact) || len(img.ArtifactTypes) > 0
+}
+
+// remoteManifest retrieves the remote manifest of an image for a platform
+// (os/arch[/variant]) using the registry options matching the image. OCI
+// artifacts are also supported.
+func (di *Diun) remoteManifest(image registry.Image, platform string) (registry.Manifest, error) {
+	sublog := log.With().Str("image", image.String()).Logger()
+
+	reg, err := di.cfg.RegOpts.Select("", image)
+	if err != nil {
+		return registry.Manifest{}, err
+	} else if reg == nil {
+		reg = (&model.RegOpt{}).GetDefaults()
+	}
+
+	img := model.Image{
+		Artifact: new(true),
+	}
+	if parts := strings.SplitN(platform, "/", 3); len(parts) >= 2 {
+		img.Platform.OS, img.Platform.Arch = parts[0], parts[1]
+		if len(parts) == 3 {
+			img.Platform.Variant = parts[2]
+		}
+	}
+
+	client, err := di.registryClient(reg, image, img, sublog)
+	if err != nil {
+		return registry.Manifest{}, errors.Wrap(err, "cannot create registry client")
+	}
+	manifest, _, err := client.Manifest(image, registry.Manifest{})
+	return manifest, err
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"github.com/crazy-max/diun/v4/internal/cosign"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/opencontainers/go-digest"
+	"github.com/rs/zerolog"
+)
+
+// newVerifier creates a cosign verifier from registry options. An additional
+// public key can be trusted for a specific image.
+func newVerifier(cfg *model.RegOptCosign, publicKey string) (*cosign.Verifier, error) {
+	opts := cosign.Options{}
+	if cfg != nil {
+		opts.PublicKeys = cfg.PublicKeys
+		opts.RootCerts = cfg.RootCerts
+		opts.RekorPublicKeys = cfg.RekorPublicKeys
+		opts.Attestations = cfg.Attestations != nil && *cfg.Attestations
+		for _, id := range cfg.Identities {
+			opts.Identities = append(opts.Identities, cosign.Identity{
+				Issuer:        id.Issuer,
+				Subject:       id.Subject,
+				SubjectRegexp: id.SubjectRegexp,
+			})
+		}
+	}
+	if publicKey != "" {
+		opts.PublicKeys = append([]string{publicKey}, opts.PublicKeys...)
+	}
+	return cosign.New(opts)
+}
+
+// jobVerifier returns the cosign verifier of an image if signature
+// verification is configured
+func (di *Diun) jobVerifier(reg *model.RegOpt, image model.Image) (*cosign.Verifier, error) {
+	if image.CosignKey != "" {
+		return newVerifier(reg.Cosign, image.CosignKey)
+	}
+	return di.verifiers[reg.Name], nil
+}
+
+// verifySignature looks up and verifies the cosign signature of a manifest
+func (di *Diun) verifySignature(job model.Job, dgst digest.Digest, sublog zerolog.Logger) *model.NotifSignature {
+	res, err := job.Verifier.Verify(job.Registry, job.RegImage, dgst)
+	if err != nil {
+		sublog.Warn().Err(err).Msg("Cannot verify image signature")
+	}
+	switch {
+	case res.Verified:
+		sublog.Debug().Str("signer", res.Signer).Bool("attested", res.Attested).Msg("Image signature verified")
+	case res.Signed:
+		sublog.Warn().Msg("Image signature cannot be verified")
+	default:
+		sublog.Debug().Msg("Image is not signed")
+	}
+	return &model.NotifSignature{
+		Signed:   res.Signed,
+		Verified: res.Verified,
+		Attested: res.Attested,
+		Signer:   res.Signer,
+	}
+}
+
+// requireSigned checks if a consumer only wants notifications for images
+// with a verified signature
+func requireSigned(job model.Job, consumer model.JobConsumer) bool {
+	if consumer.Image.RequireSigned != nil {
+		return *consumer.Image.RequireSigned
+	}
+	return job.RequireSigned
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crazy-max/diun/v4/internal/cosign"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobVerifier(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "cosign.pub")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	regVerifier, err := newVerifier(&model.RegOptCosign{PublicKeys: []string{keyFile}}, "")
+	require.NoError(t, err)
+	di := &Diun{verifiers: map[string]*cosign.Verifier{"myregistry": regVerifier}}
+
+	verifier, err := di.jobVerifier(&model.RegOpt{Name: "myregistry"}, model.Image{})
+	require.NoError(t, err)
+	assert.Same(t, regVerifier, verifier)
+
+	verifier, err = di.jobVerifier(&model.RegOpt{Name: "other"}, model.Image{})
+	require.NoError(t, err)
+	assert.Nil(t, verifier)
+
+	verifier, err = di.jobVerifier(&model.RegOpt{Name: "other"}, model.Image{CosignKey: keyFile})
+	require.NoError(t, err)
+	assert.NotNil(t, verifier)
+
+	_, err = di.jobVerifier(&model.RegOpt{Name: "other"}, model.Image{CosignKey: filepath.Join(t.TempDir(), "missing.pub")})
+	require.Error(t, err)
+}
+
+func TestRequireSigned(t *testing.T) {
+	assert.False(t, requireSigned(model.Job{}, model.JobConsumer{}))
+	assert.True(t, requireSigned(model.Job{RequireSigned: true}, model.JobConsumer{}))
+	assert.False(t, requireSigned(model.Job{RequireSigned: true}, model.JobConsumer{Image: model.Image{RequireSigned: new(false)}}))
+	assert.True(t, requireSigned(model.Job{}, model.JobConsumer{Image: model.Image{RequireSigned: new(true)}}))
+}
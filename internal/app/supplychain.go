@@ -0,0 +1,54 @@
+package app
+
+import (
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/rs/zerolog"
+)
+
+// supplyChain retrieves the SBOM and provenance of a manifest and diffs its
+// packages with the ones of the previous digest
+func supplyChain(job model.Job, dbManifest, manifest registry.Manifest, sublog zerolog.Logger) *model.NotifSupplyChain {
+	current, err := job.Registry.SupplyChain(job.RegImage, manifest)
+	if err != nil {
+		sublog.Warn().Err(err).Msg("Cannot retrieve SBOM and provenance")
+		return nil
+	}
+	for _, layer := range current.Skipped {
+		sublog.Warn().Str("digest", layer.Digest.String()).Int64("size", layer.Size).Msg("Skipping SBOM or provenance too large")
+	}
+	if current.SBOM == nil && current.Provenance == nil {
+		sublog.Debug().Msg("No SBOM or provenance found")
+		return nil
+	}
+
+	res := &model.NotifSupplyChain{
+		Provenance: current.Provenance,
+	}
+	if current.SBOM == nil {
+		return res
+	}
+	res.SBOMFormat = current.SBOM.Format
+	res.Packages = len(current.SBOM.Packages)
+	if len(dbManifest.Digest) == 0 || dbManifest.Digest == manifest.Digest {
+		return res
+	}
+
+	previous, err := job.Registry.SupplyChain(job.RegImage, dbManifest)
+	if err != nil {
+		sublog.Warn().Err(err).Str("digest", dbManifest.Digest.String()).Msg("Cannot retrieve SBOM of previous digest")
+		return res
+	} else if previous.SBOM == nil {
+		sublog.Debug().Str("digest", dbManifest.Digest.String()).Msg("No SBOM found for previous digest")
+		return res
+	}
+
+	changes := registry.DiffPackages(previous.SBOM.Packages, current.SBOM.Packages)
+	res.Changes = &changes
+	sublog.Debug().
+		Int("added", len(changes.Added)).
+		Int("removed", len(changes.Removed)).
+		Int("updated", len(changes.Updated)).
+		Msg("SBOM packages compared with previous digest")
+	return res
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"os"
 	"path"
 
+	"github.com/crazy-max/diun/v4/internal/httputil"
 	"github.com/crazy-max/diun/v4/internal/model"
 	"github.com/crazy-max/gonfig"
 	"github.com/go-playground/validator/v10"
@@ -76,5 +79,36 @@ func (cfg *Config) validate() error {
 	if cfg.Providers == nil {
 		return errors.New("at least one provider is required")
 	}
-	return validator.New().Struct(cfg)
+	if err := validator.New().Struct(cfg); err != nil {
+		return err
+	}
+	for _, regopt := range cfg.RegOpts {
+		if err := validateRegOptTransport(regopt); err != nil {
+			return errors.Wrapf(err, "invalid transport settings for regopts %s", regopt.Name)
+		}
+	}
+	return nil
+}
+
+// validateRegOptTransport checks that the proxy, CA certificates and client
+// certificate of registry options can be loaded
+func validateRegOptTransport(regopt model.RegOpt) error {
+	if _, err := httputil.NewTransport(regopt.Proxy, false, regopt.TLSCACertFiles); err != nil {
+		return err
+	}
+	for _, file := range regopt.TLSCACertFiles {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return errors.Wrapf(err, "cannot read CA certificate %s", file)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(b) {
+			return errors.Errorf("no certificate found in %s", file)
+		}
+	}
+	if regopt.TLSClientCert != "" {
+		if _, err := tls.LoadX509KeyPair(regopt.TLSClientCert, regopt.TLSClientKey); err != nil {
+			return errors.Wrap(err, "cannot load client certificate")
+		}
+	}
+	return nil
 }
@@ -35,17 +35,45 @@ func TestLoadFile(t *testing.T) {
 			cfg:     "./fixtures/config.err.hc.yml",
 			wantErr: true,
 		},
+		{
+			name:    "Fail on registry credentials along with cloud authentication",
+			cfg:     "./fixtures/config.err.regopts.yml",
+			wantErr: true,
+		},
+		{
+			name:    "Fail on client certificate without key",
+			cfg:     "./fixtures/config.err.regopts.tls.yml",
+			wantErr: true,
+		},
+		{
+			name:    "Fail on CA file without certificate",
+			cfg:     "./fixtures/config.err.regopts.ca.yml",
+			wantErr: true,
+		},
+		{
+			name:    "Fail on cosign identities without root certificates",
+			cfg:     "./fixtures/config.err.regopts.cosign.yml",
+			wantErr: true,
+		},
+		{
+			name:    "Fail on cosign identity without issuer",
+			cfg:     "./fixtures/config.err.regopts.cosign.issuer.yml",
+			wantErr: true,
+		},
 		{
 			name: "Success with healthchecks uuidFile",
 			cfg:  "./fixtures/config.hc.uuidfile.yml",
 			wantData: &Config{
 				Db: (&model.Db{}).GetDefaults(),
 				Watch: &model.Watch{
-					Workers:         10,
-					Jitter:          new(30 * time.Second),
-					FirstCheckNotif: new(false),
-					RunOnStartup:    new(true),
-					CompareDigest:   new(true),
+					Workers:            10,
+					Jitter:             new(30 * time.Second),
+					FirstCheckNotif:    new(false),
+					RunOnStartup:       new(true),
+					CompareDigest:      new(true),
+					RateLimitThreshold: new(10),
+					SupplyChain:        new(false),
+					TagsCacheTTL:       new(time.Duration(0)),
 					Healthchecks: &model.Healthchecks{
 						BaseURL:  "https://hc-ping.com/",
 						UUIDFile: "./fixtures/run_secrets_uuid",
@@ -75,12 +103,20 @@ func TestLoadFile(t *testing.T) {
 					Path: "diun.db",
 				},
 				Watch: &model.Watch{
-					Workers:         100,
-					Schedule:        "*/30 * * * *",
-					Jitter:          new(30 * time.Second),
-					FirstCheckNotif: new(true),
-					RunOnStartup:    new(false),
-					CompareDigest:   new(true),
+					Workers:            100,
+					Schedule:           "*/30 * * * *",
+					Jitter:             new(30 * time.Second),
+					FirstCheckNotif:    new(true),
+					RunOnStartup:       new(false),
+					CompareDigest:      new(true),
+					RateLimitThreshold: new(10),
+					SupplyChain:        new(true),
+					TagsCacheTTL:       new(time.Duration(0)),
+					Links: &model.Links{
+						Forges: []model.LinksForge{
+							{Host: "git.example.com", Type: model.ForgeGitea},
+						},
+					},
 					Healthchecks: &model.Healthchecks{
 						BaseURL: "https://hc-ping.com/",
 						UUID:    "5bf66975-d4c7-4bf5-bcc8-b8d8a82ea278",
@@ -139,6 +175,16 @@ func TestLoadFile(t *testing.T) {
 						TemplateTitle: model.NotifDefaultTemplateTitle,
 						TemplateBody:  model.NotifDefaultTemplateBody,
 					},
+					Kafka: &model.NotifKafka{
+						Brokers:       []string{"kafka1:9092", "kafka2:9092"},
+						Topic:         "diun.{{ .Entry.Provider }}",
+						ClientID:      "diun",
+						SASLMechanism: model.KafkaSASLScramSha512,
+						Username:      "diun",
+						Password:      "secret",
+						TLS:           true,
+						Timeout:       new(10 * time.Second),
+					},
 					Mail: &model.NotifMail{
 						Host:               "localhost",
 						Port:               25,
@@ -176,6 +222,13 @@ for <code>{{ .Entry.Manifest.Platform }}</code> platform.
 						Topic:    "docker/diun",
 						QoS:      0,
 					},
+					Nats: &model.NotifNats{
+						URL:       "nats://localhost:4222",
+						Subject:   "diun.{{ .Entry.Status }}",
+						Token:     "Token123456",
+						JetStream: true,
+						Timeout:   new(10 * time.Second),
+					},
 					Ntfy: &model.NotifNtfy{
 						Endpoint:      "https://ntfy.sh",
 						Topic:         "diun-acce65a0-b777-46f9-9a11-58c67d1579c4",
@@ -247,12 +300,20 @@ for <code>{{ .Entry.Manifest.Platform }}</code> platform.
 				},
 				RegOpts: model.RegOpts{
 					model.RegOpt{
-						Name:        "myregistry",
-						Selector:    model.RegOptSelectorName,
-						Username:    "fii",
-						Password:    "bor",
-						InsecureTLS: new(false),
-						Timeout:     new(5 * time.Second),
+						Name:           "myregistry",
+						Selector:       model.RegOptSelectorName,
+						Username:       "fii",
+						Password:       "bor",
+						InsecureTLS:    new(false),
+						TLSCACertFiles: []string{"./fixtures/ca.pem"},
+						Proxy:          "http://proxy.foo.com:3128",
+						Timeout:        new(5 * time.Second),
+						MaxConcurrency: 2,
+						Retry: &model.RegOptRetry{
+							Attempts: 5,
+							Backoff:  new(2 * time.Second),
+							Statuses: []int{500, 502, 503, 504},
+						},
 					},
 					model.RegOpt{
 						Name:        "docker.io",
@@ -261,6 +322,7 @@ for <code>{{ .Entry.Manifest.Platform }}</code> platform.
 						Password:    "bar",
 						InsecureTLS: new(false),
 						Timeout:     new(time.Duration(0)),
+						Mirrors:     []string{"mirror.foo.com"},
 					},
 					model.RegOpt{ //nolint:gosec // fixture paths are test data.
 						Name:         "docker.io/crazymax",
@@ -269,6 +331,11 @@ for <code>{{ .Entry.Manifest.Platform }}</code> platform.
 						PasswordFile: "./fixtures/run_secrets_password",
 						InsecureTLS:  new(false),
 						Timeout:      new(time.Duration(0)),
+						Cosign: &model.RegOptCosign{
+							PublicKeys:    []string{"./fixtures/run_secrets_password"},
+							Attestations:  new(false),
+							RequireSigned: new(true),
+						},
 					},
 				},
 				Providers: &model.Providers{
@@ -328,11 +395,14 @@ func TestLoadEnv(t *testing.T) {
 			expected: &Config{
 				Db: (&model.Db{}).GetDefaults(),
 				Watch: &model.Watch{
-					Workers:         10,
-					Jitter:          new(30 * time.Second),
-					FirstCheckNotif: new(false),
-					RunOnStartup:    new(true),
-					CompareDigest:   new(true),
+					Workers:            10,
+					Jitter:             new(30 * time.Second),
+					FirstCheckNotif:    new(false),
+					RunOnStartup:       new(true),
+					CompareDigest:      new(true),
+					RateLimitThreshold: new(10),
+					SupplyChain:        new(false),
+					TagsCacheTTL:       new(time.Duration(0)),
 					Healthchecks: &model.Healthchecks{
 						UUIDFile: "./fixtures/run_secrets_uuid",
 					},
@@ -484,6 +554,43 @@ func TestLoadEnv(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			desc: "docker provider and regopts ecr",
+			environ: []string{
+				"DIUN_REGOPTS_0_NAME=123456789012.dkr.ecr.eu-west-1.amazonaws.com",
+				"DIUN_REGOPTS_0_SELECTOR=image",
+				"DIUN_REGOPTS_0_ECR_ROLEARN=arn:aws:iam::123456789012:role/diun",
+				"DIUN_REGOPTS_0_TIMEOUT=10s",
+				"DIUN_REGOPTS_0_ECR_WEBIDENTITYTOKENFILE=./fixtures/run_secrets_password",
+				"DIUN_PROVIDERS_DOCKER=true",
+			},
+			expected: &Config{
+				Db:       (&model.Db{}).GetDefaults(),
+				Watch:    (&model.Watch{}).GetDefaults(),
+				Defaults: (&model.Defaults{}).GetDefaults(),
+				Metrics:  (&model.Metrics{}).GetDefaults(),
+				RegOpts: model.RegOpts{
+					model.RegOpt{
+						Name:        "123456789012.dkr.ecr.eu-west-1.amazonaws.com",
+						Selector:    model.RegOptSelectorImage,
+						InsecureTLS: new(false),
+						Timeout:     new(10 * time.Second),
+						ECR: &model.RegOptECR{
+							RoleARN:              "arn:aws:iam::123456789012:role/diun",
+							WebIdentityTokenFile: "./fixtures/run_secrets_password",
+						},
+					},
+				},
+				Providers: &model.Providers{
+					Docker: &model.PrdDocker{
+						TLSVerify:      new(true),
+						WatchByDefault: new(false),
+						WatchStopped:   new(false),
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			desc: "swarm provider and notif telegram",
 			environ: []string{
@@ -0,0 +1,248 @@
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"regexp"
+
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// Options holds cosign verification options
+type Options struct {
+	PublicKeys      []string
+	Identities      []Identity
+	RootCerts       []string
+	RekorPublicKeys []string
+	Attestations    bool
+}
+
+// Identity holds a keyless signing identity
+type Identity struct {
+	Issuer        string
+	Subject       string
+	SubjectRegexp string
+}
+
+// Result holds the result of a verification
+type Result struct {
+	Signed   bool
+	Verified bool
+	Attested bool
+	Signer   string
+}
+
+// Verifier verifies cosign signatures and attestations of manifests
+type Verifier struct {
+	keys         []crypto.PublicKey
+	identities   []identity
+	roots        *x509.CertPool
+	rekorKeys    []crypto.PublicKey
+	attestations bool
+}
+
+type identity struct {
+	issuer        string
+	subject       string
+	subjectRegexp *regexp.Regexp
+}
+
+// New creates a new cosign verifier
+func New(opts Options) (*Verifier, error) {
+	v := &Verifier{
+		attestations: opts.Attestations,
+	}
+
+	for _, file := range opts.PublicKeys {
+		key, err := loadPublicKey(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load public key %s", file)
+		}
+		v.keys = append(v.keys, key)
+	}
+
+	for _, id := range opts.Identities {
+		if id.Issuer == "" {
+			return nil, errors.New("keyless identity requires an issuer")
+		}
+		if id.Subject == "" && id.SubjectRegexp == "" {
+			return nil, errors.New("keyless identity requires a subject or subject regexp")
+		}
+		i := identity{
+			issuer:  id.Issuer,
+			subject: id.Subject,
+		}
+		if id.SubjectRegexp != "" {
+			re, err := regexp.Compile(id.SubjectRegexp)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid subject regexp %q", id.SubjectRegexp)
+			}
+			i.subjectRegexp = re
+		}
+		v.identities = append(v.identities, i)
+	}
+
+	if len(v.identities) > 0 {
+		if len(opts.RootCerts) == 0 {
+			return nil, errors.New("root certificates are required for keyless verification")
+		}
+		v.roots = x509.NewCertPool()
+		for _, file := range opts.RootCerts {
+			b, err := os.ReadFile(file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot read root certificate %s", file)
+			}
+			if !v.roots.AppendCertsFromPEM(b) {
+				return nil, errors.Errorf("no certificate found in %s", file)
+			}
+		}
+	}
+
+	for _, file := range opts.RekorPublicKeys {
+		key, err := loadPublicKey(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load Rekor public key %s", file)
+		}
+		v.rekorKeys = append(v.rekorKeys, key)
+	}
+
+	if len(v.identities) > 0 && len(v.rekorKeys) == 0 {
+		return nil, errors.New("Rekor public keys are required for keyless verification")
+	}
+
+	if len(v.keys) == 0 && len(v.identities) == 0 {
+		return nil, errors.New("at least one public key or keyless identity is required")
+	}
+
+	return v, nil
+}
+
+// Verify retrieves the signatures and attestations of a manifest and
+// verifies them
+func (v *Verifier) Verify(client *registry.Client, image registry.Image, dgst digest.Digest) (Result, error) {
+	sigs, err := client.Signatures(image, dgst)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "cannot retrieve signatures")
+	}
+	res := v.VerifySignatures(sigs, dgst)
+	if !v.attestations || !res.Verified {
+		return res, nil
+	}
+
+	atts, err := client.Attestations(image, dgst)
+	if err != nil {
+		return res, errors.Wrap(err, "cannot retrieve attestations")
+	}
+	res.Attested = v.VerifyAttestations(atts, dgst)
+
+	return res, nil
+}
+
+// VerifySignatures verifies signatures of a manifest
+func (v *Verifier) VerifySignatures(sigs []registry.Signature, dgst digest.Digest) Result {
+	res := Result{
+		Signed: len(sigs) > 0,
+	}
+	for _, sig := range sigs {
+		if sig.MediaType != registry.CosignSimpleSigningType {
+			continue
+		}
+		if err := checkPayload(sig.Payload, dgst); err != nil {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(sig.Signature)
+		if err != nil {
+			continue
+		}
+		if signer, ok := v.verify(sig, sig.Payload, sig.Payload, signature); ok {
+			res.Verified = true
+			res.Signer = signer
+			break
+		}
+	}
+	return res
+}
+
+// verify verifies a signature of data with the configured public keys or
+// the certificate of the signature for keyless signing. The artifact is the
+// content recorded in the transparency log for keyless signatures. It
+// returns the signer identity if any.
+func (v *Verifier) verify(sig registry.Signature, artifact []byte, data []byte, signature []byte) (string, bool) {
+	if sig.Certificate != "" && len(v.identities) > 0 {
+		cert, err := v.verifyCertificate(sig, artifact, signature)
+		if err != nil {
+			return "", false
+		}
+		if verifySignature(cert.PublicKey, data, signature) != nil {
+			return "", false
+		}
+		return v.matchIdentity(cert)
+	}
+	for _, key := range v.keys {
+		if verifySignature(key, data, signature) == nil {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// checkPayload checks the simple signing payload references the manifest
+func checkPayload(payload []byte, dgst digest.Digest) error {
+	var p struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+	if p.Critical.Image.DockerManifestDigest != dgst.String() {
+		return errors.Errorf("payload references %s", p.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+func loadPublicKey(file string) (crypto.PublicKey, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func verifySignature(key crypto.PublicKey, data []byte, signature []byte) error {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(k, digest[:], signature) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, data, signature) {
+			return errors.New("invalid Ed25519 signature")
+		}
+		return nil
+	default:
+		return errors.Errorf("unsupported public key type %T", key)
+	}
+}
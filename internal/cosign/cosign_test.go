@@ -0,0 +1,323 @@
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDigest = digest.FromString("manifest")
+
+func TestNew(t *testing.T) {
+	_, err := New(Options{})
+	require.EqualError(t, err, "at least one public key or keyless identity is required")
+
+	_, err = New(Options{Identities: []Identity{{Subject: "foo@example.com"}}})
+	require.EqualError(t, err, "keyless identity requires an issuer")
+
+	_, err = New(Options{Identities: []Identity{{Issuer: "https://accounts.google.com", Subject: "foo@example.com"}}})
+	require.EqualError(t, err, "root certificates are required for keyless verification")
+
+	_, err = New(Options{Identities: []Identity{{Issuer: "https://accounts.google.com", Subject: "foo@example.com"}}, RootCerts: []string{writeSelfSignedCert(t)}})
+	require.EqualError(t, err, "Rekor public keys are required for keyless verification")
+
+	_, err = New(Options{Identities: []Identity{{Issuer: "https://accounts.google.com"}}})
+	require.EqualError(t, err, "keyless identity requires a subject or subject regexp")
+
+	_, err = New(Options{PublicKeys: []string{filepath.Join(t.TempDir(), "missing.pub")}})
+	require.Error(t, err)
+}
+
+func TestVerifySignaturesPublicKey(t *testing.T) {
+	key := newTestKey(t)
+	v, err := New(Options{PublicKeys: []string{writePublicKey(t, key)}})
+	require.NoError(t, err)
+
+	payload := simpleSigningPayload(testDigest)
+	otherPayload := simpleSigningPayload(digest.FromString("other"))
+
+	assert.Equal(t, Result{}, v.VerifySignatures(nil, testDigest))
+	assert.Equal(t, Result{Signed: true, Verified: true}, v.VerifySignatures([]registry.Signature{{
+		MediaType: registry.CosignSimpleSigningType,
+		Payload:   payload,
+		Signature: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	}}, testDigest))
+	assert.Equal(t, Result{Signed: true}, v.VerifySignatures([]registry.Signature{{
+		MediaType: registry.CosignSimpleSigningType,
+		Payload:   payload,
+		Signature: base64.StdEncoding.EncodeToString(sign(t, newTestKey(t), payload)),
+	}}, testDigest), "signed by another key")
+	assert.Equal(t, Result{Signed: true}, v.VerifySignatures([]registry.Signature{{
+		MediaType: registry.CosignSimpleSigningType,
+		Payload:   otherPayload,
+		Signature: base64.StdEncoding.EncodeToString(sign(t, key, otherPayload)),
+	}}, testDigest), "signature of another manifest")
+}
+
+func TestVerifySignaturesKeyless(t *testing.T) {
+	now := time.Now()
+	signedAt := now.Add(-time.Hour)
+
+	rootKey := newTestKey(t)
+	rootTpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sigstore"},
+		NotBefore:             now.Add(-24 * time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTpl, rootTpl, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	root, err := x509.ParseCertificate(rootDER)
+	require.NoError(t, err)
+	rootFile := filepath.Join(t.TempDir(), "fulcio.pem")
+	require.NoError(t, os.WriteFile(rootFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER}), 0o600))
+
+	issuer, err := asn1.Marshal("https://token.actions.githubusercontent.com")
+	require.NoError(t, err)
+	workflow, err := url.Parse("https://github.com/crazy-max/diun/.github/workflows/release.yml@refs/tags/v4.0.0")
+	require.NoError(t, err)
+
+	// short-lived certificate already expired at verification time
+	leafKey := newTestKey(t)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       signedAt.Add(-5 * time.Minute),
+		NotAfter:        signedAt.Add(5 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{workflow},
+		ExtraExtensions: []pkix.Extension{{Id: oidIssuerV2, Value: issuer}},
+	}, root, &leafKey.PublicKey, rootKey)
+	require.NoError(t, err)
+
+	payload := simpleSigningPayload(testDigest)
+	signature := sign(t, leafKey, payload)
+	leafPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+
+	rekorKey := newTestKey(t)
+	sig := registry.Signature{
+		MediaType:   registry.CosignSimpleSigningType,
+		Payload:     payload,
+		Signature:   base64.StdEncoding.EncodeToString(signature),
+		Certificate: string(leafPEM),
+		Bundle:      newBundle(t, rekorKey, signedAt, payload, signature, leafPEM),
+	}
+	otherSignature := newBundle(t, rekorKey, signedAt, payload, sign(t, leafKey, payload), leafPEM)
+	otherArtifact := newBundle(t, rekorKey, signedAt, simpleSigningPayload(digest.FromString("other")), signature, leafPEM)
+
+	cases := []struct {
+		name     string
+		opts     Options
+		bundle   *string
+		expected Result
+	}{
+		{
+			name: "identity",
+			opts: Options{
+				Identities: []Identity{{
+					Issuer:        "https://token.actions.githubusercontent.com",
+					SubjectRegexp: `^https://github\.com/crazy-max/diun/`,
+				}},
+				RootCerts:       []string{rootFile},
+				RekorPublicKeys: []string{writePublicKey(t, rekorKey)},
+			},
+			expected: Result{Signed: true, Verified: true, Signer: workflow.String()},
+		},
+		{
+			name: "issuer mismatch",
+			opts: Options{
+				Identities:      []Identity{{Issuer: "https://accounts.google.com", Subject: workflow.String()}},
+				RootCerts:       []string{rootFile},
+				RekorPublicKeys: []string{writePublicKey(t, rekorKey)},
+			},
+			expected: Result{Signed: true},
+		},
+		{
+			name: "untrusted root",
+			opts: Options{
+				Identities:      []Identity{{Issuer: "https://token.actions.githubusercontent.com", Subject: workflow.String()}},
+				RootCerts:       []string{writeSelfSignedCert(t)},
+				RekorPublicKeys: []string{writePublicKey(t, rekorKey)},
+			},
+			expected: Result{Signed: true},
+		},
+		{
+			name: "invalid signed entry timestamp",
+			opts: Options{
+				Identities:      []Identity{{Issuer: "https://token.actions.githubusercontent.com", Subject: workflow.String()}},
+				RootCerts:       []string{rootFile},
+				RekorPublicKeys: []string{writePublicKey(t, newTestKey(t))},
+			},
+			expected: Result{Signed: true},
+		},
+		{
+			name: "signature not recorded",
+			opts: Options{
+				Identities:      []Identity{{Issuer: "https://token.actions.githubusercontent.com", Subject: workflow.String()}},
+				RootCerts:       []string{rootFile},
+				RekorPublicKeys: []string{writePublicKey(t, rekorKey)},
+			},
+			bundle:   &otherSignature,
+			expected: Result{Signed: true},
+		},
+		{
+			name: "artifact not recorded",
+			opts: Options{
+				Identities:      []Identity{{Issuer: "https://token.actions.githubusercontent.com", Subject: workflow.String()}},
+				RootCerts:       []string{rootFile},
+				RekorPublicKeys: []string{writePublicKey(t, rekorKey)},
+			},
+			bundle:   &otherArtifact,
+			expected: Result{Signed: true},
+		},
+		{
+			name: "no bundle",
+			opts: Options{
+				Identities:      []Identity{{Issuer: "https://token.actions.githubusercontent.com", Subject: workflow.String()}},
+				RootCerts:       []string{rootFile},
+				RekorPublicKeys: []string{writePublicKey(t, rekorKey)},
+			},
+			bundle:   new(""),
+			expected: Result{Signed: true},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(tt.opts)
+			require.NoError(t, err)
+			s := sig
+			if tt.bundle != nil {
+				s.Bundle = *tt.bundle
+			}
+			assert.Equal(t, tt.expected, v.VerifySignatures([]registry.Signature{s}, testDigest))
+		})
+	}
+}
+
+func TestVerifyAttestations(t *testing.T) {
+	key := newTestKey(t)
+	v, err := New(Options{PublicKeys: []string{writePublicKey(t, key)}, Attestations: true})
+	require.NoError(t, err)
+
+	envelope := func(subject digest.Digest) registry.Signature {
+		statement := fmt.Appendf(nil, `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v0.2","subject":[{"name":"registry.test/acme/diun","digest":{"sha256":%q}}],"predicate":{}}`, subject.Encoded())
+		env, err := json.Marshal(map[string]any{
+			"payloadType": "application/vnd.in-toto+json",
+			"payload":     base64.StdEncoding.EncodeToString(statement),
+			"signatures": []map[string]string{{
+				"sig": base64.StdEncoding.EncodeToString(sign(t, key, preAuthEncoding("application/vnd.in-toto+json", statement))),
+			}},
+		})
+		require.NoError(t, err)
+		return registry.Signature{MediaType: registry.DSSEEnvelopeType, Payload: env}
+	}
+
+	assert.True(t, v.VerifyAttestations([]registry.Signature{envelope(testDigest)}, testDigest))
+	assert.False(t, v.VerifyAttestations([]registry.Signature{envelope(digest.FromString("other"))}, testDigest))
+	assert.False(t, v.VerifyAttestations(nil, testDigest))
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func writePublicKey(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "cosign.pub")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+	return file
+}
+
+func writeSelfSignedCert(t *testing.T) string {
+	t.Helper()
+	key := newTestKey(t)
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "untrusted"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "root.pem")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return file
+}
+
+// newBundle returns a transparency log bundle recording a hashedrekord entry
+// of a keyless signature
+func newBundle(t *testing.T, rekorKey *ecdsa.PrivateKey, integratedTime time.Time, artifact []byte, signature []byte, certPEM []byte) string {
+	t.Helper()
+	hash := sha256.Sum256(artifact)
+	body, err := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"data": map[string]any{
+				"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(hash[:])},
+			},
+			"signature": map[string]any{
+				"content":   base64.StdEncoding.EncodeToString(signature),
+				"publicKey": map[string]string{"content": base64.StdEncoding.EncodeToString(certPEM)},
+			},
+		},
+	})
+	require.NoError(t, err)
+	entry := rekorPayload{
+		Body:           base64.StdEncoding.EncodeToString(body),
+		IntegratedTime: integratedTime.Unix(),
+		LogID:          "c0d23d6ad406973f9559f3ba2d1ca01f84147d8ffc5b8445c224f98b9591801d",
+		LogIndex:       42,
+	}
+	entryJSON, err := json.Marshal(entry)
+	require.NoError(t, err)
+	bundle, err := json.Marshal(rekorBundle{
+		SignedEntryTimestamp: sign(t, rekorKey, entryJSON),
+		Payload:              entry,
+	})
+	require.NoError(t, err)
+	return string(bundle)
+}
+
+func simpleSigningPayload(dgst digest.Digest) []byte {
+	return fmt.Appendf(nil, `{"critical":{"identity":{"docker-reference":"registry.test/acme/diun"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, dgst)
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, data []byte) []byte {
+	t.Helper()
+	sum := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, sum[:])
+	require.NoError(t, err)
+	return sig
+}
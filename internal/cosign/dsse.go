@@ -0,0 +1,72 @@
+package cosign
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/opencontainers/go-digest"
+)
+
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+	Signatures  []struct {
+		Sig string `json:"sig"`
+	} `json:"signatures"`
+}
+
+type inTotoStatement struct {
+	Subject []struct {
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+}
+
+// VerifyAttestations checks if at least one attestation of a manifest is
+// signed by a trusted key or identity
+func (v *Verifier) VerifyAttestations(atts []registry.Signature, dgst digest.Digest) bool {
+	for _, att := range atts {
+		if att.MediaType != registry.DSSEEnvelopeType {
+			continue
+		}
+		var env dsseEnvelope
+		if err := json.Unmarshal(att.Payload, &env); err != nil {
+			continue
+		}
+		payload, err := base64.StdEncoding.DecodeString(env.Payload)
+		if err != nil || !statementSubject(payload, dgst) {
+			continue
+		}
+		pae := preAuthEncoding(env.PayloadType, payload)
+		for _, s := range env.Signatures {
+			signature, err := base64.StdEncoding.DecodeString(s.Sig)
+			if err != nil {
+				continue
+			}
+			if _, ok := v.verify(att, payload, pae, signature); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// statementSubject checks the in-toto statement has the manifest as subject
+func statementSubject(payload []byte, dgst digest.Digest) bool {
+	var statement inTotoStatement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return false
+	}
+	for _, subject := range statement.Subject {
+		if subject.Digest[dgst.Algorithm().String()] == dgst.Encoded() {
+			return true
+		}
+	}
+	return false
+}
+
+// preAuthEncoding returns the DSSE pre-authentication encoding of a payload
+func preAuthEncoding(payloadType string, payload []byte) []byte {
+	return fmt.Appendf(nil, "DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload)
+}
@@ -0,0 +1,269 @@
+package cosign
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"time"
+
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/pkg/errors"
+)
+
+var (
+	// Fulcio OIDC issuer certificate extensions
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// rekorBundle holds the transparency log entry of a keyless signature
+type rekorBundle struct {
+	SignedEntryTimestamp []byte       `json:"SignedEntryTimestamp"`
+	Payload              rekorPayload `json:"Payload"`
+}
+
+// rekorPayload fields are declared in canonical JSON order
+type rekorPayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// rekorEntry holds the fields of the hashedrekord, intoto and dsse
+// transparency log entry kinds needed to match a signature
+type rekorEntry struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		// hashedrekord
+		Data struct {
+			Hash rekorHash `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   string `json:"content"`
+			PublicKey struct {
+				Content string `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+		// intoto
+		Content struct {
+			Envelope struct {
+				Signatures []struct {
+					Sig       string `json:"sig"`
+					PublicKey string `json:"publicKey"`
+				} `json:"signatures"`
+			} `json:"envelope"`
+			PayloadHash rekorHash `json:"payloadHash"`
+		} `json:"content"`
+		// dsse
+		Signatures []struct {
+			Signature string `json:"signature"`
+			Verifier  string `json:"verifier"`
+		} `json:"signatures"`
+		PayloadHash rekorHash `json:"payloadHash"`
+	} `json:"spec"`
+}
+
+type rekorHash struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+// verifyCertificate verifies the signing certificate chains to the root
+// certificates at the time the signature was recorded in the transparency
+// log.
+func (v *Verifier) verifyCertificate(sig registry.Signature, artifact []byte, signature []byte) (*x509.Certificate, error) {
+	cert, err := parseCertificate([]byte(sig.Certificate))
+	if err != nil {
+		return nil, err
+	}
+
+	intermediates := x509.NewCertPool()
+	for rest := []byte(sig.Chain); ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if c, err := x509.ParseCertificate(block.Bytes); err == nil {
+			intermediates.AddCert(c)
+		}
+	}
+
+	signedAt, err := v.verifyBundle(sig.Bundle, cert, artifact, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   signedAt,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return nil, errors.Wrap(err, "cannot verify certificate")
+	}
+
+	return cert, nil
+}
+
+// verifyBundle checks the signed entry timestamp of the transparency log
+// bundle with the Rekor public keys and that the recorded entry matches the
+// artifact, signature and certificate being verified. It returns the time
+// the signature was recorded in the transparency log.
+func (v *Verifier) verifyBundle(raw string, cert *x509.Certificate, artifact []byte, signature []byte) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, errors.New("no transparency log bundle")
+	}
+	if len(v.rekorKeys) == 0 {
+		return time.Time{}, errors.New("no Rekor public key configured")
+	}
+	var bundle rekorBundle
+	if err := json.Unmarshal([]byte(raw), &bundle); err != nil {
+		return time.Time{}, errors.Wrap(err, "cannot decode transparency log bundle")
+	}
+
+	payload, err := json.Marshal(bundle.Payload)
+	if err != nil {
+		return time.Time{}, err
+	}
+	verified := false
+	for _, key := range v.rekorKeys {
+		if verifySignature(key, payload, bundle.SignedEntryTimestamp) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return time.Time{}, errors.New("invalid signed entry timestamp")
+	}
+
+	body, err := base64.StdEncoding.DecodeString(bundle.Payload.Body)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "cannot decode transparency log entry")
+	}
+	var entry rekorEntry
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return time.Time{}, errors.Wrap(err, "cannot decode transparency log entry")
+	}
+	if err := entry.match(cert, artifact, signature); err != nil {
+		return time.Time{}, errors.Wrap(err, "transparency log entry mismatch")
+	}
+
+	return time.Unix(bundle.Payload.IntegratedTime, 0), nil
+}
+
+// match checks the entry records the hash of the artifact, the signature
+// and the certificate
+func (e rekorEntry) match(cert *x509.Certificate, artifact []byte, signature []byte) error {
+	switch e.Kind {
+	case "hashedrekord":
+		if err := e.Spec.Data.Hash.match(artifact); err != nil {
+			return err
+		}
+		return matchSignature(e.Spec.Signature.Content, e.Spec.Signature.PublicKey.Content, cert, signature)
+	case "intoto":
+		if err := e.Spec.Content.PayloadHash.match(artifact); err != nil {
+			return err
+		}
+		for _, s := range e.Spec.Content.Envelope.Signatures {
+			// signatures of intoto entries are base64 encoded twice
+			if sig, err := base64.StdEncoding.DecodeString(s.Sig); err == nil && matchSignature(string(sig), s.PublicKey, cert, signature) == nil {
+				return nil
+			}
+		}
+		return errors.New("signature not recorded")
+	case "dsse":
+		if err := e.Spec.PayloadHash.match(artifact); err != nil {
+			return err
+		}
+		for _, s := range e.Spec.Signatures {
+			if matchSignature(s.Signature, s.Verifier, cert, signature) == nil {
+				return nil
+			}
+		}
+		return errors.New("signature not recorded")
+	default:
+		return errors.Errorf("unsupported entry kind %q", e.Kind)
+	}
+}
+
+func (h rekorHash) match(artifact []byte) error {
+	if h.Algorithm != "sha256" {
+		return errors.Errorf("unsupported hash algorithm %q", h.Algorithm)
+	}
+	sum := sha256.Sum256(artifact)
+	if h.Value != hex.EncodeToString(sum[:]) {
+		return errors.New("artifact hash mismatch")
+	}
+	return nil
+}
+
+// matchSignature checks a base64 encoded signature and PEM certificate of
+// an entry are the ones being verified
+func matchSignature(sig string, publicKey string, cert *x509.Certificate, signature []byte) error {
+	if sig != base64.StdEncoding.EncodeToString(signature) {
+		return errors.New("signature mismatch")
+	}
+	pemCert, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return errors.Wrap(err, "cannot decode certificate")
+	}
+	recorded, err := parseCertificate(pemCert)
+	if err != nil {
+		return err
+	}
+	if !recorded.Equal(cert) {
+		return errors.New("certificate mismatch")
+	}
+	return nil
+}
+
+// matchIdentity returns the certificate identity if it matches one of the
+// configured keyless identities
+func (v *Verifier) matchIdentity(cert *x509.Certificate) (string, bool) {
+	issuer := certificateIssuer(cert)
+	var subjects []string
+	subjects = append(subjects, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		subjects = append(subjects, uri.String())
+	}
+
+	for _, id := range v.identities {
+		if id.issuer != issuer {
+			continue
+		}
+		for _, subject := range subjects {
+			if (id.subject != "" && id.subject == subject) || (id.subjectRegexp != nil && id.subjectRegexp.MatchString(subject)) {
+				return subject, true
+			}
+		}
+	}
+	return "", false
+}
+
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidIssuerV2):
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				return issuer
+			}
+		case ext.Id.Equal(oidIssuerV1):
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+func parseCertificate(b []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
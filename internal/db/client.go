@@ -21,6 +21,8 @@ const (
 	dbVersion      = 2
 	bucketMetadata = "metadata"
 	bucketManifest = "manifest"
+	bucketHold     = "hold"
+	bucketTags     = "tags"
 )
 
 // New creates new db instance
@@ -46,6 +48,20 @@ func New(cfg model.Db) (*Client, error) {
 		return nil, err
 	}
 
+	if err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucketHold))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucketTags))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
 	if err = db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(bucketManifest))
 		stats := b.Stats()
@@ -16,6 +16,8 @@ func TestNewInitializesBucketsAndMetadata(t *testing.T) {
 	require.NoError(t, client.View(func(tx *bolt.Tx) error {
 		assert.NotNil(t, tx.Bucket([]byte(bucketMetadata)))
 		assert.NotNil(t, tx.Bucket([]byte(bucketManifest)))
+		assert.NotNil(t, tx.Bucket([]byte(bucketHold)))
+		assert.NotNil(t, tx.Bucket([]byte(bucketTags)))
 		return nil
 	}))
 }
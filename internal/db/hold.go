@@ -0,0 +1,76 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Hold represents an image update held back until it reaches the minimum
+// age of the image, along with the manifest last reported to the consumers
+// of this minimum age
+type Hold struct {
+	Digest   digest.Digest     `json:"digest,omitempty"`
+	Since    time.Time         `json:"since,omitzero"`
+	Manifest registry.Manifest `json:"manifest,omitzero"`
+}
+
+// GetHold returns the update held back for a Docker image and a minimum age.
+// An empty hold is returned if none.
+func (c *Client) GetHold(image registry.Image, minAge time.Duration) (Hold, error) {
+	var hold Hold
+
+	err := c.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucketHold))
+		if entryBytes := b.Get(holdKey(image.String(), minAge)); entryBytes != nil {
+			return json.Unmarshal(entryBytes, &hold)
+		}
+		return nil
+	})
+
+	return hold, err
+}
+
+// PutHold add an update held back for a Docker image and a minimum age in db
+func (c *Client) PutHold(image registry.Image, minAge time.Duration, hold Hold) error {
+	entryBytes, _ := json.Marshal(hold)
+	return c.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucketHold))
+		return b.Put(holdKey(image.String(), minAge), entryBytes)
+	})
+}
+
+// DeleteHold deletes the update held back for a Docker image and a minimum
+// age
+func (c *Client) DeleteHold(image registry.Image, minAge time.Duration) error {
+	return c.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucketHold))
+		return b.Delete(holdKey(image.String(), minAge))
+	})
+}
+
+// holdKey returns the key of the held update of an image for a minimum age
+func holdKey(image string, minAge time.Duration) []byte {
+	return fmt.Appendf(nil, "%s|%s", image, minAge)
+}
+
+// deleteHolds deletes the held updates of an image for all minimum ages
+func deleteHolds(b *bolt.Bucket, image []byte) error {
+	prefix := append(bytes.Clone(image), '|')
+	keys := [][]byte{image}
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, bytes.Clone(k))
+	}
+	for _, key := range keys {
+		if err := b.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
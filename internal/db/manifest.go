@@ -69,18 +69,14 @@ func (c *Client) PutManifest(image registry.Image, manifest registry.Manifest) e
 	})
 }
 
-// DeleteManifest deletes a Docker image manifest
+// DeleteManifest deletes a Docker image manifest and its held updates
 func (c *Client) DeleteManifest(manifest registry.Manifest) error {
 	return c.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(bucketManifest))
-		keys := [][]byte{
-			[]byte(fmt.Sprintf("%s:%s", manifest.Name, manifest.Tag)),
-		}
-		if manifest.Digest != "" {
-			keys = append(keys, []byte(fmt.Sprintf("%s:%s@%s", manifest.Name, manifest.Tag, manifest.Digest)))
-		}
-		for _, key := range keys {
-			if err := b.Delete(key); err != nil {
+		for _, key := range manifestKeys(manifest) {
+			if err := tx.Bucket([]byte(bucketManifest)).Delete(key); err != nil {
+				return err
+			}
+			if err := deleteHolds(tx.Bucket([]byte(bucketHold)), key); err != nil {
 				return err
 			}
 		}
@@ -88,6 +84,17 @@ func (c *Client) DeleteManifest(manifest registry.Manifest) error {
 	})
 }
 
+// manifestKeys returns the keys under which a manifest may be stored
+func manifestKeys(manifest registry.Manifest) [][]byte {
+	keys := [][]byte{
+		[]byte(fmt.Sprintf("%s:%s", manifest.Name, manifest.Tag)),
+	}
+	if manifest.Digest != "" {
+		keys = append(keys, []byte(fmt.Sprintf("%s:%s@%s", manifest.Name, manifest.Tag, manifest.Digest)))
+	}
+	return keys
+}
+
 // ListImage return a list of Docker images with their linked manifests
 func (c *Client) ListImage() (map[string][]registry.Manifest, error) {
 	images := make(map[string][]registry.Manifest)
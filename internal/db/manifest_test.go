@@ -78,3 +78,28 @@ func testManifest(name, tag string) registry.Manifest {
 		Platform: "linux/amd64",
 	}
 }
+
+func TestDeleteManifestRemovesHold(t *testing.T) {
+	client := newTestClient(t)
+	image := parseTestImage(t, "alpine:3.20")
+	manifest := testManifest("docker.io/library/alpine", "3.20")
+	hold := Hold{
+		Digest: digest.FromString("alpine:3.21"),
+		Since:  time.Date(2026, 5, 24, 0, 0, 0, 0, time.UTC),
+	}
+
+	require.NoError(t, client.PutManifest(image, manifest))
+	require.NoError(t, client.PutHold(image, 24*time.Hour, hold))
+	require.NoError(t, client.PutHold(image, 7*24*time.Hour, Hold{Manifest: manifest}))
+	stored, err := client.GetHold(image, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, hold, stored)
+
+	require.NoError(t, client.DeleteManifest(manifest))
+	stored, err = client.GetHold(image, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, Hold{}, stored)
+	stored, err = client.GetHold(image, 7*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, Hold{}, stored)
+}
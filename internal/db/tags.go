@@ -0,0 +1,41 @@
+package db
+
+import (
+	"encoding/json"
+
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	bolt "go.etcd.io/bbolt"
+)
+
+// GetTags returns the cached tags listing of a Docker repository for a
+// registry options entry. An empty cache is returned if none.
+func (c *Client) GetTags(regopt string, image registry.Image) (*registry.TagsCache, error) {
+	cache := &registry.TagsCache{}
+
+	err := c.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucketTags))
+		if entryBytes := b.Get([]byte(tagsKey(regopt, image))); entryBytes != nil {
+			return json.Unmarshal(entryBytes, cache)
+		}
+		return nil
+	})
+
+	return cache, err
+}
+
+// PutTags add the tags listing of a Docker repository for a registry options
+// entry in db
+func (c *Client) PutTags(regopt string, image registry.Image, cache *registry.TagsCache) error {
+	entryBytes, _ := json.Marshal(cache)
+	return c.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucketTags))
+		return b.Put([]byte(tagsKey(regopt, image)), entryBytes)
+	})
+}
+
+// tagsKey returns the key of a tags listing. Registry options may use other
+// credentials or mirrors, so their listings are not shared. The endpoint the
+// listing has been fetched from is recorded in the cache itself.
+func tagsKey(regopt string, image registry.Image) string {
+	return regopt + "|" + image.Domain + "/" + image.Path
+}
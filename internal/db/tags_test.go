@@ -0,0 +1,40 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagsStore(t *testing.T) {
+	client := newTestClient(t)
+	alpine := parseTestImage(t, "alpine:3.20")
+
+	cache, err := client.GetTags("docker.io", alpine)
+	require.NoError(t, err)
+	assert.Equal(t, &registry.TagsCache{}, cache)
+
+	cache = &registry.TagsCache{
+		Endpoint: "https://registry-1.docker.io/v2/library/alpine/tags/list",
+		Pages: []registry.TagsPage{{
+			URL:  "https://registry-1.docker.io/v2/library/alpine/tags/list",
+			ETag: `"0123456789"`,
+			Tags: []string{"3.20", "3.21"},
+		}},
+		Fetched: time.Date(2026, 5, 24, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, client.PutTags("docker.io", alpine, cache))
+
+	// tags are shared by all the tags of a repository
+	stored, err := client.GetTags("docker.io", parseTestImage(t, "alpine:latest"))
+	require.NoError(t, err)
+	assert.Equal(t, cache, stored)
+
+	// but not across registry options
+	stored, err = client.GetTags("mirror", alpine)
+	require.NoError(t, err)
+	assert.Equal(t, &registry.TagsCache{}, stored)
+}
@@ -8,6 +8,7 @@ import (
 	grpclogger "github.com/crazy-max/diun/v4/internal/grpc/logger"
 	"github.com/crazy-max/diun/v4/internal/notif"
 	"github.com/crazy-max/diun/v4/pb"
+	"github.com/crazy-max/diun/v4/pkg/registry"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -22,25 +23,31 @@ const (
 	HealthServiceMetrics   = "diun.metrics"
 )
 
+// RemoteManifestFunc retrieves the remote manifest of an image for a
+// platform (os/arch[/variant])
+type RemoteManifestFunc func(image registry.Image, platform string) (registry.Manifest, error)
+
 // Client represents an active grpc object
 type Client struct {
-	server    *grpc.Server
-	health    *grpchealth.Server
-	authority string
-	db        *db.Client
-	notif     *notif.Client
+	server         *grpc.Server
+	health         *grpchealth.Server
+	authority      string
+	db             *db.Client
+	notif          *notif.Client
+	remoteManifest RemoteManifestFunc
 	pb.UnimplementedImageServiceServer
 	pb.UnimplementedNotifServiceServer
 }
 
 // New creates a new grpc instance
-func New(authority string, db *db.Client, notif *notif.Client) (*Client, error) {
+func New(authority string, db *db.Client, notif *notif.Client, remoteManifest RemoteManifestFunc) (*Client, error) {
 	grpclogger.SetGrpcLogger(log.Level(zerolog.ErrorLevel))
 
 	c := &Client{
-		authority: authority,
-		db:        db,
-		notif:     notif,
+		authority:      authority,
+		db:             db,
+		notif:          notif,
+		remoteManifest: remoteManifest,
 	}
 
 	c.server = grpc.NewServer()
@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/crazy-max/diun/v4/pb"
 	"github.com/crazy-max/diun/v4/pkg/registry"
@@ -22,7 +23,7 @@ func (c *Client) ImageList(_ context.Context, _ *pb.ImageListRequest) (*pb.Image
 		mfsts := mfsts
 		var latest *registry.Manifest
 		for _, mfst := range mfsts {
-			if latest == nil || mfst.Created.After(*latest.Created) {
+			if latest == nil || (mfst.Created != nil && (latest.Created == nil || mfst.Created.After(*latest.Created))) {
 				latest = new(mfst)
 			}
 		}
@@ -30,12 +31,13 @@ func (c *Client) ImageList(_ context.Context, _ *pb.ImageListRequest) (*pb.Image
 			Name:           name,
 			ManifestsCount: int64(len(mfsts)),
 			Latest: &pb.Manifest{
-				Tag:      latest.Tag,
-				MimeType: latest.MIMEType,
-				Digest:   latest.Digest.String(),
-				Created:  timestamppb.New(*latest.Created),
-				Labels:   latest.Labels,
-				Platform: latest.Platform,
+				Tag:          latest.Tag,
+				MimeType:     latest.MIMEType,
+				Digest:       latest.Digest.String(),
+				Created:      timestamp(latest.Created),
+				Labels:       latest.Labels,
+				Platform:     latest.Platform,
+				ArtifactType: latest.ArtifactType,
 			},
 		})
 	}
@@ -66,12 +68,13 @@ func (c *Client) ImageInspect(_ context.Context, request *pb.ImageInspectRequest
 	}
 	for _, manifest := range images[ref.Name()] {
 		iir.Manifests = append(iir.Manifests, &pb.Manifest{
-			Tag:      manifest.Tag,
-			MimeType: manifest.MIMEType,
-			Digest:   manifest.Digest.String(),
-			Created:  timestamppb.New(*manifest.Created),
-			Labels:   manifest.Labels,
-			Platform: manifest.Platform,
+			Tag:          manifest.Tag,
+			MimeType:     manifest.MIMEType,
+			Digest:       manifest.Digest.String(),
+			Created:      timestamp(manifest.Created),
+			Labels:       manifest.Labels,
+			Platform:     manifest.Platform,
+			ArtifactType: manifest.ArtifactType,
 		})
 	}
 
@@ -111,7 +114,7 @@ func (c *Client) ImageRemove(_ context.Context, request *pb.ImageRemoveRequest)
 				Tag:      manifest.Tag,
 				MimeType: manifest.MIMEType,
 				Digest:   manifest.Digest.String(),
-				Created:  timestamppb.New(*manifest.Created),
+				Created:  timestamp(manifest.Created),
 				Labels:   manifest.Labels,
 				Platform: manifest.Platform,
 				Size:     int64(len(b)),
@@ -142,7 +145,7 @@ func (c *Client) ImagePrune(_ context.Context, _ *pb.ImagePruneRequest) (*pb.Ima
 				Tag:      manifest.Tag,
 				MimeType: manifest.MIMEType,
 				Digest:   manifest.Digest.String(),
-				Created:  timestamppb.New(*manifest.Created),
+				Created:  timestamp(manifest.Created),
 				Labels:   manifest.Labels,
 				Platform: manifest.Platform,
 				Size:     int64(len(b)),
@@ -158,3 +161,78 @@ func (c *Client) ImagePrune(_ context.Context, _ *pb.ImagePruneRequest) (*pb.Ima
 		Images: removed,
 	}, nil
 }
+
+func (c *Client) ImageDiff(_ context.Context, request *pb.ImageDiffRequest) (*pb.ImageDiffResponse, error) {
+	image, err := registry.ParseImage(registry.ParseImageOptions{
+		Name: request.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := c.db.GetManifest(image)
+	if err != nil {
+		return nil, err
+	} else if len(stored.Name) == 0 {
+		return nil, errors.Errorf("%s not found in database", image.String())
+	}
+
+	remote, err := c.remoteManifest(image, stored.Platform)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get remote manifest")
+	}
+
+	diff := registry.DiffManifests(stored, remote)
+	return &pb.ImageDiffResponse{
+		Stored:        diffManifest(stored),
+		Remote:        diffManifest(remote),
+		LayersAdded:   diff.LayersAdded,
+		LayersRemoved: diff.LayersRemoved,
+		SizeDelta:     diff.SizeDelta,
+		Labels:        diffChanges(diff.Labels),
+		Annotations:   diffChanges(diff.Annotations),
+		Env:           diffChanges(diff.Env),
+		Entrypoint:    diffChange(diff.Entrypoint),
+		Cmd:           diffChange(diff.Cmd),
+	}, nil
+}
+
+func diffManifest(manifest registry.Manifest) *pb.Manifest {
+	return &pb.Manifest{
+		Tag:          manifest.Tag,
+		MimeType:     manifest.MIMEType,
+		Digest:       manifest.Digest.String(),
+		Created:      timestamp(manifest.Created),
+		Labels:       manifest.Labels,
+		Platform:     manifest.Platform,
+		Size:         manifest.Size,
+		ArtifactType: manifest.ArtifactType,
+	}
+}
+
+// timestamp returns the protobuf timestamp of a date, nil if unknown
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func diffChanges(changes []registry.ValueChange) []*pb.ImageDiffResponse_Change {
+	res := make([]*pb.ImageDiffResponse_Change, 0, len(changes))
+	for _, change := range changes {
+		res = append(res, diffChange(&change))
+	}
+	return res
+}
+
+func diffChange(change *registry.ValueChange) *pb.ImageDiffResponse_Change {
+	if change == nil {
+		return nil
+	}
+	return &pb.ImageDiffResponse_Change{
+		Name: change.Name,
+		From: change.From,
+		To:   change.To,
+	}
+}
@@ -108,6 +108,43 @@ func TestImagePrune(t *testing.T) {
 	assert.Empty(t, manifests)
 }
 
+func TestImageDiff(t *testing.T) {
+	client, _ := newTestClient(t)
+	stored := seedManifest(t, client, "crazymax/diun:latest", time.Date(2026, 5, 23, 12, 0, 0, 0, time.UTC))
+
+	var platform string
+	client.remoteManifest = func(image registry.Image, p string) (registry.Manifest, error) {
+		platform = p
+		remote := stored
+		remote.Digest = digest.FromString("remote")
+		remote.Labels = map[string]string{
+			"org.opencontainers.image.title": "docker.io/crazymax/diun",
+			registry.LabelVersion:            "4.30.0",
+		}
+		remote.Layers = []string{"sha256:layer"}
+		return remote, nil
+	}
+
+	diff, err := client.ImageDiff(context.Background(), &pb.ImageDiffRequest{
+		Name: "crazymax/diun",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "linux/amd64", platform)
+	assert.Equal(t, stored.Digest.String(), diff.Stored.Digest)
+	assert.Equal(t, digest.FromString("remote").String(), diff.Remote.Digest)
+	assert.Equal(t, []string{"sha256:layer"}, diff.LayersAdded)
+	require.Len(t, diff.Labels, 1)
+	assert.Equal(t, registry.LabelVersion, diff.Labels[0].Name)
+	assert.Equal(t, "4.30.0", diff.Labels[0].To)
+	assert.Nil(t, diff.Entrypoint)
+
+	_, err = client.ImageDiff(context.Background(), &pb.ImageDiffRequest{
+		Name: "crazymax/diun:1.0.0",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "docker.io/crazymax/diun:1.0.0 not found in database")
+}
+
 func TestNotifTestWithoutNotifier(t *testing.T) {
 	client, _ := newTestClient(t)
 
@@ -142,7 +179,7 @@ func newTestClient(t *testing.T) (*Client, *db.Client) {
 	notifClient, err := notif.New(nil, model.Meta{})
 	require.NoError(t, err)
 
-	client, err := New("127.0.0.1:0", dbClient, notifClient)
+	client, err := New("127.0.0.1:0", dbClient, notifClient, nil)
 	require.NoError(t, err)
 	return client, dbClient
 }
@@ -0,0 +1,190 @@
+package links
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/pkg/errors"
+)
+
+// OCI annotations used to build links
+const (
+	LabelSource   = "org.opencontainers.image.source"
+	LabelRevision = registry.LabelRevision
+	LabelVersion  = registry.LabelVersion
+)
+
+// Link patterns of the supported forge types
+var forgeTemplates = map[string]struct {
+	compare string
+	release string
+}{
+	model.ForgeGitHub: {
+		compare: `{{ .Source }}/compare/{{ .From }}...{{ .To }}`,
+		release: `{{ .Source }}/releases/tag/{{ .Version }}`,
+	},
+	model.ForgeGitLab: {
+		compare: `{{ .Source }}/-/compare/{{ .From }}...{{ .To }}`,
+		release: `{{ .Source }}/-/releases/{{ .Version }}`,
+	},
+	model.ForgeGitea: {
+		compare: `{{ .Source }}/compare/{{ .From }}...{{ .To }}`,
+		release: `{{ .Source }}/releases/tag/{{ .Version }}`,
+	},
+}
+
+// Forge types of well-known hosts
+var defaultForges = []model.LinksForge{
+	{Host: "github.com", Type: model.ForgeGitHub},
+	{Host: "gitlab.com", Type: model.ForgeGitLab},
+	{Host: "gitea.com", Type: model.ForgeGitea},
+	{Host: "codeberg.org", Type: model.ForgeGitea},
+}
+
+// Client builds links to the source repository of images
+type Client struct {
+	forges map[string]forge
+}
+
+type forge struct {
+	compare *template.Template
+	release *template.Template
+}
+
+// Data holds the data available in link templates
+type Data struct {
+	Source          string
+	From            string
+	To              string
+	Version         string
+	PreviousVersion string
+}
+
+// New creates a new links client. Forges of the configuration take
+// precedence over the well-known hosts.
+func New(cfg *model.Links) (*Client, error) {
+	forges := defaultForges
+	if cfg != nil {
+		forges = append(append([]model.LinksForge{}, defaultForges...), cfg.Forges...)
+	}
+
+	c := &Client{
+		forges: make(map[string]forge, len(forges)),
+	}
+	for _, f := range forges {
+		compare, release := f.Compare, f.Release
+		if tpl, ok := forgeTemplates[f.Type]; ok {
+			if compare == "" {
+				compare = tpl.compare
+			}
+			if release == "" {
+				release = tpl.release
+			}
+		}
+		var err error
+		var fg forge
+		if fg.compare, err = parseTemplate(compare); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse compare template of %s", f.Host)
+		}
+		if fg.release, err = parseTemplate(release); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse release template of %s", f.Host)
+		}
+		c.forges[strings.ToLower(f.Host)] = fg
+	}
+
+	return c, nil
+}
+
+// Links returns the links to the source repository of a manifest. The
+// compare link is only set if the previous manifest has the same source
+// and another revision, and the release link if the manifest has a version.
+func (c *Client) Links(prevManifest, manifest registry.Manifest) (*model.NotifLinks, error) {
+	source, host := normalizeSource(label(manifest, LabelSource))
+	if source == "" {
+		return nil, nil
+	}
+
+	links := &model.NotifLinks{
+		Source: source,
+	}
+	fg, ok := c.forges[host]
+	if !ok {
+		return links, nil
+	}
+
+	data := Data{
+		Source:  source,
+		To:      label(manifest, LabelRevision),
+		Version: label(manifest, LabelVersion),
+	}
+	if prevSource, _ := normalizeSource(label(prevManifest, LabelSource)); prevSource == source {
+		data.From = label(prevManifest, LabelRevision)
+		data.PreviousVersion = label(prevManifest, LabelVersion)
+	}
+
+	var err error
+	if fg.compare != nil && data.From != "" && data.To != "" && data.From != data.To {
+		if links.Compare, err = execTemplate(fg.compare, data); err != nil {
+			return links, errors.Wrap(err, "cannot render compare link")
+		}
+	}
+	if fg.release != nil && data.Version != "" {
+		if links.Release, err = execTemplate(fg.release, data); err != nil {
+			return links, errors.Wrap(err, "cannot render release link")
+		}
+	}
+
+	return links, nil
+}
+
+// label returns the value of an image label, or of a manifest annotation
+// for OCI artifacts
+func label(manifest registry.Manifest, key string) string {
+	if v, ok := manifest.Labels[key]; ok {
+		return v
+	}
+	return manifest.Annotations[key]
+}
+
+// normalizeSource returns the web URL and host of a source repository. SCP
+// like Git remotes (git@host:owner/repo.git) are converted to HTTPS.
+func normalizeSource(source string) (string, string) {
+	source = strings.TrimSpace(source)
+	source = strings.TrimPrefix(source, "git+")
+	if rest, ok := strings.CutPrefix(source, "git@"); ok {
+		if host, path, ok := strings.Cut(rest, ":"); ok {
+			source = "https://" + host + "/" + path
+		}
+	}
+
+	u, err := url.Parse(source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+	u.RawPath = ""
+
+	return u.String(), strings.ToLower(u.Hostname())
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	return template.New("").Option("missingkey=error").Parse(text)
+}
+
+func execTemplate(tpl *template.Template, data Data) (string, error) {
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,119 @@
+package links
+
+import (
+	"testing"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinks(t *testing.T) {
+	c, err := New(&model.Links{
+		Forges: []model.LinksForge{
+			{Host: "git.example.com", Type: model.ForgeGitea},
+			{
+				Host:    "code.example.com",
+				Compare: `https://code.example.com/diff?from={{ .From }}&to={{ .To }}`,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	manifest := func(source, revision, version string) registry.Manifest {
+		return registry.Manifest{Labels: map[string]string{
+			LabelSource:   source,
+			LabelRevision: revision,
+			LabelVersion:  version,
+		}}
+	}
+
+	cases := []struct {
+		name     string
+		prev     registry.Manifest
+		cur      registry.Manifest
+		expected *model.NotifLinks
+	}{
+		{
+			name: "github",
+			prev: manifest("https://github.com/crazy-max/diun", "0123456", "4.27.0"),
+			cur:  manifest("https://github.com/crazy-max/diun.git", "89abcde", "v4.28.0"),
+			expected: &model.NotifLinks{
+				Source:  "https://github.com/crazy-max/diun",
+				Compare: "https://github.com/crazy-max/diun/compare/0123456...89abcde",
+				Release: "https://github.com/crazy-max/diun/releases/tag/v4.28.0",
+			},
+		},
+		{
+			name: "gitlab scp remote",
+			prev: manifest("git@gitlab.com:acme/app.git", "0123456", ""),
+			cur:  manifest("git@gitlab.com:acme/app.git", "89abcde", "1.1.0"),
+			expected: &model.NotifLinks{
+				Source:  "https://gitlab.com/acme/app",
+				Compare: "https://gitlab.com/acme/app/-/compare/0123456...89abcde",
+				Release: "https://gitlab.com/acme/app/-/releases/1.1.0",
+			},
+		},
+		{
+			name: "self-hosted gitea",
+			prev: manifest("https://git.example.com/acme/app", "0123456", ""),
+			cur:  manifest("https://git.example.com/acme/app/", "89abcde", ""),
+			expected: &model.NotifLinks{
+				Source:  "https://git.example.com/acme/app",
+				Compare: "https://git.example.com/acme/app/compare/0123456...89abcde",
+			},
+		},
+		{
+			name: "custom template",
+			prev: manifest("https://code.example.com/acme/app", "0123456", ""),
+			cur:  manifest("https://code.example.com/acme/app", "89abcde", "1.1.0"),
+			expected: &model.NotifLinks{
+				Source:  "https://code.example.com/acme/app",
+				Compare: "https://code.example.com/diff?from=0123456&to=89abcde",
+			},
+		},
+		{
+			name: "first check",
+			cur:  manifest("https://github.com/crazy-max/diun", "89abcde", "4.28.0"),
+			expected: &model.NotifLinks{
+				Source:  "https://github.com/crazy-max/diun",
+				Release: "https://github.com/crazy-max/diun/releases/tag/4.28.0",
+			},
+		},
+		{
+			name: "source changed",
+			prev: manifest("https://github.com/acme/old", "0123456", ""),
+			cur:  manifest("https://github.com/acme/new", "89abcde", ""),
+			expected: &model.NotifLinks{
+				Source: "https://github.com/acme/new",
+			},
+		},
+		{
+			name: "unknown forge",
+			prev: manifest("https://example.org/acme/app", "0123456", ""),
+			cur:  manifest("https://example.org/acme/app", "89abcde", "1.1.0"),
+			expected: &model.NotifLinks{
+				Source: "https://example.org/acme/app",
+			},
+		},
+		{
+			name: "no source",
+			cur:  manifest("", "89abcde", "1.1.0"),
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := c.Links(tt.prev, tt.cur)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, links)
+		})
+	}
+}
+
+func TestNewInvalidTemplate(t *testing.T) {
+	_, err := New(&model.Links{
+		Forges: []model.LinksForge{{Host: "git.example.com", Release: "{{ .Version "}},
+	})
+	require.ErrorContains(t, err, "cannot parse release template of git.example.com")
+}
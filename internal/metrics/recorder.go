@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 )
@@ -31,17 +32,22 @@ type Recorder struct {
 	lastRunDuration       time.Duration
 	lastRunImages         map[model.ImageStatus]int
 	images                map[imageKey]imageState
+	rateLimits            map[string]registry.RateLimit
+	retriesTotal          map[string]uint64
 
-	buildInfoDesc               *prometheus.Desc
-	watchRunsTotalDesc          *prometheus.Desc
-	watchSkippedRunsTotalDesc   *prometheus.Desc
-	watchLastRunTimestampDesc   *prometheus.Desc
-	watchLastRunDurationDesc    *prometheus.Desc
-	watchLastRunImagesDesc      *prometheus.Desc
-	imageUpdateAvailableDesc    *prometheus.Desc
-	imageLastCheckTimestampDesc *prometheus.Desc
-	imageLastCheckStatusDesc    *prometheus.Desc
-	imageCreatedTimestampDesc   *prometheus.Desc
+	buildInfoDesc                  *prometheus.Desc
+	watchRunsTotalDesc             *prometheus.Desc
+	watchSkippedRunsTotalDesc      *prometheus.Desc
+	watchLastRunTimestampDesc      *prometheus.Desc
+	watchLastRunDurationDesc       *prometheus.Desc
+	watchLastRunImagesDesc         *prometheus.Desc
+	imageUpdateAvailableDesc       *prometheus.Desc
+	imageLastCheckTimestampDesc    *prometheus.Desc
+	imageLastCheckStatusDesc       *prometheus.Desc
+	imageCreatedTimestampDesc      *prometheus.Desc
+	registryRateLimitDesc          *prometheus.Desc
+	registryRateLimitRemainingDesc *prometheus.Desc
+	registryRetriesTotalDesc       *prometheus.Desc
 }
 
 type imageKey struct {
@@ -77,6 +83,8 @@ func newRecorder(version string) *Recorder {
 		version:       version,
 		lastRunImages: make(map[model.ImageStatus]int, len(imageStatuses)),
 		images:        make(map[imageKey]imageState),
+		rateLimits:    make(map[string]registry.RateLimit),
+		retriesTotal:  make(map[string]uint64),
 
 		buildInfoDesc: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "", "build_info"),
@@ -138,6 +146,24 @@ func newRecorder(version string) *Recorder {
 			[]string{"provider", "image"},
 			nil,
 		),
+		registryRateLimitDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "registry", "ratelimit_limit"),
+			"Pull rate limit reported by the registry.",
+			[]string{"registry"},
+			nil,
+		),
+		registryRateLimitRemainingDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "registry", "ratelimit_remaining"),
+			"Remaining pulls before reaching the rate limit reported by the registry.",
+			[]string{"registry"},
+			nil,
+		),
+		registryRetriesTotalDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "registry", "retries_total"),
+			"Total number of retried registry requests.",
+			[]string{"registry"},
+			nil,
+		),
 	}
 }
 
@@ -153,6 +179,9 @@ func (r *Recorder) Describe(ch chan<- *prometheus.Desc) {
 	ch <- r.imageLastCheckTimestampDesc
 	ch <- r.imageLastCheckStatusDesc
 	ch <- r.imageCreatedTimestampDesc
+	ch <- r.registryRateLimitDesc
+	ch <- r.registryRateLimitRemainingDesc
+	ch <- r.registryRetriesTotalDesc
 }
 
 // Collect sends metric values to Prometheus.
@@ -190,6 +219,14 @@ func (r *Recorder) Collect(ch chan<- prometheus.Metric) {
 			ch <- prometheus.MustNewConstMetric(r.imageCreatedTimestampDesc, prometheus.GaugeValue, float64(image.created.Unix()), image.provider, image.image)
 		}
 	}
+
+	for domain, rateLimit := range r.rateLimits {
+		ch <- prometheus.MustNewConstMetric(r.registryRateLimitDesc, prometheus.GaugeValue, float64(rateLimit.Limit), domain)
+		ch <- prometheus.MustNewConstMetric(r.registryRateLimitRemainingDesc, prometheus.GaugeValue, float64(rateLimit.Remaining), domain)
+	}
+	for domain, retries := range r.retriesTotal {
+		ch <- prometheus.MustNewConstMetric(r.registryRetriesTotalDesc, prometheus.CounterValue, float64(retries), domain)
+	}
 }
 
 // RecordRun records a completed Diun watch run.
@@ -247,3 +284,27 @@ func (r *Recorder) RecordSkippedRun() {
 
 	r.watchSkippedRunsTotal++
 }
+
+// RecordRateLimit records the pull rate limit reported by a registry.
+func (r *Recorder) RecordRateLimit(domain string, rateLimit *registry.RateLimit) {
+	if r == nil || rateLimit == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rateLimits[domain] = *rateLimit
+}
+
+// RecordRetry records a retried registry request.
+func (r *Recorder) RecordRetry(domain string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.retriesTotal[domain]++
+}
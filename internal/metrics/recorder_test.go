@@ -118,3 +118,44 @@ diun_watch_skipped_runs_total 1
 	)
 	require.NoError(t, err)
 }
+
+func TestRecorderRecordRateLimit(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := newRecorder("1.2.3")
+	registry.MustRegister(recorder)
+
+	recorder.RecordRateLimit("docker.io", &regpkg.RateLimit{Limit: 100, Remaining: 76})
+	recorder.RecordRateLimit("docker.io", &regpkg.RateLimit{Limit: 100, Remaining: 75})
+	recorder.RecordRateLimit("ghcr.io", nil)
+
+	err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP diun_registry_ratelimit_limit Pull rate limit reported by the registry.
+# TYPE diun_registry_ratelimit_limit gauge
+diun_registry_ratelimit_limit{registry="docker.io"} 100
+# HELP diun_registry_ratelimit_remaining Remaining pulls before reaching the rate limit reported by the registry.
+# TYPE diun_registry_ratelimit_remaining gauge
+diun_registry_ratelimit_remaining{registry="docker.io"} 75
+`),
+		"diun_registry_ratelimit_limit",
+		"diun_registry_ratelimit_remaining",
+	)
+	require.NoError(t, err)
+}
+
+func TestRecorderRecordRetry(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := newRecorder("1.2.3")
+	registry.MustRegister(recorder)
+
+	recorder.RecordRetry("registry.example.com")
+	recorder.RecordRetry("registry.example.com")
+
+	err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP diun_registry_retries_total Total number of retried registry requests.
+# TYPE diun_registry_retries_total counter
+diun_registry_retries_total{registry="registry.example.com"} 2
+`),
+		"diun_registry_retries_total",
+	)
+	require.NoError(t, err)
+}
@@ -1,18 +1,24 @@
 package model
 
 import (
+	"time"
+
 	"github.com/crazy-max/diun/v4/pkg/registry"
 )
 
 // Defaults holds data necessary for image defaults configuration
 type Defaults struct {
-	WatchRepo   *bool             `yaml:"watchRepo,omitempty" json:"watchRepo,omitempty"`
-	NotifyOn    []NotifyOn        `yaml:"notifyOn,omitempty" json:"notifyOn,omitempty"`
-	MaxTags     int               `yaml:"maxTags,omitempty" json:"maxTags,omitempty"`
-	SortTags    registry.SortTag  `yaml:"sortTags,omitempty" json:"sortTags,omitempty"`
-	IncludeTags []string          `yaml:"includeTags,omitempty" json:"includeTags,omitempty"`
-	ExcludeTags []string          `yaml:"excludeTags,omitempty" json:"excludeTags,omitempty"`
-	Metadata    map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	WatchRepo    *bool             `yaml:"watchRepo,omitempty" json:"watchRepo,omitempty"`
+	NotifyOn     []NotifyOn        `yaml:"notifyOn,omitempty" json:"notifyOn,omitempty"`
+	MaxTags      int               `yaml:"maxTags,omitempty" json:"maxTags,omitempty"`
+	SortTags     registry.SortTag  `yaml:"sortTags,omitempty" json:"sortTags,omitempty"`
+	SortRegex    string            `yaml:"sortRegex,omitempty" json:"sortRegex,omitempty"`
+	MatchVariant *bool             `yaml:"matchVariant,omitempty" json:"matchVariant,omitempty"`
+	IncludeTags  []string          `yaml:"includeTags,omitempty" json:"includeTags,omitempty"`
+	ExcludeTags  []string          `yaml:"excludeTags,omitempty" json:"excludeTags,omitempty"`
+	MinAge       *time.Duration    `yaml:"minAge,omitempty" json:"minAge,omitempty"`
+	MaxAge       *time.Duration    `yaml:"maxAge,omitempty" json:"maxAge,omitempty"`
+	Metadata     map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
 }
 
 // GetDefaults gets the default values
@@ -1,23 +1,38 @@
 package model
 
 import (
+	"time"
+
 	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/opencontainers/go-digest"
 )
 
 // Image holds image configuration
 type Image struct {
-	Name        string            `yaml:"name,omitempty" json:",omitempty"`
-	Platform    ImagePlatform     `yaml:"platform,omitempty" json:",omitempty"`
-	RegOpt      string            `yaml:"regopt,omitempty" json:",omitempty"`
-	WatchRepo   *bool             `yaml:"watch_repo,omitempty" json:",omitempty"`
-	NotifyOn    []NotifyOn        `yaml:"notify_on,omitempty" json:",omitempty"`
-	MaxTags     int               `yaml:"max_tags,omitempty" json:",omitempty"`
-	SortTags    registry.SortTag  `yaml:"sort_tags,omitempty" json:",omitempty"`
-	IncludeTags []string          `yaml:"include_tags,omitempty" json:",omitempty"`
-	ExcludeTags []string          `yaml:"exclude_tags,omitempty" json:",omitempty"`
-	HubTpl      string            `yaml:"hub_tpl,omitempty" json:",omitempty"`
-	HubLink     string            `yaml:"hub_link,omitempty" json:",omitempty"`
-	Metadata    map[string]string `yaml:"metadata,omitempty" json:",omitempty"`
+	Name          string            `yaml:"name,omitempty" json:",omitempty"`
+	Platform      ImagePlatform     `yaml:"platform,omitempty" json:",omitempty"`
+	RegOpt        string            `yaml:"regopt,omitempty" json:",omitempty"`
+	WatchRepo     *bool             `yaml:"watch_repo,omitempty" json:",omitempty"`
+	NotifyOn      []NotifyOn        `yaml:"notify_on,omitempty" json:",omitempty"`
+	MaxTags       int               `yaml:"max_tags,omitempty" json:",omitempty"`
+	SortTags      registry.SortTag  `yaml:"sort_tags,omitempty" json:",omitempty"`
+	SortRegex     string            `yaml:"sort_regex,omitempty" json:",omitempty"`
+	MatchVariant  *bool             `yaml:"match_variant,omitempty" json:",omitempty"`
+	IncludeTags   []string          `yaml:"include_tags,omitempty" json:",omitempty"`
+	ExcludeTags   []string          `yaml:"exclude_tags,omitempty" json:",omitempty"`
+	HubTpl        string            `yaml:"hub_tpl,omitempty" json:",omitempty"`
+	HubLink       string            `yaml:"hub_link,omitempty" json:",omitempty"`
+	CosignKey     string            `yaml:"cosign_key,omitempty" json:",omitempty"`
+	RequireSigned *bool             `yaml:"require_signed,omitempty" json:",omitempty"`
+	Artifact      *bool             `yaml:"artifact,omitempty" json:",omitempty"`
+	ArtifactTypes []string          `yaml:"artifact_types,omitempty" json:",omitempty"`
+	MinAge        *time.Duration    `yaml:"min_age,omitempty" json:",omitempty"`
+	MaxAge        *time.Duration    `yaml:"max_age,omitempty" json:",omitempty"`
+	Metadata      map[string]string `yaml:"metadata,omitempty" json:",omitempty"`
+
+	// RunningDigest is the digest of the image run by the workload. It is
+	// set by providers able to resolve it and is not configurable.
+	RunningDigest digest.Digest `yaml:"-" json:"-"`
 }
 
 // ImagePlatform holds image platform configuration
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/crazy-max/diun/v4/internal/cosign"
 	"github.com/crazy-max/diun/v4/pkg/registry"
 )
 
@@ -12,4 +13,13 @@ type Job struct {
 	Registry        *registry.Client
 	FirstCheck      bool
 	HubLinkOverride string
+	Verifier        *cosign.Verifier
+	RequireSigned   bool
+	Consumers       []JobConsumer
+}
+
+// JobConsumer holds a provider workload sharing the registry check of a job
+type JobConsumer struct {
+	Provider string
+	Image    Image
 }
@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/opencontainers/go-digest"
 )
 
 // Defaults used for notification template
@@ -10,6 +11,12 @@ const (
 	NotifDefaultTemplateBody  = `Docker tag {{ if .Entry.Image.HubLink }}[**{{ .Entry.Image }}**]({{ .Entry.Image.HubLink }}){{ else }}**{{ .Entry.Image }}**{{ end }} which you subscribed to through {{ .Entry.Provider }} provider {{ if (eq .Entry.Status "new") }}is available{{ else }}has been updated{{ end }} on {{ .Entry.Image.Domain }} registry (triggered by {{ .Meta.Hostname }} host).`
 )
 
+// CloudEvents content modes
+const (
+	NotifCloudEventsStructured = "structured"
+	NotifCloudEventsBinary     = "binary"
+)
+
 // NotifEntries represents a list of notification entries
 type NotifEntries struct {
 	Entries       []NotifEntry
@@ -29,31 +36,98 @@ type NotifEntry struct {
 	Manifest registry.Manifest `json:"manifest,omitempty"`
 	Metadata map[string]string `json:"metadata,omitempty"`
 
+	// RunningDigest is the digest of the image run by the workload of the
+	// entry. It is only set if the provider is able to resolve it.
+	RunningDigest digest.Digest `json:"running_digest,omitempty"`
+
+	// Consumers lists the provider workloads sharing this image. Identical
+	// images referenced by several providers are analyzed once per run.
+	Consumers []NotifConsumer `json:"consumers,omitempty"`
+
+	// Signature holds the cosign verification result of the manifest. It is
+	// only set if signature verification is configured for the registry.
+	Signature *NotifSignature `json:"signature,omitempty"`
+
+	// SupplyChain holds the SBOM and provenance attached to the manifest. It
+	// is only set if supply chain lookup is enabled.
+	SupplyChain *NotifSupplyChain `json:"supply_chain,omitempty"`
+
+	// Diff holds the changes with the previous manifest of the image. It is
+	// only set for updated images.
+	Diff *registry.ManifestDiff `json:"diff,omitempty"`
+
+	// Links holds the links to the source repository of the image built
+	// from its OCI annotations.
+	Links *NotifLinks `json:"links,omitempty"`
+
 	// updateAvailable records whether this result is an actionable image update.
 	// It is intentionally kept out of serialized notification payloads because
 	// Status already represents the public notification contract.
 	updateAvailable bool
 }
 
+// NotifConsumer represents a provider workload using an image
+type NotifConsumer struct {
+	Provider      string            `json:"provider,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	RunningDigest digest.Digest     `json:"running_digest,omitempty"`
+}
+
+// NotifSignature represents the cosign verification result of a manifest
+type NotifSignature struct {
+	Signed   bool   `json:"signed"`
+	Verified bool   `json:"verified"`
+	Attested bool   `json:"attested,omitempty"`
+	Signer   string `json:"signer,omitempty"`
+}
+
+// NotifSupplyChain represents the SBOM and provenance of a manifest along
+// with the package changes since the previous digest
+type NotifSupplyChain struct {
+	SBOMFormat string                `json:"sbom_format,omitempty"`
+	Packages   int                   `json:"packages"`
+	Changes    *registry.PackageDiff `json:"changes,omitempty"`
+	Provenance *registry.Provenance  `json:"provenance,omitempty"`
+}
+
+// NotifLinks represents the links to the source repository of an image
+type NotifLinks struct {
+	Source  string `json:"source,omitempty"`
+	Compare string `json:"compare,omitempty"`
+	Release string `json:"release,omitempty"`
+}
+
 // Notif holds data necessary for notification configuration
 type Notif struct {
+	Alertmanager  *NotifAlertmanager  `yaml:"alertmanager,omitempty" json:"alertmanager,omitempty"`
 	Amqp          *NotifAmqp          `yaml:"amqp,omitempty" json:"amqp,omitempty"`
 	Apprise       *NotifApprise       `yaml:"apprise,omitempty" json:"apprise,omitempty"`
 	Discord       *NotifDiscord       `yaml:"discord,omitempty" json:"discord,omitempty"`
 	Elasticsearch *NotifElasticsearch `yaml:"elasticsearch,omitempty" json:"elasticsearch,omitempty"`
+	Gitea         *NotifGitea         `yaml:"gitea,omitempty" json:"gitea,omitempty"`
+	GitHub        *NotifGitHub        `yaml:"github,omitempty" json:"github,omitempty"`
+	GitLab        *NotifGitLab        `yaml:"gitlab,omitempty" json:"gitlab,omitempty"`
+	GoogleChat    *NotifGoogleChat    `yaml:"googlechat,omitempty" json:"googlechat,omitempty"`
 	Gotify        *NotifGotify        `yaml:"gotify,omitempty" json:"gotify,omitempty"`
+	Kafka         *NotifKafka         `yaml:"kafka,omitempty" json:"kafka,omitempty"`
 	Mail          *NotifMail          `yaml:"mail,omitempty" json:"mail,omitempty"`
 	Matrix        *NotifMatrix        `yaml:"matrix,omitempty" json:"matrix,omitempty"`
+	Mattermost    *NotifMattermost    `yaml:"mattermost,omitempty" json:"mattermost,omitempty"`
 	Mqtt          *NotifMqtt          `yaml:"mqtt,omitempty" json:"mqtt,omitempty"`
+	Nats          *NotifNats          `yaml:"nats,omitempty" json:"nats,omitempty"`
 	Ntfy          *NotifNtfy          `yaml:"ntfy,omitempty" json:"ntfy,omitempty"`
+	Opsgenie      *NotifOpsgenie      `yaml:"opsgenie,omitempty" json:"opsgenie,omitempty"`
+	PagerDuty     *NotifPagerDuty     `yaml:"pagerduty,omitempty" json:"pagerduty,omitempty"`
 	Pushover      *NotifPushover      `yaml:"pushover,omitempty" json:"pushover,omitempty"`
 	RocketChat    *NotifRocketChat    `yaml:"rocketchat,omitempty" json:"rocketchat,omitempty"`
 	Script        *NotifScript        `yaml:"script,omitempty" json:"script,omitempty"`
 	SignalRest    *NotifSignalRest    `yaml:"signalrest,omitempty" json:"signalrest,omitempty"`
 	Slack         *NotifSlack         `yaml:"slack,omitempty" json:"slack,omitempty"`
+	Syslog        *NotifSyslog        `yaml:"syslog,omitempty" json:"syslog,omitempty"`
 	Teams         *NotifTeams         `yaml:"teams,omitempty" json:"teams,omitempty"`
 	Telegram      *NotifTelegram      `yaml:"telegram,omitempty" json:"telegram,omitempty"`
 	Webhook       *NotifWebhook       `yaml:"webhook,omitempty" json:"webhook,omitempty"`
+	Zulip         *NotifZulip         `yaml:"zulip,omitempty" json:"zulip,omitempty"`
 }
 
 // GetDefaults gets the default values
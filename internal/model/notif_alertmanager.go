@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+)
+
+// NotifAlertmanager holds Alertmanager notification configuration details
+type NotifAlertmanager struct {
+	Endpoint       string            `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required,url"`
+	Username       string            `yaml:"username,omitempty" json:"username,omitempty" validate:"omitempty"`
+	UsernameFile   string            `yaml:"usernameFile,omitempty" json:"usernameFile,omitempty" validate:"omitempty,file"`
+	Password       string            `yaml:"password,omitempty" json:"password,omitempty" validate:"omitempty"`
+	PasswordFile   string            `yaml:"passwordFile,omitempty" json:"passwordFile,omitempty" validate:"omitempty,file"`
+	Token          string            `yaml:"token,omitempty" json:"token,omitempty" validate:"omitempty"`
+	TokenFile      string            `yaml:"tokenFile,omitempty" json:"tokenFile,omitempty" validate:"omitempty,file"`
+	AlertName      string            `yaml:"alertName,omitempty" json:"alertName,omitempty" validate:"required"`
+	Labels         map[string]string `yaml:"labels,omitempty" json:"labels,omitempty" validate:"omitempty"`
+	MetadataLabels []string          `yaml:"metadataLabels,omitempty" json:"metadataLabels,omitempty" validate:"omitempty"`
+	ResolveTimeout *time.Duration    `yaml:"resolveTimeout,omitempty" json:"resolveTimeout,omitempty" validate:"required"`
+	Timeout        *time.Duration    `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+	Proxy          string            `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
+	TLSSkipVerify  bool              `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles []string          `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	TemplateTitle  string            `yaml:"templateTitle,omitempty" json:"templateTitle,omitempty" validate:"required"`
+	TemplateBody   string            `yaml:"templateBody,omitempty" json:"templateBody,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifAlertmanager) GetDefaults() *NotifAlertmanager {
+	n := &NotifAlertmanager{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifAlertmanager) SetDefaults() {
+	s.Endpoint = "http://localhost:9093"
+	s.AlertName = "DiunImageUpdate"
+	s.ResolveTimeout = new(24 * time.Hour)
+	s.Timeout = new(10 * time.Second)
+	s.TemplateTitle = NotifDefaultTemplateTitle
+	s.TemplateBody = NotifDefaultTemplateBody
+}
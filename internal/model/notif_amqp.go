@@ -10,6 +10,7 @@ type NotifAmqp struct {
 	Port         int    `yaml:"port,omitempty" json:"port,omitempty" validate:"required,min=1"`
 	Queue        string `yaml:"queue,omitempty" json:"queue,omitempty" validate:"required"`
 	Exchange     string `yaml:"exchange,omitempty" json:"exchange,omitempty" validate:"omitempty"`
+	CloudEvents  string `yaml:"cloudEvents,omitempty" json:"cloudEvents,omitempty" validate:"omitempty,oneof=structured binary"`
 }
 
 // GetDefaults gets the default values
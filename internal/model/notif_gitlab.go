@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+)
+
+// NotifGitLab holds GitLab issues notification configuration details
+type NotifGitLab struct {
+	Endpoint       string         `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required,url"`
+	Token          string         `yaml:"token,omitempty" json:"token,omitempty" validate:"omitempty"`
+	TokenFile      string         `yaml:"tokenFile,omitempty" json:"tokenFile,omitempty" validate:"omitempty,file"`
+	Project        string         `yaml:"project,omitempty" json:"project,omitempty" validate:"required"`
+	Label          string         `yaml:"label,omitempty" json:"label,omitempty" validate:"required"`
+	Labels         []string       `yaml:"labels,omitempty" json:"labels,omitempty" validate:"omitempty"`
+	Assignees      []string       `yaml:"assignees,omitempty" json:"assignees,omitempty" validate:"omitempty"`
+	CloseResolved  *bool          `yaml:"closeResolved,omitempty" json:"closeResolved,omitempty" validate:"required"`
+	Timeout        *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+	Proxy          string         `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
+	TLSSkipVerify  bool           `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles []string       `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	TemplateTitle  string         `yaml:"templateTitle,omitempty" json:"templateTitle,omitempty" validate:"required"`
+	TemplateBody   string         `yaml:"templateBody,omitempty" json:"templateBody,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifGitLab) GetDefaults() *NotifGitLab {
+	n := &NotifGitLab{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifGitLab) SetDefaults() {
+	s.Endpoint = "https://gitlab.com"
+	s.Label = "diun"
+	s.CloseResolved = new(true)
+	s.Timeout = new(10 * time.Second)
+	s.TemplateTitle = NotifDefaultTemplateTitle
+	s.TemplateBody = NotifDefaultTemplateBody
+}
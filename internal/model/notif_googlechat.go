@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+)
+
+// NotifGoogleChatDefaultTemplateBody ...
+const NotifGoogleChatDefaultTemplateBody = `Docker tag {{ if .Entry.Image.HubLink }}<a href="{{ .Entry.Image.HubLink }}"><b>{{ .Entry.Image }}</b></a>{{ else }}<b>{{ .Entry.Image }}</b>{{ end }} which you subscribed to through {{ .Entry.Provider }} provider {{ if (eq .Entry.Status "new") }}is available{{ else }}has been updated{{ end }} on {{ .Entry.Image.Domain }} registry (triggered by {{ .Meta.Hostname }} host).`
+
+// NotifGoogleChat holds Google Chat notification configuration details
+type NotifGoogleChat struct {
+	WebhookURL     string         `yaml:"webhookURL,omitempty" json:"webhookURL,omitempty" validate:"omitempty"`
+	WebhookURLFile string         `yaml:"webhookURLFile,omitempty" json:"webhookURLFile,omitempty" validate:"omitempty,file"`
+	ThreadKey      string         `yaml:"threadKey,omitempty" json:"threadKey,omitempty" validate:"omitempty"`
+	RenderFields   *bool          `yaml:"renderFields,omitempty" json:"renderFields,omitempty" validate:"required"`
+	Timeout        *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+	Proxy          string         `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
+	TLSSkipVerify  bool           `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles []string       `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	TemplateTitle  string         `yaml:"templateTitle,omitempty" json:"templateTitle,omitempty" validate:"required"`
+	TemplateBody   string         `yaml:"templateBody,omitempty" json:"templateBody,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifGoogleChat) GetDefaults() *NotifGoogleChat {
+	n := &NotifGoogleChat{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifGoogleChat) SetDefaults() {
+	s.RenderFields = new(true)
+	s.Timeout = new(10 * time.Second)
+	s.TemplateTitle = NotifDefaultTemplateTitle
+	s.TemplateBody = NotifGoogleChatDefaultTemplateBody
+}
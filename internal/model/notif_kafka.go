@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+)
+
+// Kafka SASL mechanisms
+const (
+	KafkaSASLPlain       = "PLAIN"
+	KafkaSASLScramSha256 = "SCRAM-SHA-256"
+	KafkaSASLScramSha512 = "SCRAM-SHA-512"
+)
+
+// NotifKafka holds Kafka notification configuration details
+type NotifKafka struct {
+	Brokers        []string       `yaml:"brokers,omitempty" json:"brokers,omitempty" validate:"required,min=1,dive,hostname_port"`
+	Topic          string         `yaml:"topic,omitempty" json:"topic,omitempty" validate:"required"`
+	ClientID       string         `yaml:"clientID,omitempty" json:"clientID,omitempty" validate:"required"`
+	SASLMechanism  string         `yaml:"saslMechanism,omitempty" json:"saslMechanism,omitempty" validate:"omitempty,oneof=PLAIN SCRAM-SHA-256 SCRAM-SHA-512"`
+	Username       string         `yaml:"username,omitempty" json:"username,omitempty" validate:"omitempty"`
+	UsernameFile   string         `yaml:"usernameFile,omitempty" json:"usernameFile,omitempty" validate:"omitempty,file"`
+	Password       string         `yaml:"password,omitempty" json:"password,omitempty" validate:"omitempty"`
+	PasswordFile   string         `yaml:"passwordFile,omitempty" json:"passwordFile,omitempty" validate:"omitempty,file"`
+	TLS            bool           `yaml:"tls,omitempty" json:"tls,omitempty" validate:"omitempty"`
+	TLSSkipVerify  bool           `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles []string       `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	CloudEvents    string         `yaml:"cloudEvents,omitempty" json:"cloudEvents,omitempty" validate:"omitempty,oneof=structured binary"`
+	Timeout        *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifKafka) GetDefaults() *NotifKafka {
+	n := &NotifKafka{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifKafka) SetDefaults() {
+	s.Brokers = []string{"localhost:9092"}
+	s.Topic = "diun"
+	s.ClientID = "diun"
+	s.Timeout = new(10 * time.Second)
+}
@@ -5,8 +5,8 @@ const NotifMailDefaultTemplateBody = `Docker tag {{ if .Entry.Image.HubLink }}[*
 which you subscribed to through {{ .Entry.Provider }} provider {{ if (eq .Entry.Status "new") }}is available{{ else }}has been updated{{ end }}
 on **{{ .Entry.Image.Domain }}** registry (triggered by _{{ escapeMarkdown .Meta.Hostname }}_ host).
 
-This image has been {{ if (eq .Entry.Status "new") }}created{{ else }}updated{{ end }} at
-<code>{{ .Entry.Manifest.Created.Format "Jan 02, 2006 15:04:05 UTC" }}</code> with digest <code>{{ .Entry.Manifest.Digest }}</code>
+This image has been {{ if (eq .Entry.Status "new") }}created{{ else }}updated{{ end }}{{ if .Entry.Manifest.Created }} at
+<code>{{ .Entry.Manifest.Created.Format "Jan 02, 2006 15:04:05 UTC" }}</code>{{ end }} with digest <code>{{ .Entry.Manifest.Digest }}</code>
 for <code>{{ .Entry.Manifest.Platform }}</code> platform.
 
 Need help, or have questions? Go to {{ .Meta.URL }} and leave an issue.`
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+// NotifMattermost holds Mattermost notification configuration details
+type NotifMattermost struct {
+	WebhookURL       string         `yaml:"webhookURL,omitempty" json:"webhookURL,omitempty" validate:"omitempty"`
+	WebhookURLFile   string         `yaml:"webhookURLFile,omitempty" json:"webhookURLFile,omitempty" validate:"omitempty,file"`
+	Endpoint         string         `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"omitempty,url"`
+	Token            string         `yaml:"token,omitempty" json:"token,omitempty" validate:"omitempty"`
+	TokenFile        string         `yaml:"tokenFile,omitempty" json:"tokenFile,omitempty" validate:"omitempty,file"`
+	ChannelID        string         `yaml:"channelID,omitempty" json:"channelID,omitempty" validate:"required_with=Endpoint"`
+	Channel          string         `yaml:"channel,omitempty" json:"channel,omitempty" validate:"omitempty"`
+	Username         string         `yaml:"username,omitempty" json:"username,omitempty" validate:"omitempty"`
+	IconURL          string         `yaml:"iconURL,omitempty" json:"iconURL,omitempty" validate:"omitempty,url"`
+	RenderAttachment *bool          `yaml:"renderAttachment,omitempty" json:"renderAttachment,omitempty" validate:"required"`
+	RenderFields     *bool          `yaml:"renderFields,omitempty" json:"renderFields,omitempty" validate:"required"`
+	Timeout          *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+	Proxy            string         `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
+	TLSSkipVerify    bool           `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles   []string       `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	TemplateTitle    string         `yaml:"templateTitle,omitempty" json:"templateTitle,omitempty" validate:"required"`
+	TemplateBody     string         `yaml:"templateBody,omitempty" json:"templateBody,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifMattermost) GetDefaults() *NotifMattermost {
+	n := &NotifMattermost{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifMattermost) SetDefaults() {
+	s.RenderAttachment = new(true)
+	s.RenderFields = new(true)
+	s.Timeout = new(10 * time.Second)
+	s.TemplateTitle = NotifDefaultTemplateTitle
+	s.TemplateBody = NotifDefaultTemplateBody
+}
@@ -11,6 +11,9 @@ type NotifMqtt struct {
 	Client       string `yaml:"client,omitempty" json:"client,omitempty" validate:"required"`
 	Topic        string `yaml:"topic,omitempty" json:"topic,omitempty" validate:"required"`
 	QoS          int    `yaml:"qos,omitempty" json:"qos,omitempty" validate:"omitempty"`
+	CloudEvents  string `yaml:"cloudEvents,omitempty" json:"cloudEvents,omitempty" validate:"omitempty,oneof=structured"`
+
+	HomeAssistant *NotifMqttHomeAssistant `yaml:"homeAssistant,omitempty" json:"homeAssistant,omitempty"`
 }
 
 // GetDefaults gets the default values
@@ -0,0 +1,21 @@
+package model
+
+// NotifMqttHomeAssistant holds Home Assistant MQTT discovery configuration
+type NotifMqttHomeAssistant struct {
+	DiscoveryPrefix string `yaml:"discoveryPrefix,omitempty" json:"discoveryPrefix,omitempty" validate:"required"`
+	BaseTopic       string `yaml:"baseTopic,omitempty" json:"baseTopic,omitempty" validate:"required"`
+	NodeID          string `yaml:"nodeId,omitempty" json:"nodeId,omitempty" validate:"omitempty"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifMqttHomeAssistant) GetDefaults() *NotifMqttHomeAssistant {
+	n := &NotifMqttHomeAssistant{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifMqttHomeAssistant) SetDefaults() {
+	s.DiscoveryPrefix = "homeassistant"
+	s.BaseTopic = "diun"
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+)
+
+// NotifNats holds NATS notification configuration details
+type NotifNats struct {
+	URL             string         `yaml:"url,omitempty" json:"url,omitempty" validate:"required"`
+	Subject         string         `yaml:"subject,omitempty" json:"subject,omitempty" validate:"required"`
+	Username        string         `yaml:"username,omitempty" json:"username,omitempty" validate:"omitempty"`
+	UsernameFile    string         `yaml:"usernameFile,omitempty" json:"usernameFile,omitempty" validate:"omitempty,file"`
+	Password        string         `yaml:"password,omitempty" json:"password,omitempty" validate:"omitempty"`
+	PasswordFile    string         `yaml:"passwordFile,omitempty" json:"passwordFile,omitempty" validate:"omitempty,file"`
+	Token           string         `yaml:"token,omitempty" json:"token,omitempty" validate:"omitempty"`
+	TokenFile       string         `yaml:"tokenFile,omitempty" json:"tokenFile,omitempty" validate:"omitempty,file"`
+	CredentialsFile string         `yaml:"credentialsFile,omitempty" json:"credentialsFile,omitempty" validate:"omitempty,file"`
+	JetStream       bool           `yaml:"jetStream,omitempty" json:"jetStream,omitempty" validate:"omitempty"`
+	TLSSkipVerify   bool           `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles  []string       `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	Timeout         *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifNats) GetDefaults() *NotifNats {
+	n := &NotifNats{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifNats) SetDefaults() {
+	s.URL = "nats://localhost:4222"
+	s.Subject = "diun"
+	s.Timeout = new(10 * time.Second)
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+)
+
+// NotifOpsgenie holds Opsgenie notification configuration details
+type NotifOpsgenie struct {
+	Endpoint       string            `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required,url"`
+	APIKey         string            `yaml:"apiKey,omitempty" json:"apiKey,omitempty" validate:"omitempty"`
+	APIKeyFile     string            `yaml:"apiKeyFile,omitempty" json:"apiKeyFile,omitempty" validate:"omitempty,file"`
+	Priority       map[string]string `yaml:"priority,omitempty" json:"priority,omitempty" validate:"dive,keys,oneof=new update,endkeys,oneof=P1 P2 P3 P4 P5"`
+	Tags           []string          `yaml:"tags,omitempty" json:"tags,omitempty" validate:"omitempty"`
+	Responders     []string          `yaml:"responders,omitempty" json:"responders,omitempty" validate:"omitempty"`
+	Timeout        *time.Duration    `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+	Proxy          string            `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
+	TLSSkipVerify  bool              `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles []string          `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	TemplateTitle  string            `yaml:"templateTitle,omitempty" json:"templateTitle,omitempty" validate:"required"`
+	TemplateBody   string            `yaml:"templateBody,omitempty" json:"templateBody,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifOpsgenie) GetDefaults() *NotifOpsgenie {
+	n := &NotifOpsgenie{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifOpsgenie) SetDefaults() {
+	s.Endpoint = "https://api.opsgenie.com"
+	s.Priority = map[string]string{
+		string(ImageStatusNew):    "P5",
+		string(ImageStatusUpdate): "P3",
+	}
+	s.Tags = []string{"diun"}
+	s.Timeout = new(10 * time.Second)
+	s.TemplateTitle = NotifDefaultTemplateTitle
+	s.TemplateBody = NotifDefaultTemplateBody
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+)
+
+// PagerDuty severities
+const (
+	PagerDutySeverityCritical = "critical"
+	PagerDutySeverityError    = "error"
+	PagerDutySeverityWarning  = "warning"
+	PagerDutySeverityInfo     = "info"
+)
+
+// NotifPagerDuty holds PagerDuty notification configuration details
+type NotifPagerDuty struct {
+	Endpoint       string            `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required,url"`
+	RoutingKey     string            `yaml:"routingKey,omitempty" json:"routingKey,omitempty" validate:"omitempty"`
+	RoutingKeyFile string            `yaml:"routingKeyFile,omitempty" json:"routingKeyFile,omitempty" validate:"omitempty,file"`
+	Severity       map[string]string `yaml:"severity,omitempty" json:"severity,omitempty" validate:"dive,keys,oneof=new update,endkeys,oneof=critical error warning info"`
+	Source         string            `yaml:"source,omitempty" json:"source,omitempty" validate:"omitempty"`
+	Timeout        *time.Duration    `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+	Proxy          string            `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
+	TLSSkipVerify  bool              `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles []string          `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	TemplateTitle  string            `yaml:"templateTitle,omitempty" json:"templateTitle,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifPagerDuty) GetDefaults() *NotifPagerDuty {
+	n := &NotifPagerDuty{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifPagerDuty) SetDefaults() {
+	s.Endpoint = "https://events.pagerduty.com/v2/enqueue"
+	s.Severity = map[string]string{
+		string(ImageStatusNew):    PagerDutySeverityInfo,
+		string(ImageStatusUpdate): PagerDutySeverityWarning,
+	}
+	s.Timeout = new(10 * time.Second)
+	s.TemplateTitle = NotifDefaultTemplateTitle
+}
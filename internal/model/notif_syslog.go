@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+)
+
+// NotifSyslogDefaultTemplateBody ...
+const NotifSyslogDefaultTemplateBody = `Docker tag {{ .Entry.Image }} {{ if (eq .Entry.Status "new") }}is available{{ else }}has been updated{{ end }} ({{ .Entry.Manifest.Digest }})`
+
+// Syslog networks
+const (
+	SyslogNetworkUDP      = "udp"
+	SyslogNetworkTCP      = "tcp"
+	SyslogNetworkTLS      = "tls"
+	SyslogNetworkUnix     = "unix"
+	SyslogNetworkUnixgram = "unixgram"
+)
+
+// Syslog framings for stream transports
+// https://datatracker.ietf.org/doc/html/rfc6587#section-3.4
+const (
+	SyslogFramingOctetCounting  = "octet-counting"
+	SyslogFramingNonTransparent = "non-transparent"
+)
+
+// NotifSyslog holds syslog notification configuration details
+type NotifSyslog struct {
+	Network        string            `yaml:"network,omitempty" json:"network,omitempty" validate:"required,oneof=udp tcp tls unix unixgram"`
+	Address        string            `yaml:"address,omitempty" json:"address,omitempty" validate:"required"`
+	Framing        string            `yaml:"framing,omitempty" json:"framing,omitempty" validate:"required,oneof=octet-counting non-transparent"`
+	Tag            string            `yaml:"tag,omitempty" json:"tag,omitempty" validate:"required,max=48"`
+	Facility       string            `yaml:"facility,omitempty" json:"facility,omitempty" validate:"required,oneof=kern user mail daemon auth syslog lpr news uucp cron authpriv ftp local0 local1 local2 local3 local4 local5 local6 local7"`
+	Severity       map[string]string `yaml:"severity,omitempty" json:"severity,omitempty" validate:"dive,keys,oneof=new update,endkeys,oneof=emerg alert crit err warning notice info debug"`
+	Journald       *bool             `yaml:"journald,omitempty" json:"journald,omitempty" validate:"required"`
+	Timeout        *time.Duration    `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+	TLSSkipVerify  bool              `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles []string          `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	TLSClientCert  string            `yaml:"tlsClientCert,omitempty" json:"tlsClientCert,omitempty" validate:"required_with=TLSClientKey,omitempty,file"`
+	TLSClientKey   string            `yaml:"tlsClientKey,omitempty" json:"tlsClientKey,omitempty" validate:"required_with=TLSClientCert,omitempty,file"`
+	TemplateBody   string            `yaml:"templateBody,omitempty" json:"templateBody,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifSyslog) GetDefaults() *NotifSyslog {
+	n := &NotifSyslog{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifSyslog) SetDefaults() {
+	s.Network = SyslogNetworkUDP
+	s.Address = "localhost:514"
+	s.Framing = SyslogFramingOctetCounting
+	s.Tag = "diun"
+	s.Facility = "daemon"
+	s.Severity = map[string]string{
+		string(ImageStatusNew):    "info",
+		string(ImageStatusUpdate): "notice",
+	}
+	s.Journald = new(false)
+	s.Timeout = new(10 * time.Second)
+	s.TemplateBody = NotifSyslogDefaultTemplateBody
+}
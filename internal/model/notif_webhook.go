@@ -6,13 +6,40 @@ import (
 
 // NotifWebhook holds webhook notification configuration details
 type NotifWebhook struct {
-	Endpoint       string            `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required"`
-	Method         string            `yaml:"method,omitempty" json:"method,omitempty" validate:"required"`
-	Headers        map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" validate:"omitempty"`
-	Timeout        *time.Duration    `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
-	Proxy          string            `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
-	TLSSkipVerify  bool              `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
-	TLSCACertFiles []string          `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	Endpoint        string                 `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required"`
+	Method          string                 `yaml:"method,omitempty" json:"method,omitempty" validate:"required"`
+	Headers         map[string]string      `yaml:"headers,omitempty" json:"headers,omitempty" validate:"omitempty"`
+	HeaderTemplates map[string]string      `yaml:"headerTemplates,omitempty" json:"headerTemplates,omitempty" validate:"omitempty"`
+	CloudEvents     string                 `yaml:"cloudEvents,omitempty" json:"cloudEvents,omitempty" validate:"omitempty,oneof=structured binary"`
+	BodyTemplate    string                 `yaml:"bodyTemplate,omitempty" json:"bodyTemplate,omitempty" validate:"omitempty,excluded_with=CloudEvents"`
+	ContentType     string                 `yaml:"contentType,omitempty" json:"contentType,omitempty" validate:"omitempty,excluded_with=CloudEvents"`
+	ExpectedStatus  []int                  `yaml:"expectedStatus,omitempty" json:"expectedStatus,omitempty" validate:"omitempty,dive,min=100,max=599"`
+	Signature       *NotifWebhookSignature `yaml:"signature,omitempty" json:"signature,omitempty"`
+	OAuth2          *NotifWebhookOAuth2    `yaml:"oauth2,omitempty" json:"oauth2,omitempty"`
+	Timeout         *time.Duration         `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+	Proxy           string                 `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
+	TLSSkipVerify   bool                   `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles  []string               `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+}
+
+// NotifWebhookSignature holds the HMAC-SHA256 signing configuration of
+// webhook requests
+type NotifWebhookSignature struct {
+	Secret          string `yaml:"secret,omitempty" json:"secret,omitempty" validate:"omitempty"`
+	SecretFile      string `yaml:"secretFile,omitempty" json:"secretFile,omitempty" validate:"omitempty,file"`
+	Header          string `yaml:"header,omitempty" json:"header,omitempty" validate:"required"`
+	TimestampHeader string `yaml:"timestampHeader,omitempty" json:"timestampHeader,omitempty" validate:"required"`
+}
+
+// NotifWebhookOAuth2 holds the OAuth2 client credentials configuration used
+// to authenticate webhook requests
+type NotifWebhookOAuth2 struct {
+	TokenURL         string            `yaml:"tokenUrl,omitempty" json:"tokenUrl,omitempty" validate:"required,url"`
+	ClientID         string            `yaml:"clientId,omitempty" json:"clientId,omitempty" validate:"required"`
+	ClientSecret     string            `yaml:"clientSecret,omitempty" json:"clientSecret,omitempty" validate:"omitempty"`
+	ClientSecretFile string            `yaml:"clientSecretFile,omitempty" json:"clientSecretFile,omitempty" validate:"omitempty,file"`
+	Scopes           []string          `yaml:"scopes,omitempty" json:"scopes,omitempty" validate:"omitempty"`
+	EndpointParams   map[string]string `yaml:"endpointParams,omitempty" json:"endpointParams,omitempty" validate:"omitempty"`
 }
 
 // GetDefaults gets the default values
@@ -27,3 +54,16 @@ func (s *NotifWebhook) SetDefaults() {
 	s.Method = "GET"
 	s.Timeout = new(10 * time.Second)
 }
+
+// GetDefaults gets the default values
+func (s *NotifWebhookSignature) GetDefaults() *NotifWebhookSignature {
+	n := &NotifWebhookSignature{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifWebhookSignature) SetDefaults() {
+	s.Header = "X-Diun-Signature"
+	s.TimestampHeader = "X-Diun-Timestamp"
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// NotifZulipDefaultTemplateTopic ...
+const NotifZulipDefaultTemplateTopic = `{{ .Entry.Image.Name }}`
+
+// NotifZulip holds Zulip notification configuration details
+type NotifZulip struct {
+	Endpoint       string         `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required,url"`
+	Email          string         `yaml:"email,omitempty" json:"email,omitempty" validate:"required,email"`
+	APIKey         string         `yaml:"apiKey,omitempty" json:"apiKey,omitempty" validate:"omitempty"`
+	APIKeyFile     string         `yaml:"apiKeyFile,omitempty" json:"apiKeyFile,omitempty" validate:"omitempty,file"`
+	Stream         string         `yaml:"stream,omitempty" json:"stream,omitempty" validate:"required"`
+	Topic          string         `yaml:"topic,omitempty" json:"topic,omitempty" validate:"required"`
+	Timeout        *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" validate:"required"`
+	Proxy          string         `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
+	TLSSkipVerify  bool           `yaml:"tlsSkipVerify,omitempty" json:"tlsSkipVerify,omitempty" validate:"omitempty"`
+	TLSCACertFiles []string       `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty"`
+	TemplateTitle  string         `yaml:"templateTitle,omitempty" json:"templateTitle,omitempty" validate:"required"`
+	TemplateBody   string         `yaml:"templateBody,omitempty" json:"templateBody,omitempty" validate:"required"`
+}
+
+// GetDefaults gets the default values
+func (s *NotifZulip) GetDefaults() *NotifZulip {
+	n := &NotifZulip{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *NotifZulip) SetDefaults() {
+	s.Topic = NotifZulipDefaultTemplateTopic
+	s.Timeout = new(10 * time.Second)
+	s.TemplateTitle = NotifDefaultTemplateTitle
+	s.TemplateBody = NotifDefaultTemplateBody
+}
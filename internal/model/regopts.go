@@ -13,14 +13,34 @@ type RegOpts []RegOpt
 
 // RegOpt holds registry options configuration
 type RegOpt struct {
-	Name         string         `yaml:"name,omitempty" json:"name,omitempty" validate:"required"`
-	Selector     RegOptSelector `yaml:"selector,omitempty" json:"selector,omitempty" validate:"required,oneof=name image"`
-	Username     string         `yaml:"username,omitempty" json:"username,omitempty" validate:"omitempty"`
-	UsernameFile string         `yaml:"usernameFile,omitempty" json:"usernameFile,omitempty" validate:"omitempty,file"`
-	Password     string         `yaml:"password,omitempty" json:"password,omitempty" validate:"omitempty"`
-	PasswordFile string         `yaml:"passwordFile,omitempty" json:"passwordFile,omitempty" validate:"omitempty,file"`
-	InsecureTLS  *bool          `yaml:"insecureTLS,omitempty" json:"insecureTLS,omitempty" validate:"required"`
-	Timeout      *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	Name             string         `yaml:"name,omitempty" json:"name,omitempty" validate:"required"`
+	Selector         RegOptSelector `yaml:"selector,omitempty" json:"selector,omitempty" validate:"required,oneof=name image"`
+	Username         string         `yaml:"username,omitempty" json:"username,omitempty" validate:"omitempty"`
+	UsernameFile     string         `yaml:"usernameFile,omitempty" json:"usernameFile,omitempty" validate:"omitempty,file"`
+	Password         string         `yaml:"password,omitempty" json:"password,omitempty" validate:"omitempty"`
+	PasswordFile     string         `yaml:"passwordFile,omitempty" json:"passwordFile,omitempty" validate:"omitempty,file"`
+	AuthFile         string         `yaml:"authFile,omitempty" json:"authFile,omitempty" validate:"omitempty,file"`
+	CredentialHelper string         `yaml:"credentialHelper,omitempty" json:"credentialHelper,omitempty" validate:"omitempty,excluded_with=AuthFile"`
+	InsecureTLS      *bool          `yaml:"insecureTLS,omitempty" json:"insecureTLS,omitempty" validate:"required"`
+	TLSCACertFiles   []string       `yaml:"tlsCaCertFiles,omitempty" json:"tlsCaCertFiles,omitempty" validate:"omitempty,dive,file"`
+	TLSClientCert    string         `yaml:"tlsClientCert,omitempty" json:"tlsClientCert,omitempty" validate:"required_with=TLSClientKey,omitempty,file"`
+	TLSClientKey     string         `yaml:"tlsClientKey,omitempty" json:"tlsClientKey,omitempty" validate:"required_with=TLSClientCert,omitempty,file"`
+	Proxy            string         `yaml:"proxy,omitempty" json:"proxy,omitempty" validate:"omitempty,url"`
+	Mirrors          []string       `yaml:"mirrors,omitempty" json:"mirrors,omitempty" validate:"omitempty,dive,required"`
+	Timeout          *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	MaxConcurrency   int            `yaml:"maxConcurrency,omitempty" json:"maxConcurrency,omitempty" validate:"omitempty,min=0"`
+	Retry            *RegOptRetry   `yaml:"retry,omitempty" json:"retry,omitempty"`
+	ECR              *RegOptECR     `yaml:"ecr,omitempty" json:"ecr,omitempty" validate:"excluded_with=GAR ACR Username UsernameFile"`
+	GAR              *RegOptGAR     `yaml:"gar,omitempty" json:"gar,omitempty" validate:"excluded_with=ECR ACR Username UsernameFile"`
+	ACR              *RegOptACR     `yaml:"acr,omitempty" json:"acr,omitempty" validate:"excluded_with=ECR GAR Username UsernameFile"`
+	Cosign           *RegOptCosign  `yaml:"cosign,omitempty" json:"cosign,omitempty"`
+}
+
+// RegOptRetry holds registry requests retry configuration
+type RegOptRetry struct {
+	Attempts int            `yaml:"attempts,omitempty" json:"attempts,omitempty" validate:"required,min=1"`
+	Backoff  *time.Duration `yaml:"backoff,omitempty" json:"backoff,omitempty" validate:"required"`
+	Statuses []int          `yaml:"statuses,omitempty" json:"statuses,omitempty" validate:"omitempty,dive,min=100,max=599"`
 }
 
 // RegOpt selector constants
@@ -46,6 +66,20 @@ func (s *RegOpt) SetDefaults() {
 	s.Timeout = new(time.Duration(0))
 }
 
+// GetDefaults gets the default values
+func (s *RegOptRetry) GetDefaults() *RegOptRetry {
+	n := &RegOptRetry{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *RegOptRetry) SetDefaults() {
+	s.Attempts = 3
+	s.Backoff = new(time.Second)
+	s.Statuses = []int{500, 502, 503, 504}
+}
+
 // Select returns a registry based on its selector
 func (s *RegOpts) Select(name string, image registry.Image) (*RegOpt, error) {
 	for _, regOpt := range *s {
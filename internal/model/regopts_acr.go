@@ -0,0 +1,23 @@
+package model
+
+// RegOptACR holds Azure Container Registry authentication configuration
+type RegOptACR struct {
+	TenantID           string `yaml:"tenantID,omitempty" json:"tenantID,omitempty" validate:"omitempty"`
+	ClientID           string `yaml:"clientID,omitempty" json:"clientID,omitempty" validate:"omitempty"`
+	ClientSecret       string `yaml:"clientSecret,omitempty" json:"clientSecret,omitempty" validate:"omitempty"`
+	ClientSecretFile   string `yaml:"clientSecretFile,omitempty" json:"clientSecretFile,omitempty" validate:"omitempty,file"`
+	FederatedTokenFile string `yaml:"federatedTokenFile,omitempty" json:"federatedTokenFile,omitempty" validate:"omitempty,file"`
+	AuthorityHost      string `yaml:"authorityHost,omitempty" json:"authorityHost,omitempty" validate:"omitempty,url"`
+}
+
+// GetDefaults gets the default values
+func (s *RegOptACR) GetDefaults() *RegOptACR {
+	n := &RegOptACR{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *RegOptACR) SetDefaults() {
+	// noop
+}
@@ -0,0 +1,31 @@
+package model
+
+// RegOptCosign holds cosign signature verification configuration
+type RegOptCosign struct {
+	PublicKeys      []string               `yaml:"publicKeys,omitempty" json:"publicKeys,omitempty" validate:"omitempty,dive,file"`
+	Identities      []RegOptCosignIdentity `yaml:"identities,omitempty" json:"identities,omitempty" validate:"omitempty,dive"`
+	RootCerts       []string               `yaml:"rootCerts,omitempty" json:"rootCerts,omitempty" validate:"required_with=Identities,omitempty,dive,file"`
+	RekorPublicKeys []string               `yaml:"rekorPublicKeys,omitempty" json:"rekorPublicKeys,omitempty" validate:"required_with=Identities,omitempty,dive,file"`
+	Attestations    *bool                  `yaml:"attestations,omitempty" json:"attestations,omitempty" validate:"required"`
+	RequireSigned   *bool                  `yaml:"requireSigned,omitempty" json:"requireSigned,omitempty" validate:"required"`
+}
+
+// RegOptCosignIdentity holds a keyless signing identity
+type RegOptCosignIdentity struct {
+	Issuer        string `yaml:"issuer,omitempty" json:"issuer,omitempty" validate:"required"`
+	Subject       string `yaml:"subject,omitempty" json:"subject,omitempty" validate:"required_without=SubjectRegexp"`
+	SubjectRegexp string `yaml:"subjectRegexp,omitempty" json:"subjectRegexp,omitempty" validate:"omitempty"`
+}
+
+// GetDefaults gets the default values
+func (s *RegOptCosign) GetDefaults() *RegOptCosign {
+	n := &RegOptCosign{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *RegOptCosign) SetDefaults() {
+	s.Attestations = new(false)
+	s.RequireSigned = new(false)
+}
@@ -0,0 +1,26 @@
+package model
+
+// RegOptECR holds AWS Elastic Container Registry authentication configuration
+type RegOptECR struct {
+	Region               string `yaml:"region,omitempty" json:"region,omitempty" validate:"omitempty"`
+	AccessKeyID          string `yaml:"accessKeyID,omitempty" json:"accessKeyID,omitempty" validate:"omitempty"`
+	SecretAccessKey      string `yaml:"secretAccessKey,omitempty" json:"secretAccessKey,omitempty" validate:"omitempty"`
+	SecretAccessKeyFile  string `yaml:"secretAccessKeyFile,omitempty" json:"secretAccessKeyFile,omitempty" validate:"omitempty,file"`
+	SessionToken         string `yaml:"sessionToken,omitempty" json:"sessionToken,omitempty" validate:"omitempty"`
+	RoleARN              string `yaml:"roleARN,omitempty" json:"roleARN,omitempty" validate:"omitempty"`
+	WebIdentityTokenFile string `yaml:"webIdentityTokenFile,omitempty" json:"webIdentityTokenFile,omitempty" validate:"omitempty,file"`
+	Endpoint             string `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"omitempty,url"`
+	STSEndpoint          string `yaml:"stsEndpoint,omitempty" json:"stsEndpoint,omitempty" validate:"omitempty,url"`
+}
+
+// GetDefaults gets the default values
+func (s *RegOptECR) GetDefaults() *RegOptECR {
+	n := &RegOptECR{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *RegOptECR) SetDefaults() {
+	// noop
+}
@@ -0,0 +1,18 @@
+package model
+
+// RegOptGAR holds Google Artifact Registry authentication configuration
+type RegOptGAR struct {
+	ServiceAccountFile string `yaml:"serviceAccountFile,omitempty" json:"serviceAccountFile,omitempty" validate:"omitempty,file"`
+}
+
+// GetDefaults gets the default values
+func (s *RegOptGAR) GetDefaults() *RegOptGAR {
+	n := &RegOptGAR{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *RegOptGAR) SetDefaults() {
+	// noop
+}
@@ -6,13 +6,17 @@ import (
 
 // Watch holds data necessary for watch configuration
 type Watch struct {
-	Workers         int            `yaml:"workers,omitempty" json:"workers,omitempty" validate:"required,min=1"`
-	Schedule        string         `yaml:"schedule,omitempty" json:"schedule,omitempty"`
-	Jitter          *time.Duration `yaml:"jitter,omitempty" json:"jitter,omitempty" validate:"required"`
-	FirstCheckNotif *bool          `yaml:"firstCheckNotif,omitempty" json:"firstCheckNotif,omitempty" validate:"required"`
-	RunOnStartup    *bool          `yaml:"runOnStartup,omitempty" json:"runOnStartup,omitempty" validate:"required"`
-	CompareDigest   *bool          `yaml:"compareDigest,omitempty" json:"compareDigest,omitempty" validate:"required"`
-	Healthchecks    *Healthchecks  `yaml:"healthchecks,omitempty" json:"healthchecks,omitempty"`
+	Workers            int            `yaml:"workers,omitempty" json:"workers,omitempty" validate:"required,min=1"`
+	Schedule           string         `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	Jitter             *time.Duration `yaml:"jitter,omitempty" json:"jitter,omitempty" validate:"required"`
+	FirstCheckNotif    *bool          `yaml:"firstCheckNotif,omitempty" json:"firstCheckNotif,omitempty" validate:"required"`
+	RunOnStartup       *bool          `yaml:"runOnStartup,omitempty" json:"runOnStartup,omitempty" validate:"required"`
+	CompareDigest      *bool          `yaml:"compareDigest,omitempty" json:"compareDigest,omitempty" validate:"required"`
+	RateLimitThreshold *int           `yaml:"rateLimitThreshold,omitempty" json:"rateLimitThreshold,omitempty" validate:"required,min=0"`
+	SupplyChain        *bool          `yaml:"supplyChain,omitempty" json:"supplyChain,omitempty" validate:"required"`
+	TagsCacheTTL       *time.Duration `yaml:"tagsCacheTTL,omitempty" json:"tagsCacheTTL,omitempty" validate:"required"`
+	Links              *Links         `yaml:"links,omitempty" json:"links,omitempty"`
+	Healthchecks       *Healthchecks  `yaml:"healthchecks,omitempty" json:"healthchecks,omitempty"`
 }
 
 // GetDefaults gets the default values
@@ -29,4 +33,7 @@ func (s *Watch) SetDefaults() {
 	s.FirstCheckNotif = new(false)
 	s.RunOnStartup = new(true)
 	s.CompareDigest = new(true)
+	s.RateLimitThreshold = new(10)
+	s.SupplyChain = new(false)
+	s.TagsCacheTTL = new(time.Duration(0))
 }
@@ -0,0 +1,34 @@
+package model
+
+// Forge types supported for source links
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+	ForgeGitea  = "gitea"
+)
+
+// Links holds data necessary for source links configuration
+type Links struct {
+	Forges []LinksForge `yaml:"forges,omitempty" json:"forges,omitempty" validate:"omitempty,dive"`
+}
+
+// LinksForge holds the link patterns of a source repository host. Compare
+// and Release templates override the ones of the forge type.
+type LinksForge struct {
+	Host    string `yaml:"host,omitempty" json:"host,omitempty" validate:"required"`
+	Type    string `yaml:"type,omitempty" json:"type,omitempty" validate:"required_without_all=Compare Release,omitempty,oneof=github gitlab gitea"`
+	Compare string `yaml:"compare,omitempty" json:"compare,omitempty" validate:"omitempty"`
+	Release string `yaml:"release,omitempty" json:"release,omitempty" validate:"omitempty"`
+}
+
+// GetDefaults gets the default values
+func (s *Links) GetDefaults() *Links {
+	n := &Links{}
+	n.SetDefaults()
+	return n
+}
+
+// SetDefaults sets the default values
+func (s *Links) SetDefaults() {
+	// noop
+}
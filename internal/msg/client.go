@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
@@ -92,39 +93,47 @@ func (c *Client) RenderHTML() (title []byte, body []byte, err error) {
 // RenderJSON returns a notification message as JSON
 func (c *Client) RenderJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Version  string            `json:"diun_version"`
-		Hostname string            `json:"hostname"`
-		Status   string            `json:"status"`
-		Provider string            `json:"provider"`
-		Image    string            `json:"image"`
-		HubLink  string            `json:"hub_link"`
-		MIMEType string            `json:"mime_type"`
-		Digest   digest.Digest     `json:"digest"`
-		Created  *time.Time        `json:"created"`
-		Platform string            `json:"platform"`
-		Metadata map[string]string `json:"metadata"`
+		Version     string                  `json:"diun_version"`
+		Hostname    string                  `json:"hostname"`
+		Status      string                  `json:"status"`
+		Provider    string                  `json:"provider"`
+		Image       string                  `json:"image"`
+		HubLink     string                  `json:"hub_link"`
+		MIMEType    string                  `json:"mime_type"`
+		Digest      digest.Digest           `json:"digest"`
+		Created     *time.Time              `json:"created"`
+		Platform    string                  `json:"platform"`
+		Artifact    string                  `json:"artifact_type,omitempty"`
+		Metadata    map[string]string       `json:"metadata"`
+		Consumers   []model.NotifConsumer   `json:"consumers,omitempty"`
+		Signature   *model.NotifSignature   `json:"signature,omitempty"`
+		SupplyChain *model.NotifSupplyChain `json:"supply_chain,omitempty"`
+		Diff        *registry.ManifestDiff  `json:"diff,omitempty"`
+		Links       *model.NotifLinks       `json:"links,omitempty"`
 	}{
-		Version:  c.opts.Meta.Version,
-		Hostname: c.opts.Meta.Hostname,
-		Status:   string(c.opts.Entry.Status),
-		Provider: c.opts.Entry.Provider,
-		Image:    c.opts.Entry.Image.String(),
-		HubLink:  c.opts.Entry.Image.HubLink,
-		MIMEType: c.opts.Entry.Manifest.MIMEType,
-		Digest:   c.opts.Entry.Manifest.Digest,
-		Created:  c.opts.Entry.Manifest.Created,
-		Platform: c.opts.Entry.Manifest.Platform,
-		Metadata: c.opts.Entry.Metadata,
+		Version:     c.opts.Meta.Version,
+		Hostname:    c.opts.Meta.Hostname,
+		Status:      string(c.opts.Entry.Status),
+		Provider:    c.opts.Entry.Provider,
+		Image:       c.opts.Entry.Image.String(),
+		HubLink:     c.opts.Entry.Image.HubLink,
+		MIMEType:    c.opts.Entry.Manifest.MIMEType,
+		Digest:      c.opts.Entry.Manifest.Digest,
+		Created:     c.opts.Entry.Manifest.Created,
+		Platform:    c.opts.Entry.Manifest.Platform,
+		Artifact:    c.opts.Entry.Manifest.ArtifactType,
+		Metadata:    c.opts.Entry.Metadata,
+		Consumers:   c.opts.Entry.Consumers,
+		Signature:   c.opts.Entry.Signature,
+		SupplyChain: c.opts.Entry.SupplyChain,
+		Diff:        c.opts.Entry.Diff,
+		Links:       c.opts.Entry.Links,
 	})
 }
 
 // RenderEnv returns a notification message as environment variables
 func (c *Client) RenderEnv() []string {
-	var metadataEnvs []string
-	for k, v := range c.opts.Entry.Metadata {
-		metadataEnvs = append(metadataEnvs, fmt.Sprintf("DIUN_ENTRY_METADATA_%s=%s", strings.ToUpper(k), v))
-	}
-	return append([]string{
+	envs := []string{
 		fmt.Sprintf("DIUN_VERSION=%s", c.opts.Meta.Version),
 		fmt.Sprintf("DIUN_HOSTNAME=%s", c.opts.Meta.Hostname),
 		fmt.Sprintf("DIUN_ENTRY_STATUS=%s", string(c.opts.Entry.Status)),
@@ -135,5 +144,36 @@ func (c *Client) RenderEnv() []string {
 		fmt.Sprintf("DIUN_ENTRY_DIGEST=%s", c.opts.Entry.Manifest.Digest),
 		fmt.Sprintf("DIUN_ENTRY_CREATED=%s", c.opts.Entry.Manifest.Created),
 		fmt.Sprintf("DIUN_ENTRY_PLATFORM=%s", c.opts.Entry.Manifest.Platform),
-	}, metadataEnvs...)
+	}
+	if artifactType := c.opts.Entry.Manifest.ArtifactType; artifactType != "" {
+		envs = append(envs, fmt.Sprintf("DIUN_ENTRY_ARTIFACTTYPE=%s", artifactType))
+	}
+	if sig := c.opts.Entry.Signature; sig != nil {
+		envs = append(envs,
+			fmt.Sprintf("DIUN_ENTRY_SIGNED=%t", sig.Signed),
+			fmt.Sprintf("DIUN_ENTRY_VERIFIED=%t", sig.Verified),
+			fmt.Sprintf("DIUN_ENTRY_SIGNER=%s", sig.Signer),
+		)
+	}
+	if diff := c.opts.Entry.Diff; diff != nil {
+		envs = append(envs,
+			fmt.Sprintf("DIUN_ENTRY_DIFF_LAYERS_ADDED=%d", len(diff.LayersAdded)),
+			fmt.Sprintf("DIUN_ENTRY_DIFF_LAYERS_REMOVED=%d", len(diff.LayersRemoved)),
+			fmt.Sprintf("DIUN_ENTRY_DIFF_SIZE_DELTA=%d", diff.SizeDelta),
+		)
+		if diff.Version != nil {
+			envs = append(envs, fmt.Sprintf("DIUN_ENTRY_DIFF_VERSION=%s", diff.Version.To))
+		}
+	}
+	if links := c.opts.Entry.Links; links != nil {
+		envs = append(envs,
+			fmt.Sprintf("DIUN_ENTRY_SOURCE_LINK=%s", links.Source),
+			fmt.Sprintf("DIUN_ENTRY_COMPARE_LINK=%s", links.Compare),
+			fmt.Sprintf("DIUN_ENTRY_RELEASE_LINK=%s", links.Release),
+		)
+	}
+	for k, v := range c.opts.Entry.Metadata {
+		envs = append(envs, fmt.Sprintf("DIUN_ENTRY_METADATA_%s=%s", strings.ToUpper(k), v))
+	}
+	return envs
 }
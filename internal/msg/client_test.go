@@ -43,6 +43,15 @@ func TestRenderMarkdownUsesDefaultTemplateFuncs(t *testing.T) {
 	assert.Equal(t, "Padded Value 1.2.3 1.2.3 1-2-3 diun DIUN", string(body))
 }
 
+func TestRenderTemplateToJSON(t *testing.T) {
+	client := newTestClient(t, Options{})
+
+	body, err := client.RenderTemplate("body", `{"text": {{ toJson (printf "%s \"%s\"" .Entry.Image.Tag .Meta.Hostname) }}, "metadata": {{ toJson .Entry.Metadata }}}`)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"text": "1.2.3 \"node-1\"", "metadata": {"owner": "ops", "ticket": "DIUN-123"}}`, string(body))
+}
+
 func TestRenderMarkdownAllowsTemplateFuncOverrides(t *testing.T) {
 	client := newTestClient(t, Options{
 		TemplateTitle: `{{ upper .Entry.Image.Tag }} {{ lower "DIUN" }}`,
@@ -192,8 +201,173 @@ func newTestClient(t *testing.T, overrides Options) *Client {
 	if overrides.TemplateFuncs != nil {
 		opts.TemplateFuncs = overrides.TemplateFuncs
 	}
+	if overrides.Entry.Signature != nil {
+		opts.Entry.Signature = overrides.Entry.Signature
+	}
+	if overrides.Entry.SupplyChain != nil {
+		opts.Entry.SupplyChain = overrides.Entry.SupplyChain
+	}
+	if overrides.Entry.Diff != nil {
+		opts.Entry.Diff = overrides.Entry.Diff
+	}
+	if overrides.Entry.Links != nil {
+		opts.Entry.Links = overrides.Entry.Links
+	}
+	if overrides.Entry.Manifest.ArtifactType != "" {
+		opts.Entry.Manifest.ArtifactType = overrides.Entry.Manifest.ArtifactType
+	}
 
 	client, err := New(opts)
 	require.NoError(t, err)
 	return client
 }
+
+func TestRenderEnvSignature(t *testing.T) {
+	client := newTestClient(t, Options{
+		Entry: model.NotifEntry{
+			Signature: &model.NotifSignature{
+				Signed:   true,
+				Verified: true,
+				Signer:   "ops@example.com",
+			},
+		},
+	})
+
+	envs := client.RenderEnv()
+	assert.Contains(t, envs, "DIUN_ENTRY_SIGNED=true")
+	assert.Contains(t, envs, "DIUN_ENTRY_VERIFIED=true")
+	assert.Contains(t, envs, "DIUN_ENTRY_SIGNER=ops@example.com")
+
+	body, err := client.RenderJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"signature":{"signed":true,"verified":true,"signer":"ops@example.com"}`)
+}
+
+func TestRenderJSONSupplyChain(t *testing.T) {
+	client := newTestClient(t, Options{
+		Entry: model.NotifEntry{
+			SupplyChain: &model.NotifSupplyChain{
+				SBOMFormat: registry.SBOMFormatSPDX,
+				Packages:   12,
+				Changes: &registry.PackageDiff{
+					Updated: []registry.PackageUpdate{{Name: "busybox", From: "1.36.1-r15", To: "1.36.1-r29"}},
+				},
+			},
+		},
+		TemplateBody: `{{ range .Entry.SupplyChain.Changes.Updated }}{{ .Name }} {{ .From }} -> {{ .To }}{{ end }}`,
+	})
+
+	body, err := client.RenderJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"supply_chain":{"sbom_format":"spdx","packages":12,"changes":{"updated":[{"name":"busybox","from":"1.36.1-r15","to":"1.36.1-r29"}]}}`)
+
+	_, msg, err := client.RenderMarkdown()
+	require.NoError(t, err)
+	assert.Equal(t, "busybox 1.36.1-r15 -> 1.36.1-r29", string(msg))
+}
+
+func TestRenderDiff(t *testing.T) {
+	client := newTestClient(t, Options{
+		Entry: model.NotifEntry{
+			Diff: &registry.ManifestDiff{
+				LayersAdded:   []string{"sha256:app-1.1.0"},
+				LayersRemoved: []string{"sha256:app-1.0.0"},
+				SizeDelta:     -2048,
+				Version:       &registry.ValueChange{Name: registry.LabelVersion, From: "1.0.0", To: "1.1.0"},
+			},
+		},
+		TemplateBody: `{{ with .Entry.Diff }}{{ .Version.From }} -> {{ .Version.To }} ({{ len .LayersAdded }} layer added){{ end }}`,
+	})
+
+	envs := client.RenderEnv()
+	assert.Contains(t, envs, "DIUN_ENTRY_DIFF_LAYERS_ADDED=1")
+	assert.Contains(t, envs, "DIUN_ENTRY_DIFF_LAYERS_REMOVED=1")
+	assert.Contains(t, envs, "DIUN_ENTRY_DIFF_SIZE_DELTA=-2048")
+	assert.Contains(t, envs, "DIUN_ENTRY_DIFF_VERSION=1.1.0")
+
+	body, err := client.RenderJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"diff":{"layers_added":["sha256:app-1.1.0"],"layers_removed":["sha256:app-1.0.0"],"size_delta":-2048,"version":{"name":"org.opencontainers.image.version","from":"1.0.0","to":"1.1.0"}}`)
+
+	_, msg, err := client.RenderMarkdown()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0 -> 1.1.0 (1 layer added)", string(msg))
+}
+
+func TestRenderLinks(t *testing.T) {
+	client := newTestClient(t, Options{
+		Entry: model.NotifEntry{
+			Links: &model.NotifLinks{
+				Source:  "https://github.com/crazy-max/diun",
+				Compare: "https://github.com/crazy-max/diun/compare/0123456...89abcde",
+			},
+		},
+		TemplateBody: `{{ with .Entry.Links }}[Changes]({{ .Compare }}){{ end }}`,
+	})
+
+	envs := client.RenderEnv()
+	assert.Contains(t, envs, "DIUN_ENTRY_SOURCE_LINK=https://github.com/crazy-max/diun")
+	assert.Contains(t, envs, "DIUN_ENTRY_COMPARE_LINK=https://github.com/crazy-max/diun/compare/0123456...89abcde")
+	assert.Contains(t, envs, "DIUN_ENTRY_RELEASE_LINK=")
+
+	body, err := client.RenderJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"links":{"source":"https://github.com/crazy-max/diun","compare":"https://github.com/crazy-max/diun/compare/0123456...89abcde"}`)
+
+	_, msg, err := client.RenderMarkdown()
+	require.NoError(t, err)
+	assert.Equal(t, "[Changes](https://github.com/crazy-max/diun/compare/0123456...89abcde)", string(msg))
+}
+
+func TestRenderArtifactType(t *testing.T) {
+	client := newTestClient(t, Options{
+		Entry: model.NotifEntry{
+			Manifest: registry.Manifest{
+				ArtifactType: registry.HelmChartArtifactType,
+			},
+		},
+	})
+
+	assert.Contains(t, client.RenderEnv(), "DIUN_ENTRY_ARTIFACTTYPE="+registry.HelmChartArtifactType)
+
+	body, err := client.RenderJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"artifact_type":"`+registry.HelmChartArtifactType+`"`)
+
+	client = newTestClient(t, Options{})
+	assert.NotContains(t, strings.Join(client.RenderEnv(), "\n"), "DIUN_ENTRY_ARTIFACTTYPE")
+}
+
+func TestRenderCloudEvent(t *testing.T) {
+	client := newTestClient(t, Options{})
+
+	event, err := client.RenderCloudEvent()
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.0", event.SpecVersion)
+	assert.Equal(t, "node-1", event.Source)
+	assert.Equal(t, "io.diun.image.updated", event.Type)
+	assert.Equal(t, "docker.io/crazymax/diun:1.2.3", event.Subject)
+	assert.Equal(t, "application/json", event.DataContentType)
+	assert.Len(t, event.ID, 64)
+	assert.False(t, event.Time.IsZero())
+
+	body, err := client.RenderJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(body), string(event.Data))
+
+	again, err := client.RenderCloudEvent()
+	require.NoError(t, err)
+	assert.Equal(t, event.ID, again.ID)
+
+	structured, err := json.Marshal(event)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(structured, &decoded))
+	assert.Equal(t, "io.diun.image.updated", decoded["type"])
+	assert.Equal(t, "docker.io/crazymax/diun:1.2.3", decoded["data"].(map[string]any)["image"])
+
+	attrs := event.Attributes()
+	assert.Equal(t, "docker.io/crazymax/diun:1.2.3", attrs["subject"])
+	assert.NotContains(t, attrs, "datacontenttype")
+}
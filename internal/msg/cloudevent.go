@@ -0,0 +1,77 @@
+package msg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+)
+
+// CloudEventsContentType is the media type of a CloudEvent in structured
+// content mode
+const CloudEventsContentType = "application/cloudevents+json; charset=UTF-8"
+
+// CloudEvent is a CloudEvents 1.0 event carrying a JSON notification message
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// RenderCloudEvent returns a notification message as a CloudEvent. The
+// event ID is derived from the entry so a redelivered notification keeps
+// the same ID.
+func (c *Client) RenderCloudEvent() (CloudEvent, error) {
+	data, err := c.RenderJSON()
+	if err != nil {
+		return CloudEvent{}, err
+	}
+
+	id := sha256.Sum256([]byte(c.opts.Meta.Hostname + "|" + string(c.opts.Entry.Status) + "|" + c.opts.Entry.Image.String() + "@" + c.opts.Entry.Manifest.Digest.String()))
+
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              hex.EncodeToString(id[:]),
+		Source:          c.opts.Meta.Hostname,
+		Type:            cloudEventType(c.opts.Entry.Status),
+		Subject:         c.opts.Entry.Image.String(),
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}, nil
+}
+
+// Attributes returns the context attributes of the event as used by the
+// binary content mode, without the data content type which is carried by
+// the content type of the message.
+func (e CloudEvent) Attributes() map[string]string {
+	attrs := map[string]string{
+		"specversion": e.SpecVersion,
+		"id":          e.ID,
+		"source":      e.Source,
+		"type":        e.Type,
+		"time":        e.Time.Format(time.RFC3339Nano),
+	}
+	if e.Subject != "" {
+		attrs["subject"] = e.Subject
+	}
+	return attrs
+}
+
+func cloudEventType(status model.ImageStatus) string {
+	switch status {
+	case model.ImageStatusNew:
+		return "io.diun.image.added"
+	case model.ImageStatusUpdate:
+		return "io.diun.image.updated"
+	default:
+		return "io.diun.image." + string(status)
+	}
+}
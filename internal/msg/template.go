@@ -1,6 +1,7 @@
 package msg
 
 import (
+	"encoding/json"
 	"regexp"
 	"strings"
 	"text/template"
@@ -9,6 +10,10 @@ import (
 func templateFuncs(overrides template.FuncMap) template.FuncMap {
 	funcs := template.FuncMap{
 		"lower": strings.ToLower,
+		"toJson": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
 		"regexReplaceAll": func(pattern, repl, s string) (string, error) {
 			re, err := regexp.Compile(pattern)
 			if err != nil {
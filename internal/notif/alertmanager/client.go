@@ -0,0 +1,297 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+)
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// Client represents an active Alertmanager notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifAlertmanager
+	meta model.Meta
+}
+
+// alert is a postable and gettable alert of the Alertmanager v2 API
+// https://github.com/prometheus/alertmanager/blob/main/api/v2/openapi.yaml
+type alert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt,omitzero"`
+	EndsAt       time.Time         `json:"endsAt,omitzero"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// New creates a new Alertmanager notification instance
+func New(config *model.NotifAlertmanager, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "alertmanager"
+}
+
+// Send fires an alert for each workload running the image of an entry. The
+// alerts end after the resolve timeout unless they are refreshed or resolved
+// at the end of a run.
+func (c *Client) Send(entry model.NotifEntry) error {
+	consumers := entry.Consumers
+	if len(consumers) == 0 {
+		consumers = []model.NotifConsumer{{
+			Provider:      entry.Provider,
+			Metadata:      entry.Metadata,
+			RunningDigest: entry.RunningDigest,
+		}}
+	}
+
+	now := time.Now().UTC()
+	var alerts []alert
+	fired := make(map[string]bool)
+	for _, consumer := range consumers {
+		consumerEntry := entry
+		consumerEntry.Provider = consumer.Provider
+		consumerEntry.Metadata = consumer.Metadata
+		consumerEntry.RunningDigest = consumer.RunningDigest
+
+		labels := c.labels(consumerEntry)
+		key := labelsKey(labels)
+		if fired[key] {
+			continue
+		}
+		fired[key] = true
+
+		message, err := msg.New(msg.Options{
+			Meta:          c.meta,
+			Entry:         consumerEntry,
+			TemplateTitle: c.cfg.TemplateTitle,
+			TemplateBody:  c.cfg.TemplateBody,
+		})
+		if err != nil {
+			return err
+		}
+		title, body, err := message.RenderMarkdown()
+		if err != nil {
+			return err
+		}
+
+		alerts = append(alerts, alert{
+			Labels: labels,
+			Annotations: map[string]string{
+				"summary":     string(title),
+				"description": string(body),
+				"digest":      entry.Manifest.Digest.String(),
+			},
+			StartsAt:     now,
+			EndsAt:       now.Add(*c.cfg.ResolveTimeout),
+			GeneratorURL: entry.Image.HubLink,
+		})
+	}
+
+	return c.do("POST", "/api/v2/alerts", nil, alerts, nil)
+}
+
+// Resolve updates the firing alerts of the workloads reported during a run.
+// Alerts of workloads now running the latest digest are resolved, the other
+// ones are refreshed so they keep firing until the workload catches up.
+// Alerts of workloads no longer reported end after the resolve timeout.
+func (c *Client) Resolve(entries []model.NotifEntry) error {
+	// caughtUp records for the labels of each reported workload whether it
+	// runs the latest digest. Workloads sharing the same labels must all have
+	// caught up to resolve their alert.
+	caughtUp := make(map[string]bool)
+	for _, entry := range entries {
+		key := labelsKey(c.labels(entry))
+		if !isCaughtUp(entry) {
+			caughtUp[key] = false
+		} else if _, ok := caughtUp[key]; !ok {
+			caughtUp[key] = true
+		}
+	}
+	if len(caughtUp) == 0 {
+		return nil
+	}
+
+	query := url.Values{}
+	query.Set("active", "true")
+	query.Add("filter", fmt.Sprintf("alertname=%s", strconv.Quote(c.cfg.AlertName)))
+	query.Add("filter", fmt.Sprintf("hostname=%s", strconv.Quote(c.meta.Hostname)))
+
+	var alerts []alert
+	if err := c.do("GET", "/api/v2/alerts", query, nil, &alerts); err != nil {
+		return errors.Wrap(err, "cannot list firing alerts")
+	}
+
+	now := time.Now().UTC()
+	var updated []alert
+	for _, a := range alerts {
+		resolved, reported := caughtUp[labelsKey(a.Labels)]
+		if !reported {
+			continue
+		}
+		if resolved {
+			a.EndsAt = now
+		} else {
+			a.EndsAt = now.Add(*c.cfg.ResolveTimeout)
+		}
+		updated = append(updated, a)
+	}
+	if len(updated) == 0 {
+		return nil
+	}
+	return errors.Wrap(c.do("POST", "/api/v2/alerts", nil, updated, nil), "cannot update alerts")
+}
+
+// isCaughtUp returns true if the workload of an entry runs the latest digest
+// of its image. It is false if the image could not be analyzed or the running
+// digest is unknown.
+func isCaughtUp(entry model.NotifEntry) bool {
+	switch entry.Status {
+	case model.ImageStatusNew, model.ImageStatusUpdate, model.ImageStatusUnchange:
+	default:
+		return false
+	}
+	return entry.RunningDigest != "" && entry.RunningDigest == entry.Manifest.Digest
+}
+
+// labels returns the labels identifying the alert of an entry. Static and
+// metadata labels cannot override the ones set by Diun.
+func (c *Client) labels(entry model.NotifEntry) map[string]string {
+	labels := make(map[string]string)
+	for name, value := range c.cfg.Labels {
+		labels[name] = value
+	}
+	for _, key := range c.cfg.MetadataLabels {
+		labels[labelName(key)] = entry.Metadata[key]
+	}
+	labels["alertname"] = c.cfg.AlertName
+	labels["hostname"] = c.meta.Hostname
+	labels["provider"] = entry.Provider
+	labels["image"] = entry.Image.String()
+	labels["image_domain"] = entry.Image.Domain
+	labels["image_path"] = entry.Image.Path
+	labels["image_tag"] = entry.Image.Tag
+	for name, value := range labels {
+		if value == "" {
+			delete(labels, name)
+		}
+	}
+	return labels
+}
+
+func (c *Client) do(method, path string, query url.Values, in, out any) error {
+	username, err := secret.GetSecret(c.cfg.Username, c.cfg.UsernameFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve username secret for Alertmanager notifier")
+	}
+	password, err := secret.GetSecret(c.cfg.Password, c.cfg.PasswordFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve password secret for Alertmanager notifier")
+	}
+	token, err := secret.GetSecret(c.cfg.Token, c.cfg.TokenFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve token secret for Alertmanager notifier")
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(c.cfg.Endpoint, "/") + path)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	defer func() { cancelCause(errors.WithStack(context.Canceled)) }()
+	timeoutCtx, cancel := context.WithTimeoutCause(cancelCtx, *c.cfg.Timeout, errors.WithStack(context.DeadlineExceeded))
+	defer cancel()
+
+	hc, err := httputil.NewClient(c.cfg.Proxy, c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return errors.Wrap(err, "cannot create HTTP client for Alertmanager notifier")
+	}
+	req, err := http.NewRequestWithContext(timeoutCtx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.meta.UserAgent)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(username) > 0 || len(password) > 0 {
+		req.SetBasicAuth(username, password)
+	}
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("unexpected HTTP status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrapf(err, "cannot decode JSON body response for HTTP %d %s status", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return nil
+}
+
+// labelName sanitizes a metadata key to a valid Prometheus label name
+func labelName(key string) string {
+	name := invalidLabelChars.ReplaceAllString(key, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// labelsKey returns a key identifying an alert by its labels
+func labelsKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+strconv.Quote(value))
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
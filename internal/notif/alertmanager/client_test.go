@@ -0,0 +1,244 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAlertmanager stores the posted alerts by labels and lists the active ones
+type fakeAlertmanager struct {
+	mu     sync.Mutex
+	alerts map[string]alert
+	gets   int
+}
+
+func newFakeAlertmanager(t *testing.T) (*fakeAlertmanager, *httptest.Server) {
+	am := &fakeAlertmanager{alerts: make(map[string]alert)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v2/alerts", func(w http.ResponseWriter, r *http.Request) {
+		am.mu.Lock()
+		defer am.mu.Unlock()
+		am.gets++
+		assert.Equal(t, []string{`alertname="DiunImageUpdate"`, `hostname="node-1"`}, r.URL.Query()["filter"])
+		res := []alert{}
+		for _, a := range am.alerts {
+			if a.EndsAt.After(time.Now()) {
+				res = append(res, a)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+	mux.HandleFunc("POST /api/v2/alerts", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "diun", user)
+		assert.Equal(t, "p4ss", pass)
+		var posted []alert
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+		am.mu.Lock()
+		defer am.mu.Unlock()
+		for _, a := range posted {
+			am.alerts[a.Labels["ctn_names"]] = a
+		}
+	})
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return am, ts
+}
+
+func (am *fakeAlertmanager) alert(ctn string) alert {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	return am.alerts[ctn]
+}
+
+func (am *fakeAlertmanager) firing(ctn string) bool {
+	return am.alert(ctn).EndsAt.After(time.Now())
+}
+
+func newTestClient(endpoint string, resolveTimeout time.Duration) Client {
+	return Client{
+		cfg: &model.NotifAlertmanager{
+			Endpoint:       endpoint,
+			Username:       "diun",
+			Password:       "p4ss",
+			AlertName:      "DiunImageUpdate",
+			Labels:         map[string]string{"severity": "info", "image": "overridden"},
+			MetadataLabels: []string{"ctn.names", "missing"},
+			ResolveTimeout: new(resolveTimeout),
+			Timeout:        new(2 * time.Second),
+			TemplateTitle:  model.NotifDefaultTemplateTitle,
+			TemplateBody:   "Provider {{ .Entry.Provider }} container {{ index .Entry.Metadata \"ctn.names\" }}",
+		},
+		meta: model.Meta{
+			Hostname: "node-1",
+		},
+	}
+}
+
+func TestSendResolve(t *testing.T) {
+	am, ts := newFakeAlertmanager(t)
+	c := newTestClient(ts.URL, time.Hour)
+
+	entry := testEntry(t)
+	require.NoError(t, c.Send(entry))
+	require.Len(t, am.alerts, 1)
+	a := am.alert("diun")
+	assert.Equal(t, map[string]string{
+		"alertname":    "DiunImageUpdate",
+		"hostname":     "node-1",
+		"provider":     "docker",
+		"image":        "docker.io/crazymax/diun:latest",
+		"image_domain": "docker.io",
+		"image_path":   "crazymax/diun",
+		"image_tag":    "latest",
+		"severity":     "info",
+		"ctn_names":    "diun",
+	}, a.Labels)
+	assert.Equal(t, map[string]string{
+		"summary":     "docker.io/crazymax/diun:latest has been updated",
+		"description": "Provider docker container diun",
+		"digest":      "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+	}, a.Annotations)
+	assert.Equal(t, "https://hub.docker.com/r/crazymax/diun", a.GeneratorURL)
+	assert.WithinDuration(t, a.StartsAt.Add(time.Hour), a.EndsAt, time.Second)
+
+	// empty runs do not query Alertmanager
+	require.NoError(t, c.Resolve([]model.NotifEntry{}))
+	assert.Zero(t, am.gets)
+
+	// errors, skipped images and workloads still running the previous digest
+	// leave the alert firing
+	entry.Status = model.ImageStatusError
+	entry.RunningDigest = entry.Manifest.Digest
+	require.NoError(t, c.Resolve([]model.NotifEntry{entry}))
+	entry.Status = model.ImageStatusSkip
+	require.NoError(t, c.Resolve([]model.NotifEntry{entry}))
+	entry.Status = model.ImageStatusUnchange
+	entry.RunningDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	require.NoError(t, c.Resolve([]model.NotifEntry{entry}))
+	assert.True(t, am.firing("diun"))
+
+	// another workload of the image running the latest digest
+	other := entry
+	other.RunningDigest = other.Manifest.Digest
+	other.Metadata = map[string]string{"ctn.names": "diun-2"}
+	require.NoError(t, c.Resolve([]model.NotifEntry{entry, other}))
+	assert.True(t, am.firing("diun"))
+
+	entry.RunningDigest = entry.Manifest.Digest
+	require.NoError(t, c.Resolve([]model.NotifEntry{entry}))
+	resolved := am.alert("diun")
+	assert.False(t, resolved.EndsAt.After(time.Now()))
+	assert.Equal(t, a.StartsAt, resolved.StartsAt)
+	assert.Equal(t, a.Labels, resolved.Labels)
+}
+
+func TestSendConsumers(t *testing.T) {
+	am, ts := newFakeAlertmanager(t)
+	c := newTestClient(ts.URL, time.Hour)
+
+	entry := testEntry(t)
+	entry.Consumers = []model.NotifConsumer{
+		{Provider: "docker", Metadata: map[string]string{"ctn.names": "diun"}},
+		{Provider: "docker", Metadata: map[string]string{"ctn.names": "diun-2"}},
+		{Provider: "swarm", Metadata: map[string]string{"ctn.names": "diun-3"}},
+	}
+	require.NoError(t, c.Send(entry))
+	require.Len(t, am.alerts, 3)
+	assert.Equal(t, "docker", am.alert("diun-2").Labels["provider"])
+	assert.Equal(t, "Provider docker container diun-2", am.alert("diun-2").Annotations["description"])
+	assert.Equal(t, "swarm", am.alert("diun-3").Labels["provider"])
+	assert.Equal(t, "Provider swarm container diun-3", am.alert("diun-3").Annotations["description"])
+
+	// each workload is resolved once it runs the latest digest
+	var entries []model.NotifEntry
+	for _, consumer := range entry.Consumers {
+		consumerEntry := entry
+		consumerEntry.Status = model.ImageStatusUnchange
+		consumerEntry.Provider = consumer.Provider
+		consumerEntry.Metadata = consumer.Metadata
+		entries = append(entries, consumerEntry)
+	}
+	entries[1].RunningDigest = entry.Manifest.Digest
+	require.NoError(t, c.Resolve(entries))
+	assert.True(t, am.firing("diun"))
+	assert.False(t, am.firing("diun-2"))
+	assert.True(t, am.firing("diun-3"))
+}
+
+func TestResolveRefresh(t *testing.T) {
+	am, ts := newFakeAlertmanager(t)
+	c := newTestClient(ts.URL, 300*time.Millisecond)
+
+	entry := testEntry(t)
+	require.NoError(t, c.Send(entry))
+	entry.Status = model.ImageStatusUnchange
+	entry.RunningDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	// runs past the resolve timeout keep the alert firing while the workload
+	// runs the previous digest
+	started := time.Now()
+	for time.Since(started) < 3**c.cfg.ResolveTimeout {
+		time.Sleep(*c.cfg.ResolveTimeout / 3)
+		require.NoError(t, c.Resolve([]model.NotifEntry{entry}))
+		require.True(t, am.firing("diun"))
+	}
+
+	// alerts of workloads no longer reported end after the resolve timeout
+	other := testEntry(t)
+	other.Metadata = map[string]string{"ctn.names": "other"}
+	require.NoError(t, c.Resolve([]model.NotifEntry{other}))
+	time.Sleep(*c.cfg.ResolveTimeout)
+	assert.False(t, am.firing("diun"))
+}
+
+func TestSendError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`"start time must be before end time"`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		cfg: (&model.NotifAlertmanager{}).GetDefaults(),
+	}
+	c.cfg.Endpoint = ts.URL
+
+	err := c.Send(testEntry(t))
+	require.EqualError(t, err, `unexpected HTTP status 400: "start time must be before end time"`)
+}
+
+func TestLabelName(t *testing.T) {
+	assert.Equal(t, "ctn_names", labelName("ctn.names"))
+	assert.Equal(t, "io_kubernetes_pod_name", labelName("io.kubernetes/pod-name"))
+	assert.Equal(t, "_1password", labelName("1password"))
+}
+
+func testEntry(t *testing.T) model.NotifEntry {
+	t.Helper()
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+	image.HubLink = "https://hub.docker.com/r/crazymax/diun"
+	return model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+		Manifest: registry.Manifest{
+			Digest:  "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+			Created: new(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)),
+		},
+		Metadata: map[string]string{
+			"ctn.names": "diun",
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package amqp
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/crazy-max/diun/v4/internal/model"
@@ -76,7 +77,7 @@ func (c *Client) Send(entry model.NotifEntry) error {
 		return err
 	}
 
-	body, err := message.RenderJSON()
+	publishing, err := c.publishing(message)
 	if err != nil {
 		return err
 	}
@@ -86,8 +87,39 @@ func (c *Client) Send(entry model.NotifEntry) error {
 		q.Name,
 		false,
 		false,
-		amqp.Publishing{
+		publishing)
+}
+
+// publishing returns the message to publish. In CloudEvents binary mode,
+// context attributes are mapped to application headers.
+func (c *Client) publishing(message *msg.Client) (amqp.Publishing, error) {
+	if c.cfg.CloudEvents == "" {
+		body, err := message.RenderJSON()
+		return amqp.Publishing{
 			ContentType: "application/json",
 			Body:        body,
-		})
+		}, err
+	}
+
+	event, err := message.RenderCloudEvent()
+	if err != nil {
+		return amqp.Publishing{}, err
+	}
+	if c.cfg.CloudEvents == model.NotifCloudEventsStructured {
+		body, err := json.Marshal(event)
+		return amqp.Publishing{
+			ContentType: msg.CloudEventsContentType,
+			Body:        body,
+		}, err
+	}
+
+	headers := amqp.Table{}
+	for key, value := range event.Attributes() {
+		headers["cloudEvents_"+key] = value
+	}
+	return amqp.Publishing{
+		Headers:     headers,
+		ContentType: event.DataContentType,
+		Body:        event.Data,
+	}, nil
 }
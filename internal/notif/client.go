@@ -1,27 +1,40 @@
 package notif
 
 import (
+	"io"
 	"strings"
 
 	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/notif/alertmanager"
 	"github.com/crazy-max/diun/v4/internal/notif/amqp"
 	"github.com/crazy-max/diun/v4/internal/notif/apprise"
 	"github.com/crazy-max/diun/v4/internal/notif/discord"
 	"github.com/crazy-max/diun/v4/internal/notif/elasticsearch"
+	"github.com/crazy-max/diun/v4/internal/notif/gitea"
+	"github.com/crazy-max/diun/v4/internal/notif/github"
+	"github.com/crazy-max/diun/v4/internal/notif/gitlab"
+	"github.com/crazy-max/diun/v4/internal/notif/googlechat"
 	"github.com/crazy-max/diun/v4/internal/notif/gotify"
+	"github.com/crazy-max/diun/v4/internal/notif/kafka"
 	"github.com/crazy-max/diun/v4/internal/notif/mail"
 	"github.com/crazy-max/diun/v4/internal/notif/matrix"
+	"github.com/crazy-max/diun/v4/internal/notif/mattermost"
 	"github.com/crazy-max/diun/v4/internal/notif/mqtt"
+	"github.com/crazy-max/diun/v4/internal/notif/nats"
 	"github.com/crazy-max/diun/v4/internal/notif/notifier"
 	"github.com/crazy-max/diun/v4/internal/notif/ntfy"
+	"github.com/crazy-max/diun/v4/internal/notif/opsgenie"
+	"github.com/crazy-max/diun/v4/internal/notif/pagerduty"
 	"github.com/crazy-max/diun/v4/internal/notif/pushover"
 	"github.com/crazy-max/diun/v4/internal/notif/rocketchat"
 	"github.com/crazy-max/diun/v4/internal/notif/script"
 	"github.com/crazy-max/diun/v4/internal/notif/signalrest"
 	"github.com/crazy-max/diun/v4/internal/notif/slack"
+	"github.com/crazy-max/diun/v4/internal/notif/syslog"
 	"github.com/crazy-max/diun/v4/internal/notif/teams"
 	"github.com/crazy-max/diun/v4/internal/notif/telegram"
 	"github.com/crazy-max/diun/v4/internal/notif/webhook"
+	"github.com/crazy-max/diun/v4/internal/notif/zulip"
 	"github.com/rs/zerolog/log"
 )
 
@@ -46,6 +59,9 @@ func New(config *model.Notif, meta model.Meta) (*Client, error) {
 	}
 
 	// Add notifiers
+	if config.Alertmanager != nil {
+		c.notifiers = append(c.notifiers, alertmanager.New(config.Alertmanager, meta))
+	}
 	if config.Amqp != nil {
 		c.notifiers = append(c.notifiers, amqp.New(config.Amqp, meta))
 	}
@@ -58,21 +74,48 @@ func New(config *model.Notif, meta model.Meta) (*Client, error) {
 	if config.Elasticsearch != nil {
 		c.notifiers = append(c.notifiers, elasticsearch.New(config.Elasticsearch, meta))
 	}
+	if config.Gitea != nil {
+		c.notifiers = append(c.notifiers, gitea.New(config.Gitea, meta))
+	}
+	if config.GitHub != nil {
+		c.notifiers = append(c.notifiers, github.New(config.GitHub, meta))
+	}
+	if config.GitLab != nil {
+		c.notifiers = append(c.notifiers, gitlab.New(config.GitLab, meta))
+	}
+	if config.GoogleChat != nil {
+		c.notifiers = append(c.notifiers, googlechat.New(config.GoogleChat, meta))
+	}
 	if config.Gotify != nil {
 		c.notifiers = append(c.notifiers, gotify.New(config.Gotify, meta))
 	}
+	if config.Kafka != nil {
+		c.notifiers = append(c.notifiers, kafka.New(config.Kafka, meta))
+	}
 	if config.Mail != nil {
 		c.notifiers = append(c.notifiers, mail.New(config.Mail, meta))
 	}
 	if config.Matrix != nil {
 		c.notifiers = append(c.notifiers, matrix.New(config.Matrix, meta))
 	}
+	if config.Mattermost != nil {
+		c.notifiers = append(c.notifiers, mattermost.New(config.Mattermost, meta))
+	}
 	if config.Mqtt != nil {
 		c.notifiers = append(c.notifiers, mqtt.New(config.Mqtt, meta))
 	}
+	if config.Nats != nil {
+		c.notifiers = append(c.notifiers, nats.New(config.Nats, meta))
+	}
 	if config.Ntfy != nil {
 		c.notifiers = append(c.notifiers, ntfy.New(config.Ntfy, meta))
 	}
+	if config.Opsgenie != nil {
+		c.notifiers = append(c.notifiers, opsgenie.New(config.Opsgenie, meta))
+	}
+	if config.PagerDuty != nil {
+		c.notifiers = append(c.notifiers, pagerduty.New(config.PagerDuty, meta))
+	}
 	if config.Pushover != nil {
 		c.notifiers = append(c.notifiers, pushover.New(config.Pushover, meta))
 	}
@@ -88,6 +131,9 @@ func New(config *model.Notif, meta model.Meta) (*Client, error) {
 	if config.Slack != nil {
 		c.notifiers = append(c.notifiers, slack.New(config.Slack, meta))
 	}
+	if config.Syslog != nil {
+		c.notifiers = append(c.notifiers, syslog.New(config.Syslog, meta))
+	}
 	if config.Teams != nil {
 		c.notifiers = append(c.notifiers, teams.New(config.Teams, meta))
 	}
@@ -97,6 +143,9 @@ func New(config *model.Notif, meta model.Meta) (*Client, error) {
 	if config.Webhook != nil {
 		c.notifiers = append(c.notifiers, webhook.New(config.Webhook, meta))
 	}
+	if config.Zulip != nil {
+		c.notifiers = append(c.notifiers, zulip.New(config.Zulip, meta))
+	}
 
 	log.Debug().Msgf("%d notifier(s) created", len(c.notifiers))
 	return c, nil
@@ -112,6 +161,34 @@ func (c *Client) Send(entry model.NotifEntry) {
 	}
 }
 
+// Resolve passes the entries of a run to notifiers tracking images until they
+// are no longer reported
+func (c *Client) Resolve(entries []model.NotifEntry) {
+	for _, n := range c.notifiers {
+		r, ok := n.Handler.(notifier.Resolver)
+		if !ok {
+			continue
+		}
+		log.Debug().Msgf("Resolving %s notifications...", n.Name())
+		if err := r.Resolve(entries); err != nil {
+			log.Error().Err(err).Msgf("%s notifications resolution failed", strings.Title(n.Name())) //nolint:staticcheck // ignoring "SA1019: strings.Title is deprecated", as for our use we don't need full unicode support
+		}
+	}
+}
+
+// Close releases the connections held by notifiers
+func (c *Client) Close() {
+	for _, n := range c.notifiers {
+		closer, ok := n.Handler.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			log.Warn().Err(err).Msgf("Cannot close %s notifier", n.Name())
+		}
+	}
+}
+
 // List returns created notifiers
 func (c *Client) List() []notifier.Notifier {
 	return c.notifiers
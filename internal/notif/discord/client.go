@@ -88,25 +88,32 @@ func (c *Client) Send(entry model.NotifEntry) error {
 					Name:  "Provider",
 					Value: entry.Provider,
 				},
-				{
+			}
+			if entry.Manifest.Created != nil {
+				fields = append(fields, EmbedField{
 					Name:  "Created",
 					Value: entry.Manifest.Created.Format("Jan 02, 2006 15:04:05 UTC"),
-				},
-				{
-					Name:  "Digest",
-					Value: entry.Manifest.Digest.String(),
-				},
-				{
-					Name:  "Platform",
-					Value: entry.Manifest.Platform,
-				},
+				})
 			}
+			fields = append(fields, EmbedField{
+				Name:  "Digest",
+				Value: entry.Manifest.Digest.String(),
+			}, EmbedField{
+				Name:  "Platform",
+				Value: entry.Manifest.Platform,
+			})
 			if len(entry.Image.HubLink) > 0 {
 				fields = append(fields, EmbedField{
 					Name:  "HubLink",
 					Value: entry.Image.HubLink,
 				})
 			}
+			if len(entry.Manifest.ArtifactType) > 0 {
+				fields = append(fields, EmbedField{
+					Name:  "Artifact type",
+					Value: entry.Manifest.ArtifactType,
+				})
+			}
 		}
 		embeds = []Embed{
 			{
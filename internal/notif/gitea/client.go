@@ -0,0 +1,214 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/notif/issue"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+)
+
+const (
+	perPage = 50
+
+	// labelColor is the color of the labels created by Diun
+	labelColor = "#1d76db"
+)
+
+// Client represents an active Gitea issues notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifGitea
+	meta model.Meta
+}
+
+// New creates a new Gitea issues notification instance
+func New(config *model.NotifGitea, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "gitea"
+}
+
+// Send creates or comments the Gitea issue of the image of an entry
+// https://gitea.com/api/swagger#/issue
+func (c *Client) Send(entry model.NotifEntry) error {
+	t, err := c.tracker()
+	if err != nil {
+		return err
+	}
+	return issue.Send(t, c.options(), c.meta, entry)
+}
+
+// Resolve closes the Gitea issues of images no longer reported
+func (c *Client) Resolve(entries []model.NotifEntry) error {
+	t, err := c.tracker()
+	if err != nil {
+		return err
+	}
+	return issue.Resolve(t, c.options(), entries)
+}
+
+func (c *Client) options() issue.Options {
+	return issue.Options{
+		Label:         c.cfg.Label,
+		Labels:        c.cfg.Labels,
+		Assignees:     c.cfg.Assignees,
+		CloseResolved: *c.cfg.CloseResolved,
+		TemplateTitle: c.cfg.TemplateTitle,
+		TemplateBody:  c.cfg.TemplateBody,
+	}
+}
+
+func (c *Client) tracker() (*tracker, error) {
+	token, err := secret.GetSecret(c.cfg.Token, c.cfg.TokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot retrieve token secret for Gitea notifier")
+	}
+
+	hc, err := httputil.NewClient(c.cfg.Proxy, c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create HTTP client for Gitea notifier")
+	}
+
+	header := http.Header{}
+	if len(token) > 0 {
+		header.Set("Authorization", "token "+token)
+	}
+
+	return &tracker{
+		api: &issue.API{
+			Client:    hc,
+			Timeout:   *c.cfg.Timeout,
+			Header:    header,
+			UserAgent: c.meta.UserAgent,
+		},
+		baseURL: fmt.Sprintf("%s/api/v1/repos/%s", strings.TrimSuffix(c.cfg.Endpoint, "/"), c.cfg.Repository),
+	}, nil
+}
+
+type tracker struct {
+	api     *issue.API
+	baseURL string
+}
+
+type label struct {
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+func (t *tracker) OpenIssues(label string) ([]issue.Issue, error) {
+	var issues []issue.Issue
+	for page := 1; ; page++ {
+		var res []struct {
+			Number int64  `json:"number"`
+			Body   string `json:"body"`
+		}
+		if err := t.api.Do("GET", fmt.Sprintf("%s/issues?state=open&type=issues&labels=%s&limit=%d&page=%d", t.baseURL, url.QueryEscape(label), perPage, page), nil, &res); err != nil {
+			return nil, err
+		}
+		for _, r := range res {
+			issues = append(issues, issue.Issue{Number: r.Number, Body: r.Body})
+		}
+		if len(res) < perPage {
+			return issues, nil
+		}
+	}
+}
+
+func (t *tracker) Create(title, body string, labels, assignees []string) error {
+	labelIDs, err := t.labelIDs(labels)
+	if err != nil {
+		return err
+	}
+	return t.api.Do("POST", t.baseURL+"/issues", struct {
+		Title     string   `json:"title"`
+		Body      string   `json:"body"`
+		Labels    []int64  `json:"labels,omitempty"`
+		Assignees []string `json:"assignees,omitempty"`
+	}{
+		Title:     title,
+		Body:      body,
+		Labels:    labelIDs,
+		Assignees: assignees,
+	}, nil)
+}
+
+func (t *tracker) Comment(number int64, body string) error {
+	return t.api.Do("POST", fmt.Sprintf("%s/issues/%d/comments", t.baseURL, number), struct {
+		Body string `json:"body"`
+	}{
+		Body: body,
+	}, nil)
+}
+
+func (t *tracker) Edit(number int64, body string) error {
+	return t.api.Do("PATCH", fmt.Sprintf("%s/issues/%d", t.baseURL, number), struct {
+		Body string `json:"body"`
+	}{
+		Body: body,
+	}, nil)
+}
+
+func (t *tracker) Close(number int64) error {
+	return t.api.Do("PATCH", fmt.Sprintf("%s/issues/%d", t.baseURL, number), struct {
+		State string `json:"state"`
+	}{
+		State: "closed",
+	}, nil)
+}
+
+// labelIDs returns the IDs of the given label names as Gitea only accepts
+// label IDs. Missing labels are created.
+func (t *tracker) labelIDs(names []string) ([]int64, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	existing := make(map[string]int64)
+	for page := 1; ; page++ {
+		var res []label
+		if err := t.api.Do("GET", fmt.Sprintf("%s/labels?limit=%d&page=%d", t.baseURL, perPage, page), nil, &res); err != nil {
+			return nil, errors.Wrap(err, "cannot list labels")
+		}
+		for _, l := range res {
+			existing[l.Name] = l.ID
+		}
+		if len(res) < perPage {
+			break
+		}
+	}
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		if id, ok := existing[name]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		var created label
+		if err := t.api.Do("POST", t.baseURL+"/labels", struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		}{
+			Name:  name,
+			Color: labelColor,
+		}, &created); err != nil {
+			return nil, errors.Wrapf(err, "cannot create label %s", name)
+		}
+		ids = append(ids, created.ID)
+	}
+	return ids, nil
+}
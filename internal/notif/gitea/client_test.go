@@ -0,0 +1,132 @@
+package gitea
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type gtIssue struct {
+	Number    int64    `json:"number"`
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	State     string   `json:"state"`
+	Labels    []int64  `json:"labels"`
+	Assignees []string `json:"assignees"`
+	comments  []string
+}
+
+func TestSendResolve(t *testing.T) {
+	var issues []*gtIssue
+	labels := []label{{ID: 1, Name: "diun"}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/crazy-max/diun/labels", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(labels)
+	})
+	mux.HandleFunc("POST /api/v1/repos/crazy-max/diun/labels", func(w http.ResponseWriter, r *http.Request) {
+		var l label
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&l))
+		l.ID = int64(len(labels) + 1)
+		labels = append(labels, l)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(l)
+	})
+	mux.HandleFunc("GET /api/v1/repos/crazy-max/diun/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token t0k3n", r.Header.Get("Authorization"))
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		assert.Equal(t, "issues", r.URL.Query().Get("type"))
+		assert.Equal(t, "diun", r.URL.Query().Get("labels"))
+		res := []*gtIssue{}
+		for _, i := range issues {
+			if i.State == "open" {
+				res = append(res, i)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+	mux.HandleFunc("POST /api/v1/repos/crazy-max/diun/issues", func(w http.ResponseWriter, r *http.Request) {
+		var i gtIssue
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&i))
+		i.Number = int64(len(issues) + 1)
+		i.State = "open"
+		issues = append(issues, &i)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(i)
+	})
+	mux.HandleFunc("POST /api/v1/repos/crazy-max/diun/issues/{number}/comments", func(w http.ResponseWriter, r *http.Request) {
+		var c struct {
+			Body string `json:"body"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&c))
+		n, _ := strconv.Atoi(r.PathValue("number"))
+		issues[n-1].comments = append(issues[n-1].comments, c.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("PATCH /api/v1/repos/crazy-max/diun/issues/{number}", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.PathValue("number"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(issues[n-1]))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(issues[n-1])
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	c := Client{
+		cfg: &model.NotifGitea{
+			Endpoint:      ts.URL + "/",
+			Token:         "t0k3n",
+			Repository:    "crazy-max/diun",
+			Label:         "diun",
+			Labels:        []string{"{{ .Entry.Metadata.team }}"},
+			Assignees:     []string{"{{ .Entry.Metadata.owner }}"},
+			CloseResolved: new(true),
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: model.NotifDefaultTemplateTitle,
+			TemplateBody:  "Provider {{ .Entry.Provider }}",
+		},
+	}
+
+	entry := testEntry(t)
+	require.NoError(t, c.Send(entry))
+	require.Len(t, issues, 1)
+	assert.Equal(t, "docker.io/crazymax/diun:latest has been updated", issues[0].Title)
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:providers=docker -->\nProvider docker", issues[0].Body)
+	assert.Equal(t, []int64{1, 2}, issues[0].Labels)
+	assert.Equal(t, "infra", labels[1].Name)
+	assert.Equal(t, []string{"crazy-max"}, issues[0].Assignees)
+
+	entry.Manifest.Digest = "sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031"
+	require.NoError(t, c.Send(entry))
+	require.Len(t, issues, 1)
+	assert.Equal(t, []string{"Provider docker"}, issues[0].comments)
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:digest=sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031 -->\n<!-- diun:providers=docker -->\nProvider docker", issues[0].Body)
+
+	entry.Image, _ = registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:4"})
+	require.NoError(t, c.Resolve([]model.NotifEntry{entry}))
+	assert.Equal(t, "closed", issues[0].State)
+	assert.Len(t, issues[0].comments, 2)
+}
+
+func testEntry(t *testing.T) model.NotifEntry {
+	t.Helper()
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+	return model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+		Metadata: map[string]string{
+			"team":  "infra",
+			"owner": "crazy-max",
+		},
+	}
+}
@@ -0,0 +1,166 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/notif/issue"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+)
+
+const perPage = 100
+
+// Client represents an active GitHub issues notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifGitHub
+	meta model.Meta
+}
+
+// New creates a new GitHub issues notification instance
+func New(config *model.NotifGitHub, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "github"
+}
+
+// Send creates or comments the GitHub issue of the image of an entry
+// https://docs.github.com/en/rest/issues/issues
+func (c *Client) Send(entry model.NotifEntry) error {
+	t, err := c.tracker()
+	if err != nil {
+		return err
+	}
+	return issue.Send(t, c.options(), c.meta, entry)
+}
+
+// Resolve closes the GitHub issues of images no longer reported
+func (c *Client) Resolve(entries []model.NotifEntry) error {
+	t, err := c.tracker()
+	if err != nil {
+		return err
+	}
+	return issue.Resolve(t, c.options(), entries)
+}
+
+func (c *Client) options() issue.Options {
+	return issue.Options{
+		Label:         c.cfg.Label,
+		Labels:        c.cfg.Labels,
+		Assignees:     c.cfg.Assignees,
+		CloseResolved: *c.cfg.CloseResolved,
+		TemplateTitle: c.cfg.TemplateTitle,
+		TemplateBody:  c.cfg.TemplateBody,
+	}
+}
+
+func (c *Client) tracker() (*tracker, error) {
+	token, err := secret.GetSecret(c.cfg.Token, c.cfg.TokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot retrieve token secret for GitHub notifier")
+	}
+
+	hc, err := httputil.NewClient(c.cfg.Proxy, c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create HTTP client for GitHub notifier")
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if len(token) > 0 {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	return &tracker{
+		api: &issue.API{
+			Client:    hc,
+			Timeout:   *c.cfg.Timeout,
+			Header:    header,
+			UserAgent: c.meta.UserAgent,
+		},
+		baseURL: fmt.Sprintf("%s/repos/%s", strings.TrimSuffix(c.cfg.Endpoint, "/"), c.cfg.Repository),
+	}, nil
+}
+
+type tracker struct {
+	api     *issue.API
+	baseURL string
+}
+
+func (t *tracker) OpenIssues(label string) ([]issue.Issue, error) {
+	var issues []issue.Issue
+	for page := 1; ; page++ {
+		var res []struct {
+			Number      int64  `json:"number"`
+			Body        string `json:"body"`
+			PullRequest any    `json:"pull_request"`
+		}
+		if err := t.api.Do("GET", fmt.Sprintf("%s/issues?state=open&labels=%s&per_page=%d&page=%d", t.baseURL, url.QueryEscape(label), perPage, page), nil, &res); err != nil {
+			return nil, err
+		}
+		for _, r := range res {
+			if r.PullRequest != nil {
+				continue
+			}
+			issues = append(issues, issue.Issue{Number: r.Number, Body: r.Body})
+		}
+		if len(res) < perPage {
+			return issues, nil
+		}
+	}
+}
+
+func (t *tracker) Create(title, body string, labels, assignees []string) error {
+	return t.api.Do("POST", t.baseURL+"/issues", struct {
+		Title     string   `json:"title"`
+		Body      string   `json:"body"`
+		Labels    []string `json:"labels,omitempty"`
+		Assignees []string `json:"assignees,omitempty"`
+	}{
+		Title:     title,
+		Body:      body,
+		Labels:    labels,
+		Assignees: assignees,
+	}, nil)
+}
+
+func (t *tracker) Comment(number int64, body string) error {
+	return t.api.Do("POST", fmt.Sprintf("%s/issues/%d/comments", t.baseURL, number), struct {
+		Body string `json:"body"`
+	}{
+		Body: body,
+	}, nil)
+}
+
+func (t *tracker) Edit(number int64, body string) error {
+	return t.api.Do("PATCH", fmt.Sprintf("%s/issues/%d", t.baseURL, number), struct {
+		Body string `json:"body"`
+	}{
+		Body: body,
+	}, nil)
+}
+
+func (t *tracker) Close(number int64) error {
+	return t.api.Do("PATCH", fmt.Sprintf("%s/issues/%d", t.baseURL, number), struct {
+		State       string `json:"state"`
+		StateReason string `json:"state_reason"`
+	}{
+		State:       "closed",
+		StateReason: "completed",
+	}, nil)
+}
@@ -0,0 +1,126 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ghIssue struct {
+	Number      int64    `json:"number"`
+	Title       string   `json:"title"`
+	Body        string   `json:"body"`
+	State       string   `json:"state"`
+	StateReason string   `json:"state_reason,omitempty"`
+	Labels      []string `json:"labels"`
+	Assignees   []string `json:"assignees"`
+	PullRequest any      `json:"pull_request,omitempty"`
+	comments    []string
+}
+
+func TestSendResolve(t *testing.T) {
+	issues := []*ghIssue{
+		{Number: 1, Body: "<!-- diun:image=docker.io/crazymax/diun:latest -->", State: "open", PullRequest: map[string]string{}},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/crazy-max/diun/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer t0k3n", r.Header.Get("Authorization"))
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		assert.Equal(t, "diun", r.URL.Query().Get("labels"))
+		var res []*ghIssue
+		for _, i := range issues {
+			if i.State == "open" {
+				res = append(res, i)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+	mux.HandleFunc("POST /repos/crazy-max/diun/issues", func(w http.ResponseWriter, r *http.Request) {
+		var i ghIssue
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&i))
+		i.Number = int64(len(issues) + 1)
+		i.State = "open"
+		issues = append(issues, &i)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(i)
+	})
+	mux.HandleFunc("POST /repos/crazy-max/diun/issues/{number}/comments", func(w http.ResponseWriter, r *http.Request) {
+		var c struct {
+			Body string `json:"body"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&c))
+		n, _ := strconv.Atoi(r.PathValue("number"))
+		issues[n-1].comments = append(issues[n-1].comments, c.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("PATCH /repos/crazy-max/diun/issues/{number}", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.PathValue("number"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(issues[n-1]))
+		_ = json.NewEncoder(w).Encode(issues[n-1])
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	c := Client{
+		cfg: &model.NotifGitHub{
+			Endpoint:      ts.URL,
+			Token:         "t0k3n",
+			Repository:    "crazy-max/diun",
+			Label:         "diun",
+			Labels:        []string{"{{ .Entry.Metadata.team }}"},
+			Assignees:     []string{"{{ .Entry.Metadata.owner }}"},
+			CloseResolved: new(true),
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: model.NotifDefaultTemplateTitle,
+			TemplateBody:  "Provider {{ .Entry.Provider }}",
+		},
+	}
+
+	entry := testEntry(t)
+	require.NoError(t, c.Send(entry))
+	require.Len(t, issues, 2)
+	assert.Equal(t, "docker.io/crazymax/diun:latest has been updated", issues[1].Title)
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:providers=docker -->\nProvider docker", issues[1].Body)
+	assert.Equal(t, []string{"diun", "infra"}, issues[1].Labels)
+	assert.Equal(t, []string{"crazy-max"}, issues[1].Assignees)
+
+	entry.Manifest.Digest = "sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031"
+	require.NoError(t, c.Send(entry))
+	require.Len(t, issues, 2)
+	assert.Equal(t, []string{"Provider docker"}, issues[1].comments)
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:digest=sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031 -->\n<!-- diun:providers=docker -->\nProvider docker", issues[1].Body)
+
+	require.NoError(t, c.Resolve([]model.NotifEntry{entry}))
+	assert.Equal(t, "open", issues[1].State)
+
+	entry.Image, _ = registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:4"})
+	require.NoError(t, c.Resolve([]model.NotifEntry{entry}))
+	assert.Equal(t, "closed", issues[1].State)
+	assert.Equal(t, "completed", issues[1].StateReason)
+	assert.Len(t, issues[1].comments, 2)
+	assert.Equal(t, "open", issues[0].State)
+}
+
+func testEntry(t *testing.T) model.NotifEntry {
+	t.Helper()
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+	return model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+		Metadata: map[string]string{
+			"team":  "infra",
+			"owner": "crazy-max",
+		},
+	}
+}
@@ -0,0 +1,184 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/notif/issue"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+)
+
+const perPage = 100
+
+// Client represents an active GitLab issues notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifGitLab
+	meta model.Meta
+}
+
+// New creates a new GitLab issues notification instance
+func New(config *model.NotifGitLab, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "gitlab"
+}
+
+// Send creates or comments the GitLab issue of the image of an entry
+// https://docs.gitlab.com/api/issues/
+func (c *Client) Send(entry model.NotifEntry) error {
+	t, err := c.tracker()
+	if err != nil {
+		return err
+	}
+	return issue.Send(t, c.options(), c.meta, entry)
+}
+
+// Resolve closes the GitLab issues of images no longer reported
+func (c *Client) Resolve(entries []model.NotifEntry) error {
+	t, err := c.tracker()
+	if err != nil {
+		return err
+	}
+	return issue.Resolve(t, c.options(), entries)
+}
+
+func (c *Client) options() issue.Options {
+	return issue.Options{
+		Label:         c.cfg.Label,
+		Labels:        c.cfg.Labels,
+		Assignees:     c.cfg.Assignees,
+		CloseResolved: *c.cfg.CloseResolved,
+		TemplateTitle: c.cfg.TemplateTitle,
+		TemplateBody:  c.cfg.TemplateBody,
+	}
+}
+
+func (c *Client) tracker() (*tracker, error) {
+	token, err := secret.GetSecret(c.cfg.Token, c.cfg.TokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot retrieve token secret for GitLab notifier")
+	}
+
+	hc, err := httputil.NewClient(c.cfg.Proxy, c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create HTTP client for GitLab notifier")
+	}
+
+	header := http.Header{}
+	if len(token) > 0 {
+		header.Set("PRIVATE-TOKEN", token)
+	}
+
+	apiURL := strings.TrimSuffix(c.cfg.Endpoint, "/") + "/api/v4"
+	return &tracker{
+		api: &issue.API{
+			Client:    hc,
+			Timeout:   *c.cfg.Timeout,
+			Header:    header,
+			UserAgent: c.meta.UserAgent,
+		},
+		apiURL:     apiURL,
+		projectURL: fmt.Sprintf("%s/projects/%s", apiURL, url.PathEscape(c.cfg.Project)),
+	}, nil
+}
+
+type tracker struct {
+	api        *issue.API
+	apiURL     string
+	projectURL string
+}
+
+func (t *tracker) OpenIssues(label string) ([]issue.Issue, error) {
+	var issues []issue.Issue
+	for page := 1; ; page++ {
+		var res []struct {
+			IID         int64  `json:"iid"`
+			Description string `json:"description"`
+		}
+		if err := t.api.Do("GET", fmt.Sprintf("%s/issues?state=opened&labels=%s&per_page=%d&page=%d", t.projectURL, url.QueryEscape(label), perPage, page), nil, &res); err != nil {
+			return nil, err
+		}
+		for _, r := range res {
+			issues = append(issues, issue.Issue{Number: r.IID, Body: r.Description})
+		}
+		if len(res) < perPage {
+			return issues, nil
+		}
+	}
+}
+
+func (t *tracker) Create(title, body string, labels, assignees []string) error {
+	assigneeIDs, err := t.userIDs(assignees)
+	if err != nil {
+		return err
+	}
+	return t.api.Do("POST", t.projectURL+"/issues", struct {
+		Title       string  `json:"title"`
+		Description string  `json:"description"`
+		Labels      string  `json:"labels,omitempty"`
+		AssigneeIDs []int64 `json:"assignee_ids,omitempty"`
+	}{
+		Title:       title,
+		Description: body,
+		Labels:      strings.Join(labels, ","),
+		AssigneeIDs: assigneeIDs,
+	}, nil)
+}
+
+func (t *tracker) Comment(number int64, body string) error {
+	return t.api.Do("POST", fmt.Sprintf("%s/issues/%d/notes", t.projectURL, number), struct {
+		Body string `json:"body"`
+	}{
+		Body: body,
+	}, nil)
+}
+
+func (t *tracker) Edit(number int64, body string) error {
+	return t.api.Do("PUT", fmt.Sprintf("%s/issues/%d", t.projectURL, number), struct {
+		Description string `json:"description"`
+	}{
+		Description: body,
+	}, nil)
+}
+
+func (t *tracker) Close(number int64) error {
+	return t.api.Do("PUT", fmt.Sprintf("%s/issues/%d", t.projectURL, number), struct {
+		StateEvent string `json:"state_event"`
+	}{
+		StateEvent: "close",
+	}, nil)
+}
+
+// userIDs returns the IDs of the given usernames as GitLab only accepts
+// assignee IDs
+func (t *tracker) userIDs(usernames []string) ([]int64, error) {
+	var ids []int64
+	for _, username := range usernames {
+		var res []struct {
+			ID int64 `json:"id"`
+		}
+		if err := t.api.Do("GET", fmt.Sprintf("%s/users?username=%s", t.apiURL, url.QueryEscape(username)), nil, &res); err != nil {
+			return nil, errors.Wrapf(err, "cannot get user %s", username)
+		}
+		if len(res) == 0 {
+			return nil, errors.Errorf("user %s not found", username)
+		}
+		ids = append(ids, res[0].ID)
+	}
+	return ids, nil
+}
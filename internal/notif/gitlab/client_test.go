@@ -0,0 +1,136 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type glIssue struct {
+	IID         int64   `json:"iid"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	State       string  `json:"state"`
+	Labels      string  `json:"labels"`
+	AssigneeIDs []int64 `json:"assignee_ids"`
+	StateEvent  string  `json:"state_event,omitempty"`
+	notes       []string
+}
+
+func TestSendResolve(t *testing.T) {
+	var issues []*glIssue
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("username") == "crazy-max" {
+			_, _ = w.Write([]byte(`[{"id":42,"username":"crazy-max"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "group/diun", r.PathValue("project"))
+		assert.Equal(t, "t0k3n", r.Header.Get("PRIVATE-TOKEN"))
+		assert.Equal(t, "opened", r.URL.Query().Get("state"))
+		assert.Equal(t, "diun", r.URL.Query().Get("labels"))
+		res := []*glIssue{}
+		for _, i := range issues {
+			if i.State == "opened" {
+				res = append(res, i)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+	mux.HandleFunc("POST /api/v4/projects/{project}/issues", func(w http.ResponseWriter, r *http.Request) {
+		var i glIssue
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&i))
+		i.IID = int64(len(issues) + 1)
+		i.State = "opened"
+		issues = append(issues, &i)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(i)
+	})
+	mux.HandleFunc("POST /api/v4/projects/{project}/issues/{iid}/notes", func(w http.ResponseWriter, r *http.Request) {
+		var n struct {
+			Body string `json:"body"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&n))
+		iid, _ := strconv.Atoi(r.PathValue("iid"))
+		issues[iid-1].notes = append(issues[iid-1].notes, n.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("PUT /api/v4/projects/{project}/issues/{iid}", func(w http.ResponseWriter, r *http.Request) {
+		iid, _ := strconv.Atoi(r.PathValue("iid"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(issues[iid-1]))
+		if issues[iid-1].StateEvent == "close" {
+			issues[iid-1].State = "closed"
+		}
+		_ = json.NewEncoder(w).Encode(issues[iid-1])
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	c := Client{
+		cfg: &model.NotifGitLab{
+			Endpoint:      ts.URL,
+			Token:         "t0k3n",
+			Project:       "group/diun",
+			Label:         "diun",
+			Labels:        []string{"{{ .Entry.Metadata.team }}"},
+			Assignees:     []string{"{{ .Entry.Metadata.owner }}"},
+			CloseResolved: new(true),
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: model.NotifDefaultTemplateTitle,
+			TemplateBody:  "Provider {{ .Entry.Provider }}",
+		},
+	}
+
+	entry := testEntry(t)
+	require.NoError(t, c.Send(entry))
+	require.Len(t, issues, 1)
+	assert.Equal(t, "docker.io/crazymax/diun:latest has been updated", issues[0].Title)
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:providers=docker -->\nProvider docker", issues[0].Description)
+	assert.Equal(t, "diun,infra", issues[0].Labels)
+	assert.Equal(t, []int64{42}, issues[0].AssigneeIDs)
+
+	entry.Manifest.Digest = "sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031"
+	require.NoError(t, c.Send(entry))
+	require.Len(t, issues, 1)
+	assert.Equal(t, []string{"Provider docker"}, issues[0].notes)
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:digest=sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031 -->\n<!-- diun:providers=docker -->\nProvider docker", issues[0].Description)
+
+	require.NoError(t, c.Resolve([]model.NotifEntry{}))
+	assert.Equal(t, "opened", issues[0].State)
+
+	entry.Image, _ = registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:4"})
+	require.NoError(t, c.Resolve([]model.NotifEntry{entry}))
+	assert.Equal(t, "closed", issues[0].State)
+
+	entry.Metadata["owner"] = "unknown"
+	err := c.Send(entry)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "user unknown not found")
+}
+
+func testEntry(t *testing.T) model.NotifEntry {
+	t.Helper()
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+	return model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+		Metadata: map[string]string{
+			"team":  "infra",
+			"owner": "crazy-max",
+		},
+	}
+}
@@ -0,0 +1,201 @@
+package googlechat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+)
+
+// Client represents an active Google Chat notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifGoogleChat
+	meta model.Meta
+}
+
+// New creates a new Google Chat notification instance
+func New(config *model.NotifGoogleChat, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "googlechat"
+}
+
+// Send creates and sends a Google Chat notification with an entry
+// https://developers.google.com/workspace/chat/api/reference/rest/v1/cards
+func (c *Client) Send(entry model.NotifEntry) error {
+	webhookURL, err := secret.GetSecret(c.cfg.WebhookURL, c.cfg.WebhookURLFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve webhook URL for Google Chat notifier")
+	}
+
+	message, err := msg.New(msg.Options{
+		Meta:          c.meta,
+		Entry:         entry,
+		TemplateTitle: c.cfg.TemplateTitle,
+		TemplateBody:  c.cfg.TemplateBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	title, body, err := message.RenderMarkdown()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(c.message(entry, string(title), string(body)))
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return err
+	}
+	if len(c.cfg.ThreadKey) > 0 {
+		threadKey, err := message.RenderTemplate("threadKey", c.cfg.ThreadKey)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		q.Set("threadKey", string(threadKey))
+		q.Set("messageReplyOption", "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+		u.RawQuery = q.Encode()
+	}
+
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	defer func() { cancelCause(errors.WithStack(context.Canceled)) }()
+	timeoutCtx, cancel := context.WithTimeoutCause(cancelCtx, *c.cfg.Timeout, errors.WithStack(context.DeadlineExceeded))
+	defer cancel()
+
+	hc, err := httputil.NewClient(c.cfg.Proxy, c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return errors.Wrap(err, "cannot create HTTP client for Google Chat notifier")
+	}
+
+	req, err := http.NewRequestWithContext(timeoutCtx, "POST", u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("User-Agent", c.meta.UserAgent)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("unexpected HTTP status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+func (c *Client) message(entry model.NotifEntry, title, body string) Message {
+	sections := []Section{
+		{
+			Widgets: []Widget{
+				{
+					TextParagraph: &TextParagraph{
+						Text: body,
+					},
+				},
+			},
+		},
+	}
+
+	if *c.cfg.RenderFields {
+		var created string
+		if entry.Manifest.Created != nil {
+			created = entry.Manifest.Created.Format("Jan 02, 2006 15:04:05 UTC")
+		}
+		var fields []Widget
+		for _, field := range [][2]string{
+			{"Hostname", c.meta.Hostname},
+			{"Provider", entry.Provider},
+			{"Created", created},
+			{"Digest", entry.Manifest.Digest.String()},
+			{"Platform", entry.Manifest.Platform},
+			{"Artifact type", entry.Manifest.ArtifactType},
+		} {
+			if len(field[1]) == 0 {
+				continue
+			}
+			fields = append(fields, Widget{
+				DecoratedText: &DecoratedText{
+					TopLabel: field[0],
+					Text:     field[1],
+					WrapText: true,
+				},
+			})
+		}
+		sections = append(sections, Section{
+			Header:                    "Details",
+			Collapsible:               true,
+			UncollapsibleWidgetsCount: 2,
+			Widgets:                   fields,
+		})
+	}
+
+	if len(entry.Image.HubLink) > 0 {
+		sections = append(sections, Section{
+			Widgets: []Widget{
+				{
+					ButtonList: &ButtonList{
+						Buttons: []Button{
+							{
+								Text: "Open",
+								OnClick: OnClick{
+									OpenLink: OpenLink{
+										URL: entry.Image.HubLink,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return Message{
+		CardsV2: []CardWithID{
+			{
+				CardID: "diun",
+				Card: Card{
+					Header: CardHeader{
+						Title:     title,
+						Subtitle:  fmt.Sprintf("%s © %d %s %s", c.meta.Author, time.Now().Year(), c.meta.Name, c.meta.Version),
+						ImageURL:  c.meta.Logo,
+						ImageType: "CIRCLE",
+					},
+					Sections: sections,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package googlechat
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	var query map[string]string
+	var message Message
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{
+			"key":                r.URL.Query().Get("key"),
+			"threadKey":          r.URL.Query().Get("threadKey"),
+			"messageReplyOption": r.URL.Query().Get("messageReplyOption"),
+		}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifGoogleChat{
+			WebhookURL:    ts.URL + "/v1/spaces/AAAA/messages?key=k3y",
+			ThreadKey:     "{{ .Entry.Image.Name }}",
+			RenderFields:  new(true),
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: model.NotifDefaultTemplateTitle,
+			TemplateBody:  model.NotifGoogleChatDefaultTemplateBody,
+		},
+		meta: model.Meta{
+			Hostname:  "myserver",
+			UserAgent: "diun-test",
+		},
+	}
+
+	require.NoError(t, c.Send(model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+		Manifest: registry.Manifest{
+			Name:     "docker.io/crazymax/diun",
+			Tag:      "latest",
+			Digest:   "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+			Platform: "linux/amd64",
+			Created:  new(time.Date(2026, 5, 24, 12, 34, 56, 0, time.UTC)),
+		},
+	}))
+
+	assert.Equal(t, "k3y", query["key"])
+	assert.Equal(t, "docker.io/crazymax/diun", query["threadKey"])
+	assert.Equal(t, "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD", query["messageReplyOption"])
+
+	require.Len(t, message.CardsV2, 1)
+	card := message.CardsV2[0].Card
+	assert.Equal(t, "docker.io/crazymax/diun:latest has been updated", card.Header.Title)
+	require.Len(t, card.Sections, 3)
+	require.NotNil(t, card.Sections[0].Widgets[0].TextParagraph)
+	assert.Contains(t, card.Sections[0].Widgets[0].TextParagraph.Text, `<a href="https://hub.docker.com/r/crazymax/diun"><b>docker.io/crazymax/diun:latest</b></a>`)
+	require.NotNil(t, card.Sections[1].Widgets[0].DecoratedText)
+	assert.Equal(t, "Hostname", card.Sections[1].Widgets[0].DecoratedText.TopLabel)
+	assert.Equal(t, "myserver", card.Sections[1].Widgets[0].DecoratedText.Text)
+	require.NotNil(t, card.Sections[2].Widgets[0].ButtonList)
+	assert.Equal(t, "https://hub.docker.com/r/crazymax/diun", card.Sections[2].Widgets[0].ButtonList.Buttons[0].OnClick.OpenLink.URL)
+}
+
+func TestMessageUnknownCreated(t *testing.T) {
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifGoogleChat{
+			RenderFields: new(true),
+		},
+	}
+	message := c.message(model.NotifEntry{
+		Status:   model.ImageStatusNew,
+		Provider: "docker",
+		Image:    image,
+		Manifest: registry.Manifest{
+			Digest: "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+		},
+	}, "title", "body")
+
+	require.Len(t, message.CardsV2, 1)
+	require.True(t, len(message.CardsV2[0].Card.Sections) > 1)
+	for _, widget := range message.CardsV2[0].Card.Sections[1].Widgets {
+		require.NotNil(t, widget.DecoratedText)
+		assert.NotEqual(t, "Created", widget.DecoratedText.TopLabel)
+	}
+}
+
+func TestSendError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":400,"message":"Invalid JSON payload","status":"INVALID_ARGUMENT"}}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		cfg: &model.NotifGoogleChat{
+			WebhookURL:    ts.URL,
+			RenderFields:  new(false),
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: model.NotifDefaultTemplateTitle,
+			TemplateBody:  model.NotifGoogleChatDefaultTemplateBody,
+		},
+	}
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	err = c.Send(model.NotifEntry{
+		Status: model.ImageStatusNew,
+		Image:  image,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected HTTP status 400")
+}
@@ -0,0 +1,74 @@
+package googlechat
+
+// Message contains all the information for a message
+type Message struct {
+	CardsV2 []CardWithID `json:"cardsV2"`
+}
+
+// CardWithID holds a card and its identifier
+type CardWithID struct {
+	CardID string `json:"cardId"`
+	Card   Card   `json:"card"`
+}
+
+// Card contains all the information for a card
+type Card struct {
+	Header   CardHeader `json:"header"`
+	Sections []Section  `json:"sections,omitempty"`
+}
+
+// CardHeader contains information for the header of a card
+type CardHeader struct {
+	Title     string `json:"title"`
+	Subtitle  string `json:"subtitle,omitempty"`
+	ImageURL  string `json:"imageUrl,omitempty"`
+	ImageType string `json:"imageType,omitempty"`
+}
+
+// Section contains a list of widgets
+type Section struct {
+	Header                    string   `json:"header,omitempty"`
+	Collapsible               bool     `json:"collapsible,omitempty"`
+	UncollapsibleWidgetsCount int      `json:"uncollapsibleWidgetsCount,omitempty"`
+	Widgets                   []Widget `json:"widgets"`
+}
+
+// Widget contains one of the supported widgets
+type Widget struct {
+	TextParagraph *TextParagraph `json:"textParagraph,omitempty"`
+	DecoratedText *DecoratedText `json:"decoratedText,omitempty"`
+	ButtonList    *ButtonList    `json:"buttonList,omitempty"`
+}
+
+// TextParagraph contains information for a text paragraph widget
+type TextParagraph struct {
+	Text string `json:"text"`
+}
+
+// DecoratedText contains information for a decorated text widget
+type DecoratedText struct {
+	TopLabel string `json:"topLabel,omitempty"`
+	Text     string `json:"text"`
+	WrapText bool   `json:"wrapText,omitempty"`
+}
+
+// ButtonList contains a list of buttons
+type ButtonList struct {
+	Buttons []Button `json:"buttons"`
+}
+
+// Button contains information for a button
+type Button struct {
+	Text    string  `json:"text"`
+	OnClick OnClick `json:"onClick"`
+}
+
+// OnClick contains the action of a button
+type OnClick struct {
+	OpenLink OpenLink `json:"openLink"`
+}
+
+// OpenLink contains the link to open
+type OpenLink struct {
+	URL string `json:"url"`
+}
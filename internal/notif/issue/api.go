@@ -0,0 +1,71 @@
+package issue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// API is a JSON REST client shared by the trackers
+type API struct {
+	Client    http.Client
+	Timeout   time.Duration
+	Header    http.Header
+	UserAgent string
+}
+
+// Do sends a request with the JSON encoded input and decodes the response
+// into the output if not nil
+func (a *API) Do(method, url string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	defer func() { cancelCause(errors.WithStack(context.Canceled)) }()
+	timeoutCtx, cancel := context.WithTimeoutCause(cancelCtx, a.Timeout, errors.WithStack(context.DeadlineExceeded))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(timeoutCtx, method, url, body)
+	if err != nil {
+		return err
+	}
+	for key, values := range a.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", a.UserAgent)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("unexpected HTTP status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrapf(err, "cannot decode JSON body response for HTTP %d %s status", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return nil
+}
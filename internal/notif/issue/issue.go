@@ -0,0 +1,274 @@
+package issue
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+var (
+	markerRegexp          = regexp.MustCompile(`<!-- diun:image=(\S+) -->`)
+	digestMarkerRegexp    = regexp.MustCompile(`<!-- diun:digest=(\S+) -->`)
+	providersMarkerRegexp = regexp.MustCompile(`<!-- diun:providers=(\S+) -->`)
+)
+
+// Issue represents an open issue of a tracker
+type Issue struct {
+	Number int64
+	Body   string
+}
+
+// Tracker is implemented by the issue trackers
+type Tracker interface {
+	// OpenIssues returns the open issues with the given label
+	OpenIssues(label string) ([]Issue, error)
+	// Create creates an issue
+	Create(title, body string, labels, assignees []string) error
+	// Comment adds a comment to an issue
+	Comment(number int64, body string) error
+	// Edit replaces the description of an issue
+	Edit(number int64, body string) error
+	// Close closes an issue
+	Close(number int64) error
+}
+
+// Options holds the issue notification options
+type Options struct {
+	Label         string
+	Labels        []string
+	Assignees     []string
+	CloseResolved bool
+	TemplateTitle string
+	TemplateBody  string
+}
+
+// Marker returns the hidden marker identifying the issue of an image
+func Marker(image string) string {
+	return fmt.Sprintf("<!-- diun:image=%s -->", image)
+}
+
+// DigestMarker returns the hidden marker recording the digest notified in the
+// issue of an image
+func DigestMarker(dgst digest.Digest) string {
+	return fmt.Sprintf("<!-- diun:digest=%s -->", dgst)
+}
+
+// ProvidersMarker returns the hidden marker recording the providers reporting
+// the image of an issue
+func ProvidersMarker(providers []string) string {
+	return fmt.Sprintf("<!-- diun:providers=%s -->", strings.Join(providers, ","))
+}
+
+// Send creates an issue for the image of the entry or comments the open one
+func Send(tracker Tracker, opts Options, meta model.Meta, entry model.NotifEntry) error {
+	message, err := msg.New(msg.Options{
+		Meta:          meta,
+		Entry:         entry,
+		TemplateTitle: opts.TemplateTitle,
+		TemplateBody:  opts.TemplateBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	title, body, err := message.RenderMarkdown()
+	if err != nil {
+		return err
+	}
+
+	issues, err := tracker.OpenIssues(opts.Label)
+	if err != nil {
+		return errors.Wrap(err, "cannot list open issues")
+	}
+	for _, issue := range issues {
+		if image, ok := markerImage(issue.Body); ok && image == entry.Image.String() {
+			if err := tracker.Comment(issue.Number, string(body)); err != nil {
+				return errors.Wrapf(err, "cannot comment issue #%d", issue.Number)
+			}
+			edited := issue.Body
+			if entry.Manifest.Digest != "" {
+				edited = withMarker(edited, digestMarkerRegexp, DigestMarker(entry.Manifest.Digest))
+			}
+			if providers := mergeProviders(markerProviders(issue.Body), notifier.Providers(entry)); len(providers) > 0 {
+				edited = withMarker(edited, providersMarkerRegexp, ProvidersMarker(providers))
+			}
+			if edited == issue.Body {
+				return nil
+			}
+			return errors.Wrapf(tracker.Edit(issue.Number, edited), "cannot edit issue #%d", issue.Number)
+		}
+	}
+
+	labels, err := renderList(message, "label", append([]string{opts.Label}, opts.Labels...))
+	if err != nil {
+		return err
+	}
+	assignees, err := renderList(message, "assignee", opts.Assignees)
+	if err != nil {
+		return err
+	}
+
+	markers := Marker(entry.Image.String()) + "\n"
+	if entry.Manifest.Digest != "" {
+		markers += DigestMarker(entry.Manifest.Digest) + "\n"
+	}
+	if providers := notifier.Providers(entry); len(providers) > 0 {
+		markers += ProvidersMarker(providers) + "\n"
+	}
+	return errors.Wrap(tracker.Create(string(title), markers+string(body), labels, assignees), "cannot create issue")
+}
+
+// Resolve closes the open issues of images whose workloads all run the digest
+// recorded on the issue, or that are no longer reported by their providers.
+// Issues are only closed as no longer reported if all the providers recorded
+// on the issue completed the run, so a provider failing to list its images
+// does not close them.
+func Resolve(tracker Tracker, opts Options, entries []model.NotifEntry) error {
+	if !opts.CloseResolved || len(entries) == 0 {
+		return nil
+	}
+
+	completed := notifier.CompletedProviders(entries)
+	running := make(map[string][]digest.Digest, len(entries))
+	reporting := make(map[string][]string, len(entries))
+	for _, entry := range entries {
+		running[entry.Image.String()] = append(running[entry.Image.String()], entry.RunningDigest)
+		reporting[entry.Image.String()] = append(reporting[entry.Image.String()], notifier.Providers(entry)...)
+	}
+
+	issues, err := tracker.OpenIssues(opts.Label)
+	if err != nil {
+		return errors.Wrap(err, "cannot list open issues")
+	}
+	for _, issue := range issues {
+		image, ok := markerImage(issue.Body)
+		if !ok {
+			continue
+		}
+		recorded := markerProviders(issue.Body)
+		digests, reported := running[image]
+		if !reported {
+			if len(recorded) == 0 || slices.ContainsFunc(recorded, func(provider string) bool {
+				return !completed[provider]
+			}) {
+				continue
+			}
+			if err := closeIssue(tracker, issue.Number, fmt.Sprintf("Image `%s` is no longer reported by any provider.", image)); err != nil {
+				return err
+			}
+			continue
+		}
+		if dgst, ok := markerDigest(issue.Body); ok && caughtUp(digests, dgst) {
+			if err := closeIssue(tracker, issue.Number, fmt.Sprintf("Image `%s` is now running digest `%s`.", image, dgst)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// providers that did not complete the run may still use the image
+		providers := reporting[image]
+		for _, provider := range recorded {
+			if !completed[provider] {
+				providers = append(providers, provider)
+			}
+		}
+		if providers = mergeProviders(providers); len(providers) == 0 || slices.Equal(providers, recorded) {
+			continue
+		}
+		if err := tracker.Edit(issue.Number, withMarker(issue.Body, providersMarkerRegexp, ProvidersMarker(providers))); err != nil {
+			return errors.Wrapf(err, "cannot edit issue #%d", issue.Number)
+		}
+	}
+
+	return nil
+}
+
+func closeIssue(tracker Tracker, number int64, comment string) error {
+	if err := tracker.Comment(number, comment); err != nil {
+		return errors.Wrapf(err, "cannot comment issue #%d", number)
+	}
+	return errors.Wrapf(tracker.Close(number), "cannot close issue #%d", number)
+}
+
+// caughtUp returns true if all the workloads of an image run a digest. It is
+// false if the running digest of a workload is unknown.
+func caughtUp(running []digest.Digest, dgst digest.Digest) bool {
+	for _, r := range running {
+		if r != dgst {
+			return false
+		}
+	}
+	return len(running) > 0
+}
+
+func markerImage(body string) (string, bool) {
+	m := markerRegexp.FindStringSubmatch(body)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func markerDigest(body string) (digest.Digest, bool) {
+	m := digestMarkerRegexp.FindStringSubmatch(body)
+	if m == nil {
+		return "", false
+	}
+	return digest.Digest(m[1]), true
+}
+
+func markerProviders(body string) []string {
+	m := providersMarkerRegexp.FindStringSubmatch(body)
+	if m == nil {
+		return nil
+	}
+	return mergeProviders(strings.Split(m[1], ","))
+}
+
+// mergeProviders returns the sorted and unique providers of several lists
+func mergeProviders(lists ...[]string) []string {
+	var providers []string
+	for _, list := range lists {
+		for _, provider := range list {
+			if provider != "" {
+				providers = append(providers, provider)
+			}
+		}
+	}
+	slices.Sort(providers)
+	return slices.Compact(providers)
+}
+
+// withMarker returns the description of an issue with a marker replaced.
+// Issues created before the marker was recorded get it appended to the image
+// one.
+func withMarker(body string, re *regexp.Regexp, value string) string {
+	if re.MatchString(body) {
+		return re.ReplaceAllLiteralString(body, value)
+	}
+	marker := markerRegexp.FindString(body)
+	return strings.Replace(body, marker, marker+"\n"+value, 1)
+}
+
+// renderList renders a list of templates, dropping empty and duplicated
+// values. Missing metadata keys render as "<no value>" and are dropped too.
+func renderList(message *msg.Client, name string, templates []string) ([]string, error) {
+	var values []string
+	for i, tpl := range templates {
+		value, err := message.RenderTemplate(fmt.Sprintf("%s%d", name, i), tpl)
+		if err != nil {
+			return nil, err
+		}
+		if v := strings.TrimSpace(string(value)); len(v) > 0 && v != "<no value>" && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,225 @@
+package issue
+
+import (
+	"testing"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIssue struct {
+	Issue
+	Title     string
+	Labels    []string
+	Assignees []string
+	Comments  []string
+	Closed    bool
+}
+
+type fakeTracker struct {
+	issues []*fakeIssue
+}
+
+func (t *fakeTracker) OpenIssues(label string) ([]Issue, error) {
+	var issues []Issue
+	for _, i := range t.issues {
+		if !i.Closed && len(i.Labels) > 0 && i.Labels[0] == label {
+			issues = append(issues, i.Issue)
+		}
+	}
+	return issues, nil
+}
+
+func (t *fakeTracker) Create(title, body string, labels, assignees []string) error {
+	t.issues = append(t.issues, &fakeIssue{
+		Issue:     Issue{Number: int64(len(t.issues) + 1), Body: body},
+		Title:     title,
+		Labels:    labels,
+		Assignees: assignees,
+	})
+	return nil
+}
+
+func (t *fakeTracker) Comment(number int64, body string) error {
+	t.issues[number-1].Comments = append(t.issues[number-1].Comments, body)
+	return nil
+}
+
+func (t *fakeTracker) Edit(number int64, body string) error {
+	t.issues[number-1].Body = body
+	return nil
+}
+
+func (t *fakeTracker) Close(number int64) error {
+	t.issues[number-1].Closed = true
+	return nil
+}
+
+func TestSendResolve(t *testing.T) {
+	tracker := &fakeTracker{}
+	opts := Options{
+		Label:         "diun",
+		Labels:        []string{"{{ .Entry.Metadata.team }}", "{{ .Entry.Metadata.missing }}", "diun"},
+		Assignees:     []string{"{{ .Entry.Metadata.owner }}"},
+		CloseResolved: true,
+		TemplateTitle: model.NotifDefaultTemplateTitle,
+		TemplateBody:  "Digest {{ .Entry.Manifest.Digest }}",
+	}
+
+	diun := testEntry(t, "docker.io/crazymax/diun:latest", "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01")
+	require.NoError(t, Send(tracker, opts, model.Meta{}, diun))
+	require.Len(t, tracker.issues, 1)
+	assert.Equal(t, "docker.io/crazymax/diun:latest has been updated", tracker.issues[0].Title)
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:digest=sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01 -->\n<!-- diun:providers=docker -->\nDigest sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01", tracker.issues[0].Body)
+	assert.Equal(t, []string{"diun", "infra"}, tracker.issues[0].Labels)
+	assert.Equal(t, []string{"crazy-max"}, tracker.issues[0].Assignees)
+
+	diun.Manifest.Digest = "sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031"
+	require.NoError(t, Send(tracker, opts, model.Meta{}, diun))
+	require.Len(t, tracker.issues, 1)
+	assert.Equal(t, []string{"Digest sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031"}, tracker.issues[0].Comments)
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:digest=sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031 -->\n<!-- diun:providers=docker -->\nDigest sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01", tracker.issues[0].Body)
+
+	alpine := testEntry(t, "docker.io/library/alpine:3", "sha256:4edbd2beb5f78b1014028f4fbb99f3237d9561100b6881aabbf5acce2c4f9454")
+	require.NoError(t, Send(tracker, opts, model.Meta{}, alpine))
+	require.Len(t, tracker.issues, 2)
+
+	require.NoError(t, Resolve(tracker, opts, nil))
+	assert.False(t, tracker.issues[0].Closed)
+	assert.False(t, tracker.issues[1].Closed)
+
+	alpine.Status = model.ImageStatusUnchange
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{alpine}))
+	assert.True(t, tracker.issues[0].Closed)
+	assert.Equal(t, "Image `docker.io/crazymax/diun:latest` is no longer reported by any provider.", tracker.issues[0].Comments[1])
+	assert.False(t, tracker.issues[1].Closed)
+}
+
+func TestResolveCaughtUp(t *testing.T) {
+	tracker := &fakeTracker{}
+	opts := Options{
+		Label:         "diun",
+		CloseResolved: true,
+		TemplateTitle: model.NotifDefaultTemplateTitle,
+		TemplateBody:  model.NotifDefaultTemplateBody,
+	}
+
+	entry := testEntry(t, "docker.io/crazymax/diun:latest", "sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031")
+	require.NoError(t, Send(tracker, opts, model.Meta{}, entry))
+	require.Len(t, tracker.issues, 1)
+
+	// workloads still running the previous digest or an unknown one
+	other := entry
+	other.Provider = "kubernetes"
+	entry.RunningDigest = "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01"
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{entry}))
+	assert.False(t, tracker.issues[0].Closed)
+	entry.RunningDigest = entry.Manifest.Digest
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{entry, other}))
+	assert.False(t, tracker.issues[0].Closed)
+
+	other.RunningDigest = entry.Manifest.Digest
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{entry, other}))
+	assert.True(t, tracker.issues[0].Closed)
+	assert.Equal(t, []string{"Image `docker.io/crazymax/diun:latest` is now running digest `sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031`."}, tracker.issues[0].Comments)
+}
+
+func TestResolveFailedProvider(t *testing.T) {
+	tracker := &fakeTracker{}
+	opts := Options{
+		Label:         "diun",
+		CloseResolved: true,
+		TemplateTitle: model.NotifDefaultTemplateTitle,
+		TemplateBody:  model.NotifDefaultTemplateBody,
+	}
+
+	diun := testEntry(t, "docker.io/crazymax/diun:latest", "sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031")
+	require.NoError(t, Send(tracker, opts, model.Meta{}, diun))
+	alpine := testEntry(t, "docker.io/library/alpine:3", "sha256:4edbd2beb5f78b1014028f4fbb99f3237d9561100b6881aabbf5acce2c4f9454")
+	alpine.Provider = "kubernetes"
+	require.NoError(t, Send(tracker, opts, model.Meta{}, alpine))
+	require.Len(t, tracker.issues, 2)
+
+	// the docker provider failed to list its images
+	alpine.Status = model.ImageStatusUnchange
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{alpine}))
+	assert.False(t, tracker.issues[0].Closed)
+
+	// the docker provider failed to analyze an image
+	nginx := testEntry(t, "docker.io/library/nginx:latest", "")
+	nginx.Status = model.ImageStatusError
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{alpine, nginx}))
+	assert.False(t, tracker.issues[0].Closed)
+
+	// the image is now reported by the kubernetes provider while docker failed
+	kube := diun
+	kube.Provider = "kubernetes"
+	kube.Status = model.ImageStatusUnchange
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{kube, alpine, nginx}))
+	assert.False(t, tracker.issues[0].Closed)
+	assert.Equal(t, []string{"docker", "kubernetes"}, markerProviders(tracker.issues[0].Body))
+
+	// docker completed the run without the image, kubernetes still runs it
+	docker := testEntry(t, "docker.io/library/nginx:latest", "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01")
+	docker.Status = model.ImageStatusUnchange
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{kube, alpine, docker}))
+	assert.False(t, tracker.issues[0].Closed)
+	assert.Equal(t, []string{"kubernetes"}, markerProviders(tracker.issues[0].Body))
+
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{alpine, docker}))
+	assert.True(t, tracker.issues[0].Closed)
+	assert.False(t, tracker.issues[1].Closed)
+}
+
+func TestWithMarker(t *testing.T) {
+	body := Marker("docker.io/crazymax/diun:latest") + "\nbody"
+	body = withMarker(body, digestMarkerRegexp, DigestMarker("sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01"))
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:digest=sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01 -->\nbody", body)
+	body = withMarker(body, digestMarkerRegexp, DigestMarker("sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031"))
+	assert.Equal(t, "<!-- diun:image=docker.io/crazymax/diun:latest -->\n<!-- diun:digest=sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031 -->\nbody", body)
+	body = withMarker(body, providersMarkerRegexp, ProvidersMarker([]string{"docker", "swarm"}))
+	assert.Equal(t, []string{"docker", "swarm"}, markerProviders(body))
+}
+
+func TestResolveDisabled(t *testing.T) {
+	tracker := &fakeTracker{}
+	opts := Options{
+		Label:         "diun",
+		TemplateTitle: model.NotifDefaultTemplateTitle,
+		TemplateBody:  model.NotifDefaultTemplateBody,
+	}
+
+	require.NoError(t, Send(tracker, opts, model.Meta{}, testEntry(t, "docker.io/crazymax/diun:latest", "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01")))
+	require.NoError(t, Resolve(tracker, opts, []model.NotifEntry{testEntry(t, "docker.io/library/alpine:3", "sha256:4edbd2beb5f78b1014028f4fbb99f3237d9561100b6881aabbf5acce2c4f9454")}))
+	assert.False(t, tracker.issues[0].Closed)
+}
+
+func TestMarkerImage(t *testing.T) {
+	image, ok := markerImage("Some text\n" + Marker("ghcr.io/crazy-max/diun:4") + "\nbody")
+	assert.True(t, ok)
+	assert.Equal(t, "ghcr.io/crazy-max/diun:4", image)
+
+	_, ok = markerImage("no marker")
+	assert.False(t, ok)
+}
+
+func testEntry(t *testing.T, name, dgst string) model.NotifEntry {
+	t.Helper()
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: name})
+	require.NoError(t, err)
+	return model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+		Manifest: registry.Manifest{
+			Digest: digest.Digest(dgst),
+		},
+		Metadata: map[string]string{
+			"team":  "infra",
+			"owner": "crazy-max",
+		},
+	}
+}
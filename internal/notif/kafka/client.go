@@ -0,0 +1,172 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// Client represents an active kafka notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifKafka
+	meta model.Meta
+}
+
+// New creates a new kafka notification instance
+func New(config *model.NotifKafka, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "kafka"
+}
+
+// Send creates and sends a kafka notification with an entry. Records are
+// keyed by image so notifications of an image land on the same partition.
+func (c *Client) Send(entry model.NotifEntry) error {
+	mechanism, err := c.mechanism()
+	if err != nil {
+		return err
+	}
+
+	message, err := msg.New(msg.Options{
+		Meta:  c.meta,
+		Entry: entry,
+	})
+	if err != nil {
+		return err
+	}
+
+	topic, err := message.RenderTemplate("topic", c.cfg.Topic)
+	if err != nil {
+		return err
+	}
+	body, headers, err := c.render(message)
+	if err != nil {
+		return err
+	}
+
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	defer func() { cancelCause(errors.WithStack(context.Canceled)) }()
+	timeoutCtx, cancel := context.WithTimeoutCause(cancelCtx, *c.cfg.Timeout, errors.WithStack(context.DeadlineExceeded))
+	defer cancel()
+
+	return c.produce(timeoutCtx, mechanism, &kgo.Record{
+		Topic: strings.TrimSpace(string(topic)),
+		Key:   []byte(entry.Image.String()),
+		Value: body,
+		Headers: append([]kgo.RecordHeader{
+			{Key: "Diun-Status", Value: []byte(entry.Status)},
+			{Key: "Diun-Provider", Value: []byte(entry.Provider)},
+			{Key: "Diun-Image", Value: []byte(entry.Image.String())},
+		}, headers...),
+	})
+}
+
+// render returns the record value and the headers describing it. In
+// CloudEvents binary mode, context attributes are mapped to ce_ headers.
+func (c *Client) render(message *msg.Client) ([]byte, []kgo.RecordHeader, error) {
+	if c.cfg.CloudEvents == "" {
+		body, err := message.RenderJSON()
+		return body, nil, err
+	}
+
+	event, err := message.RenderCloudEvent()
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.cfg.CloudEvents == model.NotifCloudEventsStructured {
+		body, err := json.Marshal(event)
+		return body, []kgo.RecordHeader{{Key: "content-type", Value: []byte(msg.CloudEventsContentType)}}, err
+	}
+
+	attrs := event.Attributes()
+	headers := []kgo.RecordHeader{{Key: "content-type", Value: []byte(event.DataContentType)}}
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		headers = append(headers, kgo.RecordHeader{Key: "ce_" + key, Value: []byte(attrs[key])})
+	}
+	return event.Data, headers, nil
+}
+
+// produce writes a record to its topic and waits for the brokers to
+// acknowledge it
+func (c *Client) produce(ctx context.Context, mechanism sasl.Mechanism, record *kgo.Record) error {
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(c.cfg.Brokers...),
+		kgo.ClientID(c.cfg.ClientID),
+		kgo.AllowAutoTopicCreation(),
+		kgo.ProduceRequestTimeout(*c.cfg.Timeout),
+		// a single small record is written per client: compression would
+		// not pay off and idempotence requires an extra ACL on the cluster
+		kgo.ProducerBatchCompression(kgo.NoCompression()),
+		kgo.DisableIdempotentWrite(),
+	}
+	if mechanism != nil {
+		opts = append(opts, kgo.SASL(mechanism))
+	}
+	if c.cfg.TLS {
+		tlsConfig, err := httputil.LoadTLSConfig(c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+		if err != nil {
+			return errors.Wrap(err, "cannot load TLS configuration")
+		}
+		opts = append(opts, kgo.DialTLSConfig(tlsConfig))
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return errors.Wrap(err, "cannot create Kafka client")
+	}
+	defer client.Close()
+
+	if err := client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return errors.Wrapf(err, "cannot produce to topic %s", record.Topic)
+	}
+	return nil
+}
+
+// mechanism returns the configured SASL mechanism if any
+func (c *Client) mechanism() (sasl.Mechanism, error) {
+	if c.cfg.SASLMechanism == "" {
+		return nil, nil
+	}
+
+	username, err := secret.GetSecret(c.cfg.Username, c.cfg.UsernameFile)
+	if err != nil {
+		return nil, err
+	}
+	password, err := secret.GetSecret(c.cfg.Password, c.cfg.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.cfg.SASLMechanism {
+	case model.KafkaSASLPlain:
+		return plain.Auth{User: username, Pass: password}.AsMechanism(), nil
+	case model.KafkaSASLScramSha256:
+		return scram.Auth{User: username, Pass: password}.AsSha256Mechanism(), nil
+	case model.KafkaSASLScramSha512:
+		return scram.Auth{User: username, Pass: password}.AsSha512Mechanism(), nil
+	default:
+		return nil, errors.Errorf("unknown SASL mechanism %q", c.cfg.SASLMechanism)
+	}
+}
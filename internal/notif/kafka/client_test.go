@@ -0,0 +1,241 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestSend(t *testing.T) {
+	broker := newFakeBroker(t, 3)
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifKafka{
+			Brokers:       []string{broker.addr},
+			Topic:         "diun.{{ .Entry.Provider }}",
+			ClientID:      "diun",
+			SASLMechanism: model.KafkaSASLPlain,
+			Username:      "user",
+			Password:      "pass",
+			Timeout:       new(5 * time.Second),
+		},
+		meta: model.Meta{
+			Hostname: "myserver",
+		},
+	}
+
+	for range 2 {
+		require.NoError(t, c.Send(model.NotifEntry{
+			Status:   model.ImageStatusUpdate,
+			Provider: "docker",
+			Image:    image,
+			Manifest: registry.Manifest{
+				Name:   "docker.io/crazymax/diun",
+				Tag:    "latest",
+				Digest: "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+			},
+		}))
+	}
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	assert.Equal(t, []byte("\x00user\x00pass"), broker.auth)
+	require.Len(t, broker.produced, 2)
+	for _, p := range broker.produced {
+		assert.Equal(t, "diun.docker", p.topic)
+		assert.Equal(t, kgo.StickyKeyPartitioner(nil).ForTopic(p.topic).Partition(&kgo.Record{Key: p.record.Key}, 3), int(p.partition))
+		assert.Equal(t, "docker.io/crazymax/diun:latest", string(p.record.Key))
+		assert.Contains(t, string(p.record.Value), `"status":"update"`)
+		assert.Equal(t, []kmsg.Header{
+			{Key: "Diun-Status", Value: []byte("update")},
+			{Key: "Diun-Provider", Value: []byte("docker")},
+			{Key: "Diun-Image", Value: []byte("docker.io/crazymax/diun:latest")},
+		}, p.record.Headers)
+	}
+}
+
+func TestSendCloudEventsBinary(t *testing.T) {
+	broker := newFakeBroker(t, 1)
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifKafka{
+			Brokers:     []string{broker.addr},
+			Topic:       "diun",
+			ClientID:    "diun",
+			CloudEvents: model.NotifCloudEventsBinary,
+			Timeout:     new(5 * time.Second),
+		},
+		meta: model.Meta{
+			Hostname: "myserver",
+		},
+	}
+	require.NoError(t, c.Send(model.NotifEntry{
+		Status:   model.ImageStatusNew,
+		Provider: "docker",
+		Image:    image,
+	}))
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	require.Len(t, broker.produced, 1)
+	headers := map[string]string{}
+	for _, h := range broker.produced[0].record.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	assert.Equal(t, "application/json", headers["content-type"])
+	assert.Equal(t, "1.0", headers["ce_specversion"])
+	assert.Equal(t, "io.diun.image.added", headers["ce_type"])
+	assert.Equal(t, "myserver", headers["ce_source"])
+	assert.Equal(t, "docker.io/crazymax/diun:latest", headers["ce_subject"])
+	assert.Equal(t, "new", headers["Diun-Status"])
+	assert.Contains(t, string(broker.produced[0].record.Value), `"status":"new"`)
+}
+
+type produced struct {
+	topic     string
+	partition int32
+	record    kmsg.Record
+}
+
+type fakeBroker struct {
+	t          *testing.T
+	mu         sync.Mutex
+	addr       string
+	partitions int32
+	auth       []byte
+	produced   []produced
+}
+
+func newFakeBroker(t *testing.T, partitions int32) *fakeBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	b := &fakeBroker{t: t, addr: ln.Addr().String(), partitions: partitions}
+	go func() {
+		for {
+			cn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(cn)
+		}
+	}()
+	return b
+}
+
+func (b *fakeBroker) serve(cn net.Conn) {
+	defer cn.Close()
+	for {
+		size := make([]byte, 4)
+		if _, err := io.ReadFull(cn, size); err != nil {
+			return
+		}
+		frame := make([]byte, binary.BigEndian.Uint32(size))
+		if _, err := io.ReadFull(cn, frame); err != nil {
+			return
+		}
+
+		key := int16(binary.BigEndian.Uint16(frame))
+		req := kmsg.RequestForKey(key)
+		req.SetVersion(int16(binary.BigEndian.Uint16(frame[2:])))
+		correlationID := frame[4:8]
+		clientIDLen := int(binary.BigEndian.Uint16(frame[8:]))
+		body := frame[10+clientIDLen:]
+		if req.IsFlexible() {
+			body = body[1:]
+		}
+		if !assert.NoError(b.t, req.ReadFrom(body)) {
+			return
+		}
+
+		resp := b.handle(req)
+		out := append([]byte{0, 0, 0, 0}, correlationID...)
+		if resp.IsFlexible() && key != int16(kmsg.ApiVersions) {
+			out = append(out, 0)
+		}
+		out = resp.AppendTo(out)
+		binary.BigEndian.PutUint32(out, uint32(len(out)-4))
+		if _, err := cn.Write(out); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) handle(req kmsg.Request) kmsg.Response {
+	switch req := req.(type) {
+	case *kmsg.ApiVersionsRequest:
+		resp := req.ResponseKind().(*kmsg.ApiVersionsResponse)
+		for key, maxVersion := range map[kmsg.Key]int16{kmsg.Produce: 9, kmsg.Metadata: 12, kmsg.SASLHandshake: 1, kmsg.SASLAuthenticate: 2, kmsg.ApiVersions: 3} {
+			resp.ApiKeys = append(resp.ApiKeys, kmsg.ApiVersionsResponseApiKey{ApiKey: int16(key), MinVersion: 0, MaxVersion: maxVersion})
+		}
+		return resp
+	case *kmsg.SASLHandshakeRequest:
+		return req.ResponseKind()
+	case *kmsg.SASLAuthenticateRequest:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.auth = req.SASLAuthBytes
+		return req.ResponseKind()
+	case *kmsg.MetadataRequest:
+		resp := req.ResponseKind().(*kmsg.MetadataResponse)
+		host, port, _ := net.SplitHostPort(b.addr)
+		portNum, _ := strconv.Atoi(port)
+		resp.Brokers = append(resp.Brokers, kmsg.MetadataResponseBroker{NodeID: 1, Host: host, Port: int32(portNum)})
+		for _, t := range req.Topics {
+			topic := kmsg.NewMetadataResponseTopic()
+			topic.Topic = t.Topic
+			for i := range b.partitions {
+				p := kmsg.NewMetadataResponseTopicPartition()
+				p.Partition = i
+				p.Leader = 1
+				topic.Partitions = append(topic.Partitions, p)
+			}
+			resp.Topics = append(resp.Topics, topic)
+		}
+		return resp
+	case *kmsg.ProduceRequest:
+		resp := req.ResponseKind().(*kmsg.ProduceResponse)
+		for _, t := range req.Topics {
+			rt := kmsg.NewProduceResponseTopic()
+			rt.Topic = t.Topic
+			for _, p := range t.Partitions {
+				var batch kmsg.RecordBatch
+				assert.NoError(b.t, batch.ReadFrom(p.Records))
+				assert.Equal(b.t, int32(len(p.Records)-12), batch.Length)
+				assert.Equal(b.t, int32(crc32.Checksum(p.Records[21:], crc32.MakeTable(crc32.Castagnoli))), batch.CRC)
+				assert.Equal(b.t, int32(1), batch.NumRecords)
+				var record kmsg.Record
+				assert.NoError(b.t, record.ReadFrom(batch.Records))
+				b.mu.Lock()
+				b.produced = append(b.produced, produced{topic: t.Topic, partition: p.Partition, record: record})
+				b.mu.Unlock()
+				rp := kmsg.NewProduceResponseTopicPartition()
+				rp.Partition = p.Partition
+				rt.Partitions = append(rt.Partitions, rp)
+			}
+			resp.Topics = append(resp.Topics, rt)
+		}
+		return resp
+	}
+	b.t.Errorf("unexpected request %s", kmsg.NameForKey(req.Key()))
+	return req.ResponseKind()
+}
@@ -0,0 +1,237 @@
+package mattermost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+)
+
+// Client represents an active mattermost notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifMattermost
+	meta model.Meta
+}
+
+// New creates a new mattermost notification instance
+func New(config *model.NotifMattermost, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "mattermost"
+}
+
+// Send creates and sends a mattermost notification with an entry. The message
+// is posted through an incoming webhook if a webhook URL is defined, or with
+// the REST API using a bot token otherwise.
+// https://developers.mattermost.com/integrate/webhooks/incoming/
+// https://api.mattermost.com/#tag/posts/operation/CreatePost
+func (c *Client) Send(entry model.NotifEntry) error {
+	webhookURL, err := secret.GetSecret(c.cfg.WebhookURL, c.cfg.WebhookURLFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve webhook URL for Mattermost notifier")
+	}
+
+	token, err := secret.GetSecret(c.cfg.Token, c.cfg.TokenFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve token secret for Mattermost notifier")
+	}
+
+	message, err := msg.New(msg.Options{
+		Meta:          c.meta,
+		Entry:         entry,
+		TemplateTitle: c.cfg.TemplateTitle,
+		TemplateBody:  c.cfg.TemplateBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	title, body, err := message.RenderMarkdown()
+	if err != nil {
+		return err
+	}
+
+	text := string(title)
+	var attachments []Attachment
+	if *c.cfg.RenderAttachment {
+		attachments = append(attachments, c.attachment(entry, string(title), string(body)))
+	} else {
+		text = fmt.Sprintf("#### %s\n%s", title, body)
+	}
+
+	var endpoint string
+	var payload any
+	switch {
+	case len(webhookURL) > 0:
+		endpoint = webhookURL
+		payload = Webhook{
+			Text:        text,
+			Channel:     c.cfg.Channel,
+			Username:    c.username(),
+			IconURL:     c.iconURL(),
+			Attachments: attachments,
+		}
+	case len(c.cfg.Endpoint) > 0:
+		if len(token) == 0 {
+			return errors.New("token is required for Mattermost notifier with endpoint")
+		}
+		u, err := url.Parse(c.cfg.Endpoint)
+		if err != nil {
+			return err
+		}
+		u.Path = path.Join(u.Path, "api/v4/posts")
+		endpoint = u.String()
+		payload = Post{
+			ChannelID: c.cfg.ChannelID,
+			Message:   text,
+			Props: Props{
+				Attachments:      attachments,
+				OverrideUsername: c.cfg.Username,
+				OverrideIconURL:  c.cfg.IconURL,
+			},
+		}
+	default:
+		return errors.New("webhook URL or endpoint is required for Mattermost notifier")
+	}
+
+	dataBuf := new(bytes.Buffer)
+	if err := json.NewEncoder(dataBuf).Encode(payload); err != nil {
+		return err
+	}
+
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	defer func() { cancelCause(errors.WithStack(context.Canceled)) }()
+	timeoutCtx, cancel := context.WithTimeoutCause(cancelCtx, *c.cfg.Timeout, errors.WithStack(context.DeadlineExceeded))
+	defer cancel()
+
+	hc, err := httputil.NewClient(c.cfg.Proxy, c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return errors.Wrap(err, "cannot create HTTP client for Mattermost notifier")
+	}
+
+	req, err := http.NewRequestWithContext(timeoutCtx, "POST", endpoint, dataBuf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.meta.UserAgent)
+	if len(webhookURL) == 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errBody struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		if err := json.Unmarshal(respBody, &errBody); err == nil && len(errBody.Message) > 0 {
+			return errors.Errorf("unexpected HTTP status %d: %s (%s)", resp.StatusCode, errBody.Message, errBody.ID)
+		}
+		return errors.Errorf("unexpected HTTP status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+func (c *Client) attachment(entry model.NotifEntry, title, body string) Attachment {
+	color := "#68CA00"
+	if entry.Status == model.ImageStatusUpdate {
+		color = "#0076D7"
+	}
+
+	var fields []AttachmentField
+	if *c.cfg.RenderFields {
+		fields = []AttachmentField{
+			{
+				Title: "Hostname",
+				Value: c.meta.Hostname,
+				Short: true,
+			},
+			{
+				Title: "Provider",
+				Value: entry.Provider,
+				Short: true,
+			},
+		}
+		if entry.Manifest.Created != nil {
+			fields = append(fields, AttachmentField{
+				Title: "Created",
+				Value: entry.Manifest.Created.Format("Jan 02, 2006 15:04:05 UTC"),
+				Short: true,
+			})
+		}
+		fields = append(fields, AttachmentField{
+			Title: "Platform",
+			Value: entry.Manifest.Platform,
+			Short: true,
+		}, AttachmentField{
+			Title: "Digest",
+			Value: entry.Manifest.Digest.String(),
+			Short: false,
+		})
+		if len(entry.Manifest.ArtifactType) > 0 {
+			fields = append(fields, AttachmentField{
+				Title: "Artifact type",
+				Value: entry.Manifest.ArtifactType,
+				Short: false,
+			})
+		}
+	}
+
+	return Attachment{
+		Fallback:   title,
+		Color:      color,
+		AuthorName: c.meta.Name,
+		AuthorIcon: c.meta.Logo,
+		AuthorLink: c.meta.URL,
+		Title:      entry.Image.String(),
+		TitleLink:  entry.Image.HubLink,
+		Text:       body,
+		Fields:     fields,
+		Footer:     fmt.Sprintf("%s © %d %s %s", c.meta.Author, time.Now().Year(), c.meta.Name, c.meta.Version),
+		FooterIcon: c.meta.Logo,
+	}
+}
+
+func (c *Client) username() string {
+	if len(c.cfg.Username) > 0 {
+		return c.cfg.Username
+	}
+	return c.meta.Name
+}
+
+func (c *Client) iconURL() string {
+	if len(c.cfg.IconURL) > 0 {
+		return c.cfg.IconURL
+	}
+	return c.meta.Logo
+}
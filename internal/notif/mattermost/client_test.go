@@ -0,0 +1,148 @@
+package mattermost
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendWebhook(t *testing.T) {
+	var webhook Webhook
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		cfg: &model.NotifMattermost{
+			WebhookURL:       ts.URL + "/hooks/xxx",
+			Channel:          "town-square",
+			RenderAttachment: new(true),
+			RenderFields:     new(true),
+			Timeout:          new(2 * time.Second),
+			TemplateTitle:    model.NotifDefaultTemplateTitle,
+			TemplateBody:     model.NotifDefaultTemplateBody,
+		},
+		meta: model.Meta{
+			Name:      "Diun",
+			Hostname:  "myserver",
+			Logo:      "https://raw.githubusercontent.com/crazy-max/diun/master/.res/diun.png",
+			UserAgent: "diun-test",
+		},
+	}
+	require.NoError(t, c.Send(testEntry(t)))
+
+	assert.Equal(t, "docker.io/crazymax/diun:latest has been updated", webhook.Text)
+	assert.Equal(t, "town-square", webhook.Channel)
+	assert.Equal(t, "Diun", webhook.Username)
+	assert.Equal(t, "https://raw.githubusercontent.com/crazy-max/diun/master/.res/diun.png", webhook.IconURL)
+	require.Len(t, webhook.Attachments, 1)
+	assert.Equal(t, "#0076D7", webhook.Attachments[0].Color)
+	assert.Equal(t, "https://hub.docker.com/r/crazymax/diun", webhook.Attachments[0].TitleLink)
+	assert.Contains(t, webhook.Attachments[0].Text, "[**docker.io/crazymax/diun:latest**](https://hub.docker.com/r/crazymax/diun)")
+	require.NotEmpty(t, webhook.Attachments[0].Fields)
+	assert.Equal(t, AttachmentField{Title: "Hostname", Value: "myserver", Short: true}, webhook.Attachments[0].Fields[0])
+}
+
+func TestSendBot(t *testing.T) {
+	var post Post
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/mattermost/api/v4/posts", r.URL.Path)
+		assert.Equal(t, "Bearer t0k3n", r.Header.Get("Authorization"))
+		if err := json.NewDecoder(r.Body).Decode(&post); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"abc"}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		cfg: &model.NotifMattermost{
+			Endpoint:         ts.URL + "/mattermost",
+			Token:            "t0k3n",
+			ChannelID:        "4xp9fdt77pncbef59f4k1qe83o",
+			RenderAttachment: new(false),
+			RenderFields:     new(false),
+			Timeout:          new(2 * time.Second),
+			TemplateTitle:    model.NotifDefaultTemplateTitle,
+			TemplateBody:     "Digest {{ .Entry.Manifest.Digest }}",
+		},
+	}
+	require.NoError(t, c.Send(testEntry(t)))
+
+	assert.Equal(t, "4xp9fdt77pncbef59f4k1qe83o", post.ChannelID)
+	assert.Equal(t, "#### docker.io/crazymax/diun:latest has been updated\nDigest sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01", post.Message)
+	assert.Empty(t, post.Props.Attachments)
+}
+
+func TestSendBotError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"id":"api.context.permissions.app_error","message":"You do not have the appropriate permissions.","status_code":403}`))
+	}))
+	defer ts.Close()
+
+	c := Client{
+		cfg: &model.NotifMattermost{
+			Endpoint:         ts.URL,
+			Token:            "t0k3n",
+			ChannelID:        "4xp9fdt77pncbef59f4k1qe83o",
+			RenderAttachment: new(true),
+			RenderFields:     new(true),
+			Timeout:          new(2 * time.Second),
+			TemplateTitle:    model.NotifDefaultTemplateTitle,
+			TemplateBody:     model.NotifDefaultTemplateBody,
+		},
+	}
+
+	err := c.Send(testEntry(t))
+	require.Error(t, err)
+	assert.Equal(t, "unexpected HTTP status 403: You do not have the appropriate permissions. (api.context.permissions.app_error)", err.Error())
+}
+
+func TestAttachmentUnknownCreated(t *testing.T) {
+	c := Client{
+		cfg: &model.NotifMattermost{
+			RenderFields: new(true),
+		},
+	}
+	entry := testEntry(t)
+	entry.Manifest.Created = nil
+
+	attachment := c.attachment(entry, "title", "body")
+	require.NotEmpty(t, attachment.Fields)
+	for _, field := range attachment.Fields {
+		assert.NotEqual(t, "Created", field.Title)
+	}
+}
+
+func testEntry(t *testing.T) model.NotifEntry {
+	t.Helper()
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+	return model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+		Manifest: registry.Manifest{
+			Name:    "docker.io/crazymax/diun",
+			Tag:     "latest",
+			Digest:  "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+			Created: new(time.Date(2026, 5, 24, 12, 34, 56, 0, time.UTC)),
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package mattermost
+
+// Webhook contains all the information for an incoming webhook message
+type Webhook struct {
+	Text        string       `json:"text"`
+	Channel     string       `json:"channel,omitempty"`
+	Username    string       `json:"username,omitempty"`
+	IconURL     string       `json:"icon_url,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Post contains all the information for a post created with a bot token
+type Post struct {
+	ChannelID string `json:"channel_id"`
+	Message   string `json:"message"`
+	Props     Props  `json:"props,omitzero"`
+}
+
+// Props contains the properties of a post
+type Props struct {
+	Attachments      []Attachment `json:"attachments,omitempty"`
+	OverrideUsername string       `json:"override_username,omitempty"`
+	OverrideIconURL  string       `json:"override_icon_url,omitempty"`
+}
+
+// Attachment contains all the information for a message attachment
+// https://developers.mattermost.com/integrate/reference/message-attachments/
+type Attachment struct {
+	Fallback   string            `json:"fallback,omitempty"`
+	Color      string            `json:"color,omitempty"`
+	AuthorName string            `json:"author_name,omitempty"`
+	AuthorIcon string            `json:"author_icon,omitempty"`
+	AuthorLink string            `json:"author_link,omitempty"`
+	Title      string            `json:"title,omitempty"`
+	TitleLink  string            `json:"title_link,omitempty"`
+	Text       string            `json:"text"`
+	Fields     []AttachmentField `json:"fields,omitempty"`
+	Footer     string            `json:"footer,omitempty"`
+	FooterIcon string            `json:"footer_icon,omitempty"`
+}
+
+// AttachmentField contains information for an attachment field
+type AttachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
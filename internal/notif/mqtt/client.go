@@ -1,7 +1,10 @@
 package mqtt
 
 import (
+	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/crazy-max/diun/v4/internal/model"
 	"github.com/crazy-max/diun/v4/internal/msg"
@@ -16,6 +19,14 @@ type Client struct {
 	cfg        *model.NotifMqtt
 	meta       model.Meta
 	mqttClient MQTT.Client
+
+	// mu guards the Home Assistant entities retained on the broker, which
+	// are tracked through a subscription to their discovery and state topics.
+	mu           sync.Mutex
+	entities     map[string]bool
+	states       map[string]haState
+	subscribed   chan struct{}
+	subscribedAt time.Time
 }
 
 // New creates a new mqtt notification instance
@@ -35,6 +46,55 @@ func (c *Client) Name() string {
 
 // Send creates and sends a mqtt notification with an entry
 func (c *Client) Send(entry model.NotifEntry) error {
+	if err := c.connect(); err != nil {
+		return err
+	}
+
+	message, err := msg.New(msg.Options{
+		Meta:  c.meta,
+		Entry: entry,
+	})
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	if c.cfg.CloudEvents == model.NotifCloudEventsStructured {
+		event, err := message.RenderCloudEvent()
+		if err != nil {
+			return err
+		}
+		if body, err = json.Marshal(event); err != nil {
+			return err
+		}
+	} else if body, err = message.RenderJSON(); err != nil {
+		return err
+	}
+
+	if err := c.publish(c.cfg.Topic, false, body); err != nil {
+		return err
+	}
+	if c.cfg.HomeAssistant != nil {
+		return c.publishUpdate(entry)
+	}
+	return nil
+}
+
+// Close marks Diun as unavailable in Home Assistant and disconnects from the
+// broker
+func (c *Client) Close() error {
+	if c.mqttClient == nil || !c.mqttClient.IsConnected() {
+		return nil
+	}
+	var err error
+	if c.cfg.HomeAssistant != nil {
+		err = c.publish(c.availabilityTopic(), true, []byte(haPayloadNotAvailable))
+	}
+	c.mqttClient.Disconnect(250)
+	return err
+}
+
+func (c *Client) connect() error {
 	username, err := secret.GetSecret(c.cfg.Username, c.cfg.UsernameFile)
 	if err != nil {
 		return err
@@ -49,6 +109,10 @@ func (c *Client) Send(entry model.NotifEntry) error {
 	opts := MQTT.NewClientOptions().AddBroker(broker).SetClientID(c.cfg.Client)
 	opts.Username = username
 	opts.Password = password
+	if c.cfg.HomeAssistant != nil {
+		opts.SetWill(c.availabilityTopic(), haPayloadNotAvailable, byte(c.cfg.QoS), true)
+		opts.SetOnConnectHandler(c.onConnect)
+	}
 
 	if c.mqttClient == nil {
 		c.mqttClient = MQTT.NewClient(opts)
@@ -58,21 +122,11 @@ func (c *Client) Send(entry model.NotifEntry) error {
 			return token.Error()
 		}
 	}
+	return nil
+}
 
-	message, err := msg.New(msg.Options{
-		Meta:  c.meta,
-		Entry: entry,
-	})
-	if err != nil {
-		return err
-	}
-
-	body, err := message.RenderJSON()
-	if err != nil {
-		return err
-	}
-
-	token := c.mqttClient.Publish(c.cfg.Topic, byte(c.cfg.QoS), false, body)
+func (c *Client) publish(topic string, retained bool, payload []byte) error {
+	token := c.mqttClient.Publish(topic, byte(c.cfg.QoS), retained, payload)
 	token.Wait()
 	return token.Error()
 }
@@ -36,6 +36,31 @@ func TestSendPublishesRenderedJSON(t *testing.T) {
 	assert.Equal(t, "docker.io/library/alpine:latest", payload.Image)
 }
 
+func TestSendPublishesStructuredCloudEvent(t *testing.T) {
+	mqttClient := &fakeMQTTClient{}
+	client := newTestClient(mqttClient)
+	client.cfg.CloudEvents = model.NotifCloudEventsStructured
+
+	err := client.Send(testEntry(t))
+	require.NoError(t, err)
+
+	var event struct {
+		SpecVersion string `json:"specversion"`
+		Type        string `json:"type"`
+		Source      string `json:"source"`
+		Subject     string `json:"subject"`
+		Data        struct {
+			Image string `json:"image"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(mqttClient.payload, &event))
+	assert.Equal(t, "1.0", event.SpecVersion)
+	assert.Equal(t, "io.diun.image.updated", event.Type)
+	assert.Equal(t, "node-1", event.Source)
+	assert.Equal(t, "docker.io/library/alpine:latest", event.Subject)
+	assert.Equal(t, "docker.io/library/alpine:latest", event.Data.Image)
+}
+
 func TestSendReturnsConnectError(t *testing.T) {
 	mqttClient := &fakeMQTTClient{
 		connectErr: errors.New("connect failed"),
@@ -63,16 +88,180 @@ func TestSendReturnsPublishError(t *testing.T) {
 	assert.Equal(t, 1, mqttClient.publishCalls)
 }
 
+func TestSendPublishesHomeAssistantUpdate(t *testing.T) {
+	mqttClient := &fakeMQTTClient{connected: true}
+	client := newTestClient(mqttClient)
+	client.cfg.HomeAssistant = (&model.NotifMqttHomeAssistant{}).GetDefaults()
+
+	entry := testEntry(t)
+	entry.Manifest = registry.Manifest{
+		Digest:  "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		Created: new(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)),
+	}
+	entry.Diff = &registry.ManifestDiff{
+		Digest: &registry.ValueChange{
+			Name: "digest",
+			From: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			To:   "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		},
+		Version: &registry.ValueChange{Name: registry.LabelVersion, From: "3.19", To: "3.20"},
+	}
+	entry.Image.HubLink = "https://hub.docker.com/_/alpine"
+
+	require.NoError(t, client.Send(entry))
+	require.Len(t, mqttClient.published, 3)
+	assert.Equal(t, "diun/images", mqttClient.published[0].topic)
+
+	config := mqttClient.published[1]
+	assert.Equal(t, "homeassistant/update/node-1/docker_io_library_alpine_latest/config", config.topic)
+	assert.True(t, config.retained)
+	var discovery struct {
+		UniqueID          string `json:"unique_id"`
+		StateTopic        string `json:"state_topic"`
+		AvailabilityTopic string `json:"availability_topic"`
+		Device            struct {
+			Identifiers []string `json:"identifiers"`
+			SwVersion   string   `json:"sw_version"`
+		} `json:"device"`
+	}
+	require.NoError(t, json.Unmarshal(config.payload, &discovery))
+	assert.Equal(t, "diun_node-1_docker_io_library_alpine_latest", discovery.UniqueID)
+	assert.Equal(t, "diun/node-1/docker_io_library_alpine_latest/state", discovery.StateTopic)
+	assert.Equal(t, "diun/node-1/status", discovery.AvailabilityTopic)
+	assert.Equal(t, []string{"diun_node-1"}, discovery.Device.Identifiers)
+	assert.Equal(t, "4.0.0", discovery.Device.SwVersion)
+
+	state := mqttClient.published[2]
+	assert.Equal(t, "diun/node-1/docker_io_library_alpine_latest/state", state.topic)
+	assert.True(t, state.retained)
+	assert.JSONEq(t, `{
+		"installed_version": "sha256:0123456789ab",
+		"latest_version": "sha256:fedcba987654",
+		"title": "docker.io/library/alpine:latest",
+		"release_url": "https://hub.docker.com/_/alpine",
+		"release_summary": "Version 3.19 → 3.20 (created 2026-10-01)",
+		"providers": ["file"]
+	}`, string(state.payload))
+}
+
+func TestResolveHomeAssistantEntities(t *testing.T) {
+	retainedDelay := haRetainedDelay
+	haRetainedDelay = 0
+	t.Cleanup(func() {
+		haRetainedDelay = retainedDelay
+	})
+
+	mqttClient := &fakeMQTTClient{connected: true}
+	client := newTestClient(mqttClient)
+	client.cfg.HomeAssistant = (&model.NotifMqttHomeAssistant{}).GetDefaults()
+
+	client.onConnect(mqttClient)
+	assert.Equal(t, []string{"homeassistant/update/node-1/+/config", "diun/node-1/+/state"}, mqttClient.subscriptions)
+	require.Len(t, mqttClient.published, 1)
+	assert.Equal(t, fakeMessage{topic: "diun/node-1/status", qos: 1, retained: true, payload: []byte("online")}, mqttClient.published[0])
+
+	for _, objectID := range []string{"docker_io_library_nginx_latest", "docker_io_library_redis_latest", "docker_io_library_httpd_latest", "docker_io_library_mysql_latest"} {
+		client.onConfig(mqttClient, fakeMessage{topic: "homeassistant/update/node-1/" + objectID + "/config", payload: []byte("{}")})
+	}
+	client.onConfig(mqttClient, fakeMessage{topic: "homeassistant/update/node-1/docker_io_library_redis_latest/config"})
+	client.onState(mqttClient, fakeMessage{topic: "diun/node-1/docker_io_library_nginx_latest/state", payload: []byte(`{"latest_version":"sha256:0123456789ab","providers":["file"]}`)})
+	client.onState(mqttClient, fakeMessage{topic: "diun/node-1/docker_io_library_httpd_latest/state", payload: []byte(`{"latest_version":"sha256:0123456789ab","providers":["docker"]}`)})
+
+	// an empty run does not remove any entity
+	require.NoError(t, client.Resolve(nil))
+	mqttClient.published = nil
+
+	entry := testEntry(t)
+	entry.Status = model.ImageStatusUnchange
+	entry.Manifest = registry.Manifest{
+		Digest: "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+	}
+	require.NoError(t, client.Resolve([]model.NotifEntry{entry}))
+
+	topics := make(map[string]string)
+	for _, message := range mqttClient.published {
+		assert.True(t, message.retained)
+		topics[message.topic] = string(message.payload)
+	}
+	require.Len(t, topics, 4)
+	assert.Contains(t, topics["homeassistant/update/node-1/docker_io_library_alpine_latest/config"], `"unique_id":"diun_node-1_docker_io_library_alpine_latest"`)
+	assert.Contains(t, topics["diun/node-1/docker_io_library_alpine_latest/state"], `"installed_version":"sha256:fedcba987654","latest_version":"sha256:fedcba987654"`)
+	assert.Contains(t, topics["diun/node-1/docker_io_library_alpine_latest/state"], `"providers":["file"]`)
+	assert.Empty(t, topics["homeassistant/update/node-1/docker_io_library_nginx_latest/config"])
+	assert.Contains(t, topics, "diun/node-1/docker_io_library_nginx_latest/state")
+	assert.Empty(t, topics["diun/node-1/docker_io_library_nginx_latest/state"])
+	// entities of providers that did not complete the run or without known providers are kept
+	assert.ElementsMatch(t, []string{"docker_io_library_alpine_latest", "docker_io_library_httpd_latest", "docker_io_library_mysql_latest"}, client.entityIDs())
+
+	// entities already known are left untouched
+	mqttClient.published = nil
+	require.NoError(t, client.Resolve([]model.NotifEntry{entry}))
+	assert.Empty(t, mqttClient.published)
+}
+
+func TestResolveHomeAssistantKeepsEntitiesOfFailedProviders(t *testing.T) {
+	retainedDelay := haRetainedDelay
+	haRetainedDelay = 0
+	t.Cleanup(func() {
+		haRetainedDelay = retainedDelay
+	})
+
+	mqttClient := &fakeMQTTClient{connected: true}
+	client := newTestClient(mqttClient)
+	client.cfg.HomeAssistant = (&model.NotifMqttHomeAssistant{}).GetDefaults()
+	client.onConnect(mqttClient)
+
+	client.onConfig(mqttClient, fakeMessage{topic: "homeassistant/update/node-1/docker_io_library_alpine_latest/config", payload: []byte("{}")})
+	client.onState(mqttClient, fakeMessage{topic: "diun/node-1/docker_io_library_alpine_latest/state", payload: []byte(`{"latest_version":"sha256:fedcba987654","providers":["file"]}`)})
+	client.onConfig(mqttClient, fakeMessage{topic: "homeassistant/update/node-1/docker_io_library_nginx_latest/config", payload: []byte("{}")})
+	client.onState(mqttClient, fakeMessage{topic: "diun/node-1/docker_io_library_nginx_latest/state", payload: []byte(`{"latest_version":"sha256:0123456789ab","providers":["file"]}`)})
+
+	// the file provider reported an error, so it did not complete the run
+	entry := testEntry(t)
+	entry.Status = model.ImageStatusError
+	mqttClient.published = nil
+	require.NoError(t, client.Resolve([]model.NotifEntry{entry}))
+	assert.Empty(t, mqttClient.published)
+	assert.ElementsMatch(t, []string{"docker_io_library_alpine_latest", "docker_io_library_nginx_latest"}, client.entityIDs())
+
+	// the image is now also reported by another provider
+	entry.Status = model.ImageStatusUnchange
+	entry.Manifest = registry.Manifest{
+		Digest: "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+	}
+	swarmEntry := entry
+	swarmEntry.Provider = "swarm"
+	require.NoError(t, client.Resolve([]model.NotifEntry{entry, swarmEntry}))
+	require.Len(t, mqttClient.published, 3)
+	assert.Equal(t, "diun/node-1/docker_io_library_alpine_latest/state", mqttClient.published[0].topic)
+	assert.JSONEq(t, `{"latest_version":"sha256:fedcba987654","title":"","providers":["file","swarm"]}`, string(mqttClient.published[0].payload))
+	assert.ElementsMatch(t, []string{"docker_io_library_alpine_latest"}, client.entityIDs())
+}
+
+func TestCloseMarksHomeAssistantUnavailable(t *testing.T) {
+	mqttClient := &fakeMQTTClient{connected: true}
+	client := newTestClient(mqttClient)
+	client.cfg.HomeAssistant = (&model.NotifMqttHomeAssistant{}).GetDefaults()
+
+	require.NoError(t, client.Close())
+	assert.Equal(t, "diun/node-1/status", mqttClient.topic)
+	assert.True(t, mqttClient.retained)
+	assert.Equal(t, []byte("offline"), mqttClient.payload)
+	assert.False(t, mqttClient.connected)
+}
+
 type fakeMQTTClient struct {
-	connected    bool
-	connectCalls int
-	publishCalls int
-	connectErr   error
-	publishErr   error
-	topic        string
-	qos          byte
-	retained     bool
-	payload      []byte
+	connected     bool
+	connectCalls  int
+	publishCalls  int
+	connectErr    error
+	publishErr    error
+	topic         string
+	qos           byte
+	retained      bool
+	payload       []byte
+	published     []fakeMessage
+	subscriptions []string
 }
 
 func (c *fakeMQTTClient) IsConnected() bool {
@@ -98,11 +287,18 @@ func (c *fakeMQTTClient) Publish(topic string, qos byte, retained bool, payload
 	c.qos = qos
 	c.retained = retained
 	c.payload, _ = payload.([]byte)
+	c.published = append(c.published, fakeMessage{
+		topic:    topic,
+		qos:      qos,
+		retained: retained,
+		payload:  c.payload,
+	})
 	return fakeToken{err: c.publishErr}
 }
 
-func (c *fakeMQTTClient) Subscribe(string, byte, MQTT.MessageHandler) MQTT.Token {
-	panic("not implemented")
+func (c *fakeMQTTClient) Subscribe(topic string, _ byte, _ MQTT.MessageHandler) MQTT.Token {
+	c.subscriptions = append(c.subscriptions, topic)
+	return fakeToken{}
 }
 
 func (c *fakeMQTTClient) SubscribeMultiple(map[string]byte, MQTT.MessageHandler) MQTT.Token {
@@ -121,6 +317,39 @@ func (c *fakeMQTTClient) OptionsReader() MQTT.ClientOptionsReader {
 	return MQTT.NewOptionsReader(MQTT.NewClientOptions())
 }
 
+type fakeMessage struct {
+	topic    string
+	qos      byte
+	retained bool
+	payload  []byte
+}
+
+func (m fakeMessage) Duplicate() bool {
+	return false
+}
+
+func (m fakeMessage) Qos() byte {
+	return m.qos
+}
+
+func (m fakeMessage) Retained() bool {
+	return m.retained
+}
+
+func (m fakeMessage) Topic() string {
+	return m.topic
+}
+
+func (m fakeMessage) MessageID() uint16 {
+	return 0
+}
+
+func (m fakeMessage) Payload() []byte {
+	return m.payload
+}
+
+func (m fakeMessage) Ack() {}
+
 type fakeToken struct {
 	err error
 }
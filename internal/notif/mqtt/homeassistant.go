@@ -0,0 +1,444 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// Home Assistant availability payloads
+const (
+	haPayloadAvailable    = "online"
+	haPayloadNotAvailable = "offline"
+)
+
+// haReleaseSummaryMaxLength is the maximum length of the release summary
+// accepted by Home Assistant update entities
+const haReleaseSummaryMaxLength = 255
+
+// Delays to learn the update entities retained by the broker once connected
+var (
+	haSubscribeTimeout = 10 * time.Second
+	haRetainedDelay    = time.Second
+)
+
+var haInvalidIDChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// haConfig is the discovery payload of an update entity
+// https://www.home-assistant.io/integrations/update.mqtt/
+type haConfig struct {
+	Name                string   `json:"name"`
+	UniqueID            string   `json:"unique_id"`
+	ObjectID            string   `json:"object_id"`
+	StateTopic          string   `json:"state_topic"`
+	AvailabilityTopic   string   `json:"availability_topic"`
+	PayloadAvailable    string   `json:"payload_available"`
+	PayloadNotAvailable string   `json:"payload_not_available"`
+	EntityPicture       string   `json:"entity_picture,omitempty"`
+	Device              haDevice `json:"device"`
+	Origin              haOrigin `json:"origin"`
+}
+
+type haDevice struct {
+	Identifiers      []string `json:"identifiers"`
+	Name             string   `json:"name"`
+	Manufacturer     string   `json:"manufacturer"`
+	SwVersion        string   `json:"sw_version"`
+	ConfigurationURL string   `json:"configuration_url,omitempty"`
+}
+
+type haOrigin struct {
+	Name       string `json:"name"`
+	SwVersion  string `json:"sw_version"`
+	SupportURL string `json:"support_url,omitempty"`
+}
+
+// haState is the state payload of an update entity
+type haState struct {
+	InstalledVersion string `json:"installed_version,omitempty"`
+	LatestVersion    string `json:"latest_version"`
+	Title            string `json:"title"`
+	ReleaseURL       string `json:"release_url,omitempty"`
+	ReleaseSummary   string `json:"release_summary,omitempty"`
+
+	// Providers lists the providers reporting the image. It is ignored by
+	// Home Assistant and only read back by Diun to know which providers must
+	// complete a run before the entity can be removed.
+	Providers []string `json:"providers,omitempty"`
+}
+
+// Resolve creates the update entities of images that do not have one yet and
+// removes the ones of images no longer reported by their providers. An entity
+// is only removed if all its providers completed the run, so images of a
+// provider failing to list them are kept.
+func (c *Client) Resolve(entries []model.NotifEntry) error {
+	if c.cfg.HomeAssistant == nil || len(entries) == 0 {
+		return nil
+	}
+	if err := c.connect(); err != nil {
+		return err
+	}
+	if err := c.waitRetained(); err != nil {
+		return err
+	}
+
+	completed := notifier.CompletedProviders(entries)
+
+	var objectIDs []string
+	latest := make(map[string]model.NotifEntry)
+	reporting := make(map[string][]string)
+	for _, entry := range entries {
+		objectID := haObjectID(entry.Image)
+		if _, ok := reporting[objectID]; !ok {
+			objectIDs = append(objectIDs, objectID)
+		}
+		reporting[objectID] = append(reporting[objectID], notifier.Providers(entry)...)
+		if _, ok := latest[objectID]; !ok && entry.Manifest.Digest != "" {
+			latest[objectID] = entry
+		}
+	}
+
+	for _, objectID := range objectIDs {
+		state, known := c.entityState(objectID)
+		// providers that did not complete the run may still use the image
+		providers := reporting[objectID]
+		for _, provider := range state.Providers {
+			if !completed[provider] {
+				providers = append(providers, provider)
+			}
+		}
+		providers = haSortedUnique(providers)
+
+		if !c.hasEntity(objectID) {
+			entry, ok := latest[objectID]
+			if !ok {
+				continue
+			}
+			if err := c.publishEntity(objectID, entry, haVersion(entry.Manifest), providers); err != nil {
+				return err
+			}
+			continue
+		}
+		if !known || slices.Equal(state.Providers, providers) {
+			continue
+		}
+		state.Providers = providers
+		if err := c.publishState(objectID, state); err != nil {
+			return err
+		}
+	}
+
+	for _, objectID := range c.entityIDs() {
+		if _, ok := reporting[objectID]; ok {
+			continue
+		}
+		state, _ := c.entityState(objectID)
+		if len(state.Providers) == 0 || slices.ContainsFunc(state.Providers, func(provider string) bool {
+			return !completed[provider]
+		}) {
+			log.Debug().Str("entity", objectID).Strs("providers", state.Providers).
+				Msg("Keeping Home Assistant update entity of providers that did not complete the run")
+			continue
+		}
+		log.Debug().Str("entity", objectID).Msg("Removing Home Assistant update entity")
+		if err := c.publish(c.stateTopic(objectID), true, nil); err != nil {
+			return errors.Wrapf(err, "cannot clear state of %s", objectID)
+		}
+		if err := c.publish(c.configTopic(objectID), true, nil); err != nil {
+			return errors.Wrapf(err, "cannot remove %s", objectID)
+		}
+		c.setEntity(objectID, false)
+		c.setEntityState(objectID, nil)
+	}
+
+	return nil
+}
+
+// publishUpdate publishes the update entity of a new or updated image. The
+// installed version of an updated image is its previous digest.
+func (c *Client) publishUpdate(entry model.NotifEntry) error {
+	installed := haVersion(entry.Manifest)
+	if entry.Status == model.ImageStatusUpdate {
+		installed = ""
+		if entry.Diff != nil && entry.Diff.Digest != nil && entry.Diff.Digest.From != "" {
+			installed = haShortDigest(entry.Diff.Digest.From)
+		}
+	}
+	objectID := haObjectID(entry.Image)
+	state, _ := c.entityState(objectID)
+	return c.publishEntity(objectID, entry, installed, haSortedUnique(append(notifier.Providers(entry), state.Providers...)))
+}
+
+func (c *Client) publishEntity(objectID string, entry model.NotifEntry, installed string, providers []string) error {
+	config, err := json.Marshal(c.entityConfig(objectID, entry))
+	if err != nil {
+		return err
+	}
+	if err := c.publish(c.configTopic(objectID), true, config); err != nil {
+		return errors.Wrapf(err, "cannot publish discovery config of %s", objectID)
+	}
+	c.setEntity(objectID, true)
+
+	return c.publishState(objectID, haState{
+		InstalledVersion: installed,
+		LatestVersion:    haVersion(entry.Manifest),
+		Title:            entry.Image.String(),
+		ReleaseURL:       haReleaseURL(entry),
+		ReleaseSummary:   haReleaseSummary(entry),
+		Providers:        providers,
+	})
+}
+
+func (c *Client) publishState(objectID string, state haState) error {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := c.publish(c.stateTopic(objectID), true, payload); err != nil {
+		return errors.Wrapf(err, "cannot publish state of %s", objectID)
+	}
+	c.setEntityState(objectID, &state)
+	return nil
+}
+
+func (c *Client) entityConfig(objectID string, entry model.NotifEntry) haConfig {
+	nodeID := c.nodeID()
+	return haConfig{
+		Name:                entry.Image.String(),
+		UniqueID:            fmt.Sprintf("diun_%s_%s", nodeID, objectID),
+		ObjectID:            objectID,
+		StateTopic:          c.stateTopic(objectID),
+		AvailabilityTopic:   c.availabilityTopic(),
+		PayloadAvailable:    haPayloadAvailable,
+		PayloadNotAvailable: haPayloadNotAvailable,
+		EntityPicture:       c.meta.Logo,
+		Device: haDevice{
+			Identifiers:      []string{fmt.Sprintf("diun_%s", nodeID)},
+			Name:             fmt.Sprintf("%s (%s)", c.meta.Name, c.meta.Hostname),
+			Manufacturer:     c.meta.Author,
+			SwVersion:        c.meta.Version,
+			ConfigurationURL: c.meta.URL,
+		},
+		Origin: haOrigin{
+			Name:       c.meta.Name,
+			SwVersion:  c.meta.Version,
+			SupportURL: c.meta.URL,
+		},
+	}
+}
+
+// onConnect marks Diun as available and subscribes to the discovery and state
+// topics of its update entities to learn the ones retained by the broker. It
+// is called in its own goroutine on each connection.
+func (c *Client) onConnect(client MQTT.Client) {
+	if token := client.Publish(c.availabilityTopic(), byte(c.cfg.QoS), true, []byte(haPayloadAvailable)); token.Wait() && token.Error() != nil {
+		log.Error().Err(token.Error()).Msg("Cannot publish Home Assistant availability")
+	}
+	if token := client.Subscribe(c.configTopic("+"), byte(c.cfg.QoS), c.onConfig); token.Wait() && token.Error() != nil {
+		log.Error().Err(token.Error()).Msg("Cannot subscribe to Home Assistant discovery topics")
+		return
+	}
+	if token := client.Subscribe(c.stateTopic("+"), byte(c.cfg.QoS), c.onState); token.Wait() && token.Error() != nil {
+		log.Error().Err(token.Error()).Msg("Cannot subscribe to Home Assistant state topics")
+		return
+	}
+
+	subscribed := c.subscribedChan()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subscribedAt.IsZero() {
+		close(subscribed)
+	}
+	c.subscribedAt = time.Now()
+}
+
+// waitRetained waits for the retained configs and states to be delivered after
+// subscribing, so existing update entities are not overwritten or left behind
+func (c *Client) waitRetained() error {
+	select {
+	case <-c.subscribedChan():
+	case <-time.After(haSubscribeTimeout):
+		return errors.New("timed out waiting for Home Assistant discovery subscription")
+	}
+	c.mu.Lock()
+	wait := haRetainedDelay - time.Since(c.subscribedAt)
+	c.mu.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return nil
+}
+
+func (c *Client) subscribedChan() chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subscribed == nil {
+		c.subscribed = make(chan struct{})
+	}
+	return c.subscribed
+}
+
+// onConfig tracks the update entities retained by the broker. An empty
+// payload means the entity has been removed.
+func (c *Client) onConfig(_ MQTT.Client, message MQTT.Message) {
+	parts := strings.Split(message.Topic(), "/")
+	if len(parts) < 2 {
+		return
+	}
+	c.setEntity(parts[len(parts)-2], len(message.Payload()) > 0)
+}
+
+// onState tracks the states of the update entities retained by the broker. An
+// empty payload means the state has been cleared.
+func (c *Client) onState(_ MQTT.Client, message MQTT.Message) {
+	parts := strings.Split(message.Topic(), "/")
+	if len(parts) < 2 {
+		return
+	}
+	objectID := parts[len(parts)-2]
+	if len(message.Payload()) == 0 {
+		c.setEntityState(objectID, nil)
+		return
+	}
+	var state haState
+	if err := json.Unmarshal(message.Payload(), &state); err != nil {
+		log.Warn().Err(err).Str("entity", objectID).Msg("Cannot decode Home Assistant update entity state")
+		return
+	}
+	c.setEntityState(objectID, &state)
+}
+
+func (c *Client) hasEntity(objectID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entities[objectID]
+}
+
+func (c *Client) setEntity(objectID string, exists bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !exists {
+		delete(c.entities, objectID)
+		return
+	}
+	if c.entities == nil {
+		c.entities = make(map[string]bool)
+	}
+	c.entities[objectID] = true
+}
+
+func (c *Client) entityState(objectID string) (haState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state, ok := c.states[objectID]
+	return state, ok
+}
+
+func (c *Client) setEntityState(objectID string, state *haState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if state == nil {
+		delete(c.states, objectID)
+		return
+	}
+	if c.states == nil {
+		c.states = make(map[string]haState)
+	}
+	c.states[objectID] = *state
+}
+
+func (c *Client) entityIDs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := make([]string, 0, len(c.entities))
+	for objectID := range c.entities {
+		ids = append(ids, objectID)
+	}
+	return ids
+}
+
+func (c *Client) nodeID() string {
+	if c.cfg.HomeAssistant.NodeID != "" {
+		return c.cfg.HomeAssistant.NodeID
+	}
+	return haID(c.meta.Hostname)
+}
+
+func (c *Client) configTopic(objectID string) string {
+	return fmt.Sprintf("%s/update/%s/%s/config", c.cfg.HomeAssistant.DiscoveryPrefix, c.nodeID(), objectID)
+}
+
+func (c *Client) stateTopic(objectID string) string {
+	return fmt.Sprintf("%s/%s/%s/state", c.cfg.HomeAssistant.BaseTopic, c.nodeID(), objectID)
+}
+
+func (c *Client) availabilityTopic() string {
+	return fmt.Sprintf("%s/%s/status", c.cfg.HomeAssistant.BaseTopic, c.nodeID())
+}
+
+// haID sanitizes a value to be used as a node or object ID
+func haID(value string) string {
+	return strings.Trim(haInvalidIDChars.ReplaceAllString(strings.ToLower(value), "_"), "_")
+}
+
+func haObjectID(image registry.Image) string {
+	return haID(image.String())
+}
+
+// haVersion returns the version of a manifest as reported to Home Assistant.
+// The digest algorithm is kept so short digests are not compared as numbers.
+func haVersion(manifest registry.Manifest) string {
+	return haShortDigest(manifest.Digest.String())
+}
+
+func haShortDigest(dgst string) string {
+	algorithm, encoded, ok := strings.Cut(dgst, ":")
+	if !ok || len(encoded) <= 12 {
+		return dgst
+	}
+	return fmt.Sprintf("%s:%s", algorithm, encoded[:12])
+}
+
+func haSortedUnique(values []string) []string {
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return slices.Compact(values)
+}
+
+func haReleaseURL(entry model.NotifEntry) string {
+	if entry.Links != nil && entry.Links.Release != "" {
+		return entry.Links.Release
+	}
+	return entry.Image.HubLink
+}
+
+func haReleaseSummary(entry model.NotifEntry) string {
+	var summary string
+	if entry.Diff != nil && entry.Diff.Version != nil {
+		summary = fmt.Sprintf("Version %s → %s", entry.Diff.Version.From, entry.Diff.Version.To)
+	} else if version := entry.Manifest.Labels[registry.LabelVersion]; version != "" {
+		summary = fmt.Sprintf("Version %s", version)
+	}
+	if entry.Manifest.Created != nil {
+		created := entry.Manifest.Created.Format("2006-01-02")
+		if summary == "" {
+			summary = fmt.Sprintf("Created %s", created)
+		} else {
+			summary = fmt.Sprintf("%s (created %s)", summary, created)
+		}
+	}
+	if runes := []rune(summary); len(runes) > haReleaseSummaryMaxLength {
+		summary = string(runes[:haReleaseSummaryMaxLength])
+	}
+	return summary
+}
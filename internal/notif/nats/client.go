@@ -0,0 +1,134 @@
+package nats
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+)
+
+// Client represents an active nats notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifNats
+	meta model.Meta
+}
+
+// New creates a new nats notification instance
+func New(config *model.NotifNats, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "nats"
+}
+
+// Send creates and sends a nats notification with an entry
+func (c *Client) Send(entry model.NotifEntry) error {
+	opts, err := c.options()
+	if err != nil {
+		return err
+	}
+
+	message, err := msg.New(msg.Options{
+		Meta:  c.meta,
+		Entry: entry,
+	})
+	if err != nil {
+		return err
+	}
+
+	subject, err := message.RenderTemplate("subject", c.cfg.Subject)
+	if err != nil {
+		return err
+	}
+	body, err := message.RenderJSON()
+	if err != nil {
+		return err
+	}
+
+	natsMsg := nats.NewMsg(strings.TrimSpace(string(subject)))
+	natsMsg.Data = body
+	natsMsg.Header.Set("Diun-Status", string(entry.Status))
+	natsMsg.Header.Set("Diun-Provider", entry.Provider)
+	natsMsg.Header.Set("Diun-Image", entry.Image.String())
+
+	nc, err := nats.Connect(c.cfg.URL, opts...)
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to NATS server")
+	}
+	defer nc.Close()
+
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	defer func() { cancelCause(errors.WithStack(context.Canceled)) }()
+	timeoutCtx, cancel := context.WithTimeoutCause(cancelCtx, *c.cfg.Timeout, errors.WithStack(context.DeadlineExceeded))
+	defer cancel()
+
+	if c.cfg.JetStream {
+		js, err := nc.JetStream()
+		if err != nil {
+			return err
+		}
+		_, err = js.PublishMsg(natsMsg, nats.Context(timeoutCtx))
+		return errors.Wrapf(err, "cannot publish to JetStream subject %s", natsMsg.Subject)
+	}
+
+	if err := nc.PublishMsg(natsMsg); err != nil {
+		return errors.Wrapf(err, "cannot publish to subject %s", natsMsg.Subject)
+	}
+	return nc.FlushWithContext(timeoutCtx)
+}
+
+// options returns the connection options of the NATS client
+func (c *Client) options() ([]nats.Option, error) {
+	opts := []nats.Option{
+		nats.Name(c.meta.Name),
+		nats.Timeout(*c.cfg.Timeout),
+	}
+
+	username, err := secret.GetSecret(c.cfg.Username, c.cfg.UsernameFile)
+	if err != nil {
+		return nil, err
+	}
+	password, err := secret.GetSecret(c.cfg.Password, c.cfg.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+	if username != "" {
+		opts = append(opts, nats.UserInfo(username, password))
+	}
+
+	token, err := secret.GetSecret(c.cfg.Token, c.cfg.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		opts = append(opts, nats.Token(token))
+	}
+
+	if c.cfg.CredentialsFile != "" {
+		opts = append(opts, nats.UserCredentials(c.cfg.CredentialsFile))
+	}
+
+	if c.cfg.TLSSkipVerify || len(c.cfg.TLSCACertFiles) > 0 {
+		tlsConfig, err := httputil.LoadTLSConfig(c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot load TLS configuration")
+		}
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	return opts, nil
+}
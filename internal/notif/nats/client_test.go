@@ -0,0 +1,123 @@
+package nats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	server := newFakeServer(t)
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifNats{
+			URL:      "nats://" + server.addr,
+			Subject:  "diun.{{ .Entry.Status }}",
+			Username: "user",
+			Password: "pass",
+			Timeout:  new(5 * time.Second),
+		},
+		meta: model.Meta{
+			Name:     "Diun",
+			Hostname: "myserver",
+		},
+	}
+
+	require.NoError(t, c.Send(model.NotifEntry{
+		Status:   model.ImageStatusNew,
+		Provider: "file",
+		Image:    image,
+		Manifest: registry.Manifest{
+			Name:   "docker.io/crazymax/diun",
+			Tag:    "latest",
+			Digest: "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+		},
+	}))
+
+	msg := <-server.msgs
+	assert.Equal(t, "Diun", server.connect["name"])
+	assert.Equal(t, "user", server.connect["user"])
+	assert.Equal(t, "pass", server.connect["pass"])
+	assert.Equal(t, "diun.new", msg.subject)
+	assert.Equal(t, "new", msg.header.Get("Diun-Status"))
+	assert.Equal(t, "file", msg.header.Get("Diun-Provider"))
+	assert.Equal(t, "docker.io/crazymax/diun:latest", msg.header.Get("Diun-Image"))
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(msg.data, &payload))
+	assert.Equal(t, "docker.io/crazymax/diun:latest", payload["image"])
+	assert.Equal(t, "myserver", payload["hostname"])
+}
+
+type published struct {
+	subject string
+	header  textproto.MIMEHeader
+	data    []byte
+}
+
+type fakeServer struct {
+	addr    string
+	connect map[string]any
+	msgs    chan published
+}
+
+// newFakeServer starts a server speaking just enough of the NATS client
+// protocol to receive published messages.
+func newFakeServer(t *testing.T) *fakeServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	s := &fakeServer{addr: ln.Addr().String(), msgs: make(chan published, 1)}
+	go func() {
+		cn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer cn.Close()
+		_, _ = fmt.Fprint(cn, "INFO {\"server_id\":\"test\",\"version\":\"2.10.0\",\"proto\":1,\"headers\":true,\"max_payload\":1048576}\r\n")
+
+		r := bufio.NewReader(cn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			op, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+			switch op {
+			case "CONNECT":
+				_ = json.Unmarshal([]byte(args), &s.connect)
+			case "PING":
+				_, _ = fmt.Fprint(cn, "PONG\r\n")
+			case "HPUB":
+				fields := strings.Fields(args)
+				hdrLen, _ := strconv.Atoi(fields[len(fields)-2])
+				totalLen, _ := strconv.Atoi(fields[len(fields)-1])
+				buf := make([]byte, totalLen+2)
+				if _, err := io.ReadFull(r, buf); err != nil {
+					return
+				}
+				hr := textproto.NewReader(bufio.NewReader(strings.NewReader(string(buf[:hdrLen]))))
+				_, _ = hr.ReadLine()
+				header, _ := hr.ReadMIMEHeader()
+				s.msgs <- published{subject: fields[0], header: header, data: buf[hdrLen:totalLen]}
+			}
+		}
+	}()
+	return s
+}
@@ -0,0 +1,24 @@
+package notifier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+)
+
+// maxDedupKeyLength is the maximum length of a dedup key accepted by all
+// incident management services
+const maxDedupKeyLength = 255
+
+// DedupKey returns a stable key identifying the alerts of an image, so
+// repeated runs update a single alert instead of opening new ones. The image
+// reference is hashed if too long.
+func DedupKey(entry model.NotifEntry) string {
+	key := "diun/" + entry.Image.String()
+	if len(key) > maxDedupKeyLength {
+		sum := sha256.Sum256([]byte(entry.Image.String()))
+		key = "diun/" + hex.EncodeToString(sum[:])
+	}
+	return key
+}
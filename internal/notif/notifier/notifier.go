@@ -10,6 +10,12 @@ type Handler interface {
 	Send(entry model.NotifEntry) error
 }
 
+// Resolver is implemented by handlers tracking the notifications of an image
+// until it is no longer reported. It is called with all the entries of a run.
+type Resolver interface {
+	Resolve(entries []model.NotifEntry) error
+}
+
 // Notifier represents an active notifier object
 type Notifier struct {
 	Handler
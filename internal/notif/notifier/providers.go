@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"slices"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+)
+
+// Providers returns the sorted providers of an entry and its consumers
+func Providers(entry model.NotifEntry) []string {
+	var providers []string
+	if entry.Provider != "" {
+		providers = append(providers, entry.Provider)
+	}
+	for _, consumer := range entry.Consumers {
+		if consumer.Provider != "" {
+			providers = append(providers, consumer.Provider)
+		}
+	}
+	slices.Sort(providers)
+	return slices.Compact(providers)
+}
+
+// CompletedProviders returns the providers that completed a run, i.e. that
+// reported images and none of them failed to be analyzed. A provider failing
+// to list its images reports none, so images no longer reported can only be
+// considered gone for the providers of this set.
+func CompletedProviders(entries []model.NotifEntry) map[string]bool {
+	completed := make(map[string]bool)
+	for _, entry := range entries {
+		for _, provider := range Providers(entry) {
+			if ok, found := completed[provider]; !found || ok {
+				completed[provider] = entry.Status != model.ImageStatusError
+			}
+		}
+	}
+	return completed
+}
@@ -0,0 +1,199 @@
+package opsgenie
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+)
+
+// Opsgenie alert field limits
+const (
+	maxMessageLength     = 130
+	maxDescriptionLength = 15000
+	maxDetailLength      = 8000
+)
+
+// Client represents an active Opsgenie notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifOpsgenie
+	meta model.Meta
+}
+
+// New creates a new Opsgenie notification instance
+func New(config *model.NotifOpsgenie, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "opsgenie"
+}
+
+// Alert is an Opsgenie alert
+type Alert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Responders  []Responder       `json:"responders,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Entity      string            `json:"entity,omitempty"`
+	Source      string            `json:"source,omitempty"`
+	Priority    string            `json:"priority,omitempty"`
+}
+
+// Responder is a team responsible for an Opsgenie alert
+type Responder struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Send creates and sends an Opsgenie alert with an entry. Alerts of an image
+// share the same alias so repeated runs update a single alert.
+func (c *Client) Send(entry model.NotifEntry) error {
+	apiKey, err := secret.GetSecret(c.cfg.APIKey, c.cfg.APIKeyFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve API key secret for Opsgenie notifier")
+	} else if apiKey == "" {
+		return errors.New("Opsgenie API key cannot be empty")
+	}
+
+	message, err := msg.New(msg.Options{
+		Meta:          c.meta,
+		Entry:         entry,
+		TemplateTitle: c.cfg.TemplateTitle,
+		TemplateBody:  c.cfg.TemplateBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	title, body, err := message.RenderMarkdown()
+	if err != nil {
+		return err
+	}
+	details, err := c.details(message)
+	if err != nil {
+		return err
+	}
+
+	alert := Alert{
+		Message:     truncate(string(title), maxMessageLength),
+		Alias:       notifier.DedupKey(entry),
+		Description: truncate(string(body), maxDescriptionLength),
+		Tags:        c.cfg.Tags,
+		Details:     details,
+		Entity:      entry.Image.Name(),
+		Source:      c.meta.Hostname,
+		Priority:    c.priority(entry.Status),
+	}
+	for _, team := range c.cfg.Responders {
+		alert.Responders = append(alert.Responders, Responder{Name: team, Type: "team"})
+	}
+
+	dataBuf := new(bytes.Buffer)
+	if err := json.NewEncoder(dataBuf).Encode(alert); err != nil {
+		return err
+	}
+
+	u, err := url.JoinPath(c.cfg.Endpoint, "/v2/alerts")
+	if err != nil {
+		return err
+	}
+
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	defer func() { cancelCause(errors.WithStack(context.Canceled)) }()
+	timeoutCtx, cancel := context.WithTimeoutCause(cancelCtx, *c.cfg.Timeout, errors.WithStack(context.DeadlineExceeded))
+	defer cancel()
+
+	hc, err := httputil.NewClient(c.cfg.Proxy, c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return errors.Wrap(err, "cannot create HTTP client for Opsgenie notifier")
+	}
+
+	req, err := http.NewRequestWithContext(timeoutCtx, "POST", u, dataBuf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", apiKey))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.meta.UserAgent)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		var errBody struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errBody); err != nil {
+			return errors.Wrapf(err, "cannot decode JSON error response for HTTP %d %s status", resp.StatusCode, http.StatusText(resp.StatusCode))
+		}
+		return errors.Errorf("%d %s: %s", resp.StatusCode, http.StatusText(resp.StatusCode), errBody.Message)
+	}
+
+	return nil
+}
+
+// details returns the custom details of an alert from the JSON message.
+// Opsgenie only accepts string values, so other values are JSON encoded.
+func (c *Client) details(message *msg.Client) (map[string]string, error) {
+	raw, err := message.RenderJSON()
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	details := make(map[string]string, len(fields))
+	for key, value := range fields {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(value)
+		}
+		if s == "" || s == "null" {
+			continue
+		}
+		details[key] = truncate(s, maxDetailLength)
+	}
+	return details, nil
+}
+
+// priority returns the priority of an alert for an image status
+func (c *Client) priority(status model.ImageStatus) string {
+	if priority, ok := c.cfg.Priority[string(status)]; ok {
+		return priority
+	}
+	if priority, ok := (&model.NotifOpsgenie{}).GetDefaults().Priority[string(status)]; ok {
+		return priority
+	}
+	return "P3"
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,108 @@
+package opsgenie
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	var alerts []Alert
+	var gotPath, gotAuth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		var alert Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		alerts = append(alerts, alert)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifOpsgenie{
+			Endpoint: ts.URL,
+			APIKey:   "4P1K3Y",
+			Priority: map[string]string{
+				string(model.ImageStatusUpdate): "P2",
+			},
+			Tags:          []string{"diun", "docker"},
+			Responders:    []string{"ops"},
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: "{{ .Entry.Image }} {{ .Entry.Status }}",
+			TemplateBody:  "Provider: {{ .Entry.Provider }}",
+		},
+		meta: model.Meta{
+			Hostname:  "myserver",
+			UserAgent: "diun-test",
+		},
+	}
+
+	for _, status := range []model.ImageStatus{model.ImageStatusNew, model.ImageStatusUpdate} {
+		require.NoError(t, c.Send(model.NotifEntry{
+			Status:   status,
+			Provider: "docker",
+			Image:    image,
+			Manifest: registry.Manifest{
+				Name:   "docker.io/crazymax/diun",
+				Tag:    "latest",
+				Digest: "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+			},
+		}))
+	}
+
+	assert.Equal(t, "/v2/alerts", gotPath)
+	assert.Equal(t, "GenieKey 4P1K3Y", gotAuth)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, alerts[0].Alias, alerts[1].Alias)
+	assert.Equal(t, "diun/docker.io/crazymax/diun:latest", alerts[0].Alias)
+	assert.Equal(t, "docker.io/crazymax/diun:latest new", alerts[0].Message)
+	assert.Equal(t, "Provider: docker", alerts[0].Description)
+	assert.Equal(t, "P5", alerts[0].Priority)
+	assert.Equal(t, "P2", alerts[1].Priority)
+	assert.Equal(t, []string{"diun", "docker"}, alerts[1].Tags)
+	assert.Equal(t, []Responder{{Name: "ops", Type: "team"}}, alerts[1].Responders)
+	assert.Equal(t, "docker.io/crazymax/diun", alerts[1].Entity)
+	assert.Equal(t, "myserver", alerts[1].Source)
+	assert.Equal(t, "docker.io/crazymax/diun:latest", alerts[1].Details["image"])
+	assert.Equal(t, "update", alerts[1].Details["status"])
+}
+
+func TestSendError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Key format is not valid!","took":0.001,"requestId":"a1b2"}`))
+	}))
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifOpsgenie{
+			Endpoint:      ts.URL,
+			APIKey:        "foo",
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: model.NotifDefaultTemplateTitle,
+			TemplateBody:  model.NotifDefaultTemplateBody,
+		},
+	}
+	err = c.Send(model.NotifEntry{
+		Status: model.ImageStatusNew,
+		Image:  image,
+	})
+	require.ErrorContains(t, err, "Key format is not valid!")
+}
@@ -0,0 +1,188 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+)
+
+// maxSummaryLength is the maximum length of an event summary
+const maxSummaryLength = 1024
+
+// Client represents an active PagerDuty notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifPagerDuty
+	meta model.Meta
+}
+
+// New creates a new PagerDuty notification instance
+func New(config *model.NotifPagerDuty, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "pagerduty"
+}
+
+// Event is a PagerDuty Events API v2 event
+type Event struct {
+	RoutingKey  string       `json:"routing_key"`
+	EventAction string       `json:"event_action"`
+	DedupKey    string       `json:"dedup_key"`
+	Payload     EventPayload `json:"payload"`
+	Client      string       `json:"client,omitempty"`
+	ClientURL   string       `json:"client_url,omitempty"`
+	Links       []EventLink  `json:"links,omitempty"`
+}
+
+// EventPayload holds the details of a PagerDuty event
+type EventPayload struct {
+	Summary       string          `json:"summary"`
+	Source        string          `json:"source"`
+	Severity      string          `json:"severity"`
+	Timestamp     string          `json:"timestamp,omitempty"`
+	Component     string          `json:"component,omitempty"`
+	Group         string          `json:"group,omitempty"`
+	Class         string          `json:"class,omitempty"`
+	CustomDetails json.RawMessage `json:"custom_details,omitempty"`
+}
+
+// EventLink is a link attached to a PagerDuty event
+type EventLink struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
+}
+
+// Send creates and sends a PagerDuty event with an entry. Events of an image
+// share the same dedup key so repeated runs update a single alert.
+func (c *Client) Send(entry model.NotifEntry) error {
+	routingKey, err := secret.GetSecret(c.cfg.RoutingKey, c.cfg.RoutingKeyFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve routing key secret for PagerDuty notifier")
+	} else if routingKey == "" {
+		return errors.New("PagerDuty routing key cannot be empty")
+	}
+
+	message, err := msg.New(msg.Options{
+		Meta:  c.meta,
+		Entry: entry,
+	})
+	if err != nil {
+		return err
+	}
+
+	title, err := message.RenderTemplate("title", c.cfg.TemplateTitle)
+	if err != nil {
+		return err
+	}
+	customDetails, err := message.RenderJSON()
+	if err != nil {
+		return err
+	}
+
+	source := c.cfg.Source
+	if source == "" {
+		source = c.meta.Hostname
+	}
+
+	event := Event{
+		RoutingKey:  routingKey,
+		EventAction: "trigger",
+		DedupKey:    notifier.DedupKey(entry),
+		Payload: EventPayload{
+			Summary:       truncate(string(title), maxSummaryLength),
+			Source:        source,
+			Severity:      c.severity(entry.Status),
+			Component:     entry.Image.Name(),
+			Group:         entry.Provider,
+			Class:         string(entry.Status),
+			Timestamp:     time.Now().UTC().Format(time.RFC3339),
+			CustomDetails: customDetails,
+		},
+		Client:    c.meta.Name,
+		ClientURL: c.meta.URL,
+	}
+	if entry.Image.HubLink != "" {
+		event.Links = append(event.Links, EventLink{Href: entry.Image.HubLink, Text: "Registry"})
+	}
+	if entry.Links != nil && entry.Links.Release != "" {
+		event.Links = append(event.Links, EventLink{Href: entry.Links.Release, Text: "Release"})
+	}
+
+	dataBuf := new(bytes.Buffer)
+	if err := json.NewEncoder(dataBuf).Encode(event); err != nil {
+		return err
+	}
+
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	defer func() { cancelCause(errors.WithStack(context.Canceled)) }()
+	timeoutCtx, cancel := context.WithTimeoutCause(cancelCtx, *c.cfg.Timeout, errors.WithStack(context.DeadlineExceeded))
+	defer cancel()
+
+	hc, err := httputil.NewClient(c.cfg.Proxy, c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return errors.Wrap(err, "cannot create HTTP client for PagerDuty notifier")
+	}
+
+	req, err := http.NewRequestWithContext(timeoutCtx, "POST", c.cfg.Endpoint, dataBuf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.meta.UserAgent)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		var errBody struct {
+			Status  string   `json:"status"`
+			Message string   `json:"message"`
+			Errors  []string `json:"errors"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errBody); err != nil {
+			return errors.Wrapf(err, "cannot decode JSON error response for HTTP %d %s status", resp.StatusCode, http.StatusText(resp.StatusCode))
+		}
+		return errors.Errorf("%d %s: %s", resp.StatusCode, errBody.Message, strings.Join(errBody.Errors, ", "))
+	}
+
+	return nil
+}
+
+// severity returns the severity of an event for an image status
+func (c *Client) severity(status model.ImageStatus) string {
+	if severity, ok := c.cfg.Severity[string(status)]; ok {
+		return severity
+	}
+	if severity, ok := (&model.NotifPagerDuty{}).GetDefaults().Severity[string(status)]; ok {
+		return severity
+	}
+	return model.PagerDutySeverityInfo
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
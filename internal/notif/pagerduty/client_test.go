@@ -0,0 +1,103 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	var events []Event
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifPagerDuty{
+			Endpoint:   ts.URL,
+			RoutingKey: "R0UT1NGK3Y",
+			Severity: map[string]string{
+				string(model.ImageStatusUpdate): model.PagerDutySeverityCritical,
+			},
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: "{{ .Entry.Image }} {{ .Entry.Status }}",
+		},
+		meta: model.Meta{
+			Hostname:  "myserver",
+			UserAgent: "diun-test",
+		},
+	}
+
+	for _, status := range []model.ImageStatus{model.ImageStatusNew, model.ImageStatusUpdate} {
+		require.NoError(t, c.Send(model.NotifEntry{
+			Status:   status,
+			Provider: "docker",
+			Image:    image,
+			Manifest: registry.Manifest{
+				Name:   "docker.io/crazymax/diun",
+				Tag:    "latest",
+				Digest: "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+			},
+		}))
+	}
+
+	require.Len(t, events, 2)
+	assert.Equal(t, events[0].DedupKey, events[1].DedupKey)
+	assert.Equal(t, "diun/docker.io/crazymax/diun:latest", events[0].DedupKey)
+	assert.Equal(t, "R0UT1NGK3Y", events[0].RoutingKey)
+	assert.Equal(t, "trigger", events[0].EventAction)
+	assert.Equal(t, "docker.io/crazymax/diun:latest new", events[0].Payload.Summary)
+	assert.Equal(t, "myserver", events[0].Payload.Source)
+	timestamp, err := time.Parse(time.RFC3339, events[0].Payload.Timestamp)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), timestamp, time.Minute)
+	assert.Equal(t, model.PagerDutySeverityInfo, events[0].Payload.Severity)
+	assert.Equal(t, model.PagerDutySeverityCritical, events[1].Payload.Severity)
+
+	var details map[string]any
+	require.NoError(t, json.Unmarshal(events[1].Payload.CustomDetails, &details))
+	assert.Equal(t, "docker.io/crazymax/diun:latest", details["image"])
+	assert.Equal(t, "update", details["status"])
+}
+
+func TestSendError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"invalid event","message":"Event object is invalid","errors":["Length of 'routing_key' is incorrect (should be 32 characters)"]}`))
+	}))
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifPagerDuty{
+			Endpoint:      ts.URL,
+			RoutingKey:    "foo",
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: model.NotifDefaultTemplateTitle,
+		},
+	}
+	err = c.Send(model.NotifEntry{
+		Status: model.ImageStatusNew,
+		Image:  image,
+	})
+	require.ErrorContains(t, err, "Event object is invalid")
+}
@@ -76,22 +76,23 @@ func (c *Client) Send(entry model.NotifEntry) error {
 				Value: entry.Provider,
 				Short: false,
 			},
-			{
+		}
+		if entry.Manifest.Created != nil {
+			fields = append(fields, AttachmentField{
 				Title: "Created",
 				Value: entry.Manifest.Created.Format("Jan 02, 2006 15:04:05 UTC"),
 				Short: false,
-			},
-			{
-				Title: "Digest",
-				Value: entry.Manifest.Digest.String(),
-				Short: false,
-			},
-			{
-				Title: "Platform",
-				Value: entry.Manifest.Platform,
-				Short: false,
-			},
+			})
 		}
+		fields = append(fields, AttachmentField{
+			Title: "Digest",
+			Value: entry.Manifest.Digest.String(),
+			Short: false,
+		}, AttachmentField{
+			Title: "Platform",
+			Value: entry.Manifest.Platform,
+			Short: false,
+		})
 		if len(entry.Image.HubLink) > 0 {
 			fields = append(fields, AttachmentField{
 				Title: "HubLink",
@@ -99,6 +100,13 @@ func (c *Client) Send(entry model.NotifEntry) error {
 				Short: false,
 			})
 		}
+		if len(entry.Manifest.ArtifactType) > 0 {
+			fields = append(fields, AttachmentField{
+				Title: "Artifact type",
+				Value: entry.Manifest.ArtifactType,
+				Short: false,
+			})
+		}
 		attachments = append(attachments, Attachment{
 			Text:   string(body),
 			Ts:     json.Number(strconv.FormatInt(time.Now().Unix(), 10)),
@@ -74,22 +74,23 @@ func (c *Client) Send(entry model.NotifEntry) error {
 				Value: entry.Provider,
 				Short: false,
 			},
-			{
+		}
+		if entry.Manifest.Created != nil {
+			fields = append(fields, slack.AttachmentField{
 				Title: "Created",
 				Value: entry.Manifest.Created.Format("Jan 02, 2006 15:04:05 UTC"),
 				Short: false,
-			},
-			{
-				Title: "Digest",
-				Value: entry.Manifest.Digest.String(),
-				Short: false,
-			},
-			{
-				Title: "Platform",
-				Value: entry.Manifest.Platform,
-				Short: false,
-			},
+			})
 		}
+		fields = append(fields, slack.AttachmentField{
+			Title: "Digest",
+			Value: entry.Manifest.Digest.String(),
+			Short: false,
+		}, slack.AttachmentField{
+			Title: "Platform",
+			Value: entry.Manifest.Platform,
+			Short: false,
+		})
 		if len(entry.Image.HubLink) > 0 {
 			fields = append(fields, slack.AttachmentField{
 				Title: "HubLink",
@@ -97,6 +98,13 @@ func (c *Client) Send(entry model.NotifEntry) error {
 				Short: false,
 			})
 		}
+		if len(entry.Manifest.ArtifactType) > 0 {
+			fields = append(fields, slack.AttachmentField{
+				Title: "Artifact type",
+				Value: entry.Manifest.ArtifactType,
+				Short: false,
+			})
+		}
 	}
 
 	color := "#4caf50"
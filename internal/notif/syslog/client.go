@@ -0,0 +1,289 @@
+package syslog
+
+import (
+	"crypto/tls"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/journal"
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/pkg/errors"
+)
+
+// sdID is the structured data ID of the entry. 32473 is the private
+// enterprise number reserved for documentation by RFC 5612.
+const sdID = "diun@32473"
+
+// sdMetadataID is the structured data ID of the entry metadata
+const sdMetadataID = "meta@32473"
+
+var facilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+var severities = map[string]int{
+	"emerg":   0,
+	"alert":   1,
+	"crit":    2,
+	"err":     3,
+	"warning": 4,
+	"notice":  5,
+	"info":    6,
+	"debug":   7,
+}
+
+// Client represents an active syslog notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifSyslog
+	meta model.Meta
+}
+
+// New creates a new syslog notification instance
+func New(config *model.NotifSyslog, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "syslog"
+}
+
+// Send creates and sends a syslog notification with an entry
+// https://datatracker.ietf.org/doc/html/rfc5424
+func (c *Client) Send(entry model.NotifEntry) error {
+	message, err := msg.New(msg.Options{
+		Meta:         c.meta,
+		Entry:        entry,
+		TemplateBody: c.cfg.TemplateBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, body, err := message.RenderMarkdown()
+	if err != nil {
+		return err
+	}
+
+	facility := facilities[c.cfg.Facility]
+	severity := c.severity(entry.Status)
+
+	if err := c.write(c.format(entry, string(body), facility, severity, time.Now())); err != nil {
+		return errors.Wrapf(err, "cannot write syslog message to %s://%s", c.cfg.Network, c.cfg.Address)
+	}
+
+	if *c.cfg.Journald {
+		if !journal.Enabled() {
+			return errors.New("journald is not available")
+		}
+		if err := journal.Send(string(body), journal.Priority(severity), c.journalFields(entry, facility)); err != nil {
+			return errors.Wrap(err, "cannot send journald entry")
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) write(message []byte) error {
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(*c.cfg.Timeout)); err != nil {
+		return err
+	}
+
+	switch c.cfg.Network {
+	case model.SyslogNetworkTCP, model.SyslogNetworkTLS, model.SyslogNetworkUnix:
+		if c.cfg.Framing == model.SyslogFramingNonTransparent {
+			message = append(message, '\n')
+		} else {
+			message = append([]byte(strconv.Itoa(len(message))+" "), message...)
+		}
+	}
+
+	_, err = conn.Write(message)
+	return err
+}
+
+func (c *Client) dial() (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout: *c.cfg.Timeout,
+	}
+	if c.cfg.Network != model.SyslogNetworkTLS {
+		return dialer.Dial(c.cfg.Network, c.cfg.Address)
+	}
+
+	tlsConfig, err := httputil.LoadTLSConfig(c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load TLS configuration")
+	}
+	if len(c.cfg.TLSClientCert) > 0 {
+		cert, err := tls.LoadX509KeyPair(c.cfg.TLSClientCert, c.cfg.TLSClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot load TLS client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tls.DialWithDialer(dialer, "tcp", c.cfg.Address, tlsConfig)
+}
+
+// format returns the entry as an RFC 5424 message
+func (c *Client) format(entry model.NotifEntry, body string, facility, severity int, ts time.Time) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<%d>1 %s %s %s %d %s ",
+		facility*8+severity,
+		ts.Format("2006-01-02T15:04:05.000000Z07:00"),
+		header(c.meta.Hostname, 255),
+		header(c.cfg.Tag, 48),
+		os.Getpid(),
+		header(string(entry.Status), 32),
+	)
+
+	sb.WriteString("[" + sdID)
+	for _, param := range [][2]string{
+		{"image", entry.Image.String()},
+		{"status", string(entry.Status)},
+		{"provider", entry.Provider},
+		{"digest", entry.Manifest.Digest.String()},
+		{"platform", entry.Manifest.Platform},
+	} {
+		if len(param[1]) > 0 {
+			fmt.Fprintf(&sb, ` %s="%s"`, param[0], sdEscape(param[1]))
+		}
+	}
+	sb.WriteString("]")
+
+	if len(entry.Metadata) > 0 {
+		sb.WriteString("[" + sdMetadataID)
+		for _, key := range slices.Sorted(maps.Keys(entry.Metadata)) {
+			fmt.Fprintf(&sb, ` %s="%s"`, sdName(key), sdEscape(entry.Metadata[key]))
+		}
+		sb.WriteString("]")
+	}
+
+	if len(body) > 0 {
+		sb.WriteString(" " + body)
+	}
+
+	return []byte(sb.String())
+}
+
+func (c *Client) journalFields(entry model.NotifEntry, facility int) map[string]string {
+	fields := map[string]string{
+		"SYSLOG_IDENTIFIER": c.cfg.Tag,
+		"SYSLOG_FACILITY":   strconv.Itoa(facility),
+		"DIUN_HOSTNAME":     c.meta.Hostname,
+		"DIUN_IMAGE":        entry.Image.String(),
+		"DIUN_STATUS":       string(entry.Status),
+		"DIUN_PROVIDER":     entry.Provider,
+		"DIUN_DIGEST":       entry.Manifest.Digest.String(),
+		"DIUN_PLATFORM":     entry.Manifest.Platform,
+	}
+	for key, value := range entry.Metadata {
+		fields[journalName("DIUN_METADATA_"+key)] = value
+	}
+	maps.DeleteFunc(fields, func(_, value string) bool {
+		return len(value) == 0
+	})
+	return fields
+}
+
+func (c *Client) severity(status model.ImageStatus) int {
+	if severity, ok := c.cfg.Severity[string(status)]; ok {
+		return severities[severity]
+	}
+	if severity, ok := (&model.NotifSyslog{}).GetDefaults().Severity[string(status)]; ok {
+		return severities[severity]
+	}
+	return severities["notice"]
+}
+
+// header returns a header field made of printable US-ASCII characters or
+// the NILVALUE if empty
+func header(s string, n int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) == 0 {
+		return "-"
+	}
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// sdName returns a structured data parameter name
+func sdName(s string) string {
+	return header(strings.Map(func(r rune) rune {
+		switch r {
+		case '=', ']', '"':
+			return '_'
+		}
+		return r
+	}, s), 32)
+}
+
+// sdEscape escapes a structured data parameter value
+func sdEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
+
+// journalName returns a journal field name made of uppercase letters, digits
+// and underscores
+func journalName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		}
+		return '_'
+	}, s)
+	if len(s) > 64 {
+		return s[:64]
+	}
+	return s
+}
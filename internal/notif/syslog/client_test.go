@@ -0,0 +1,178 @@
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	c := Client{
+		cfg: (&model.NotifSyslog{}).GetDefaults(),
+		meta: model.Meta{
+			Hostname: "my server",
+		},
+	}
+
+	entry := testEntry(t)
+	entry.Metadata = map[string]string{
+		"ctn_name":   "diun",
+		"ctn_labels": `a="b"]`,
+	}
+
+	ts := time.Date(2026, 5, 24, 12, 34, 56, 789000, time.UTC)
+	message := string(c.format(entry, "Docker tag updated", facilities["local3"], severities["notice"], ts))
+
+	assert.Regexp(t, regexp.MustCompile(`^<157>1 2026-05-24T12:34:56\.000789Z my_server diun \d+ update `), message)
+	assert.Contains(t, message, `[diun@32473 image="docker.io/crazymax/diun:latest" status="update" provider="docker" digest="sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01"]`)
+	assert.Contains(t, message, `[meta@32473 ctn_labels="a=\"b\"\]" ctn_name="diun"]`)
+	assert.True(t, strings.HasSuffix(message, "] Docker tag updated"))
+}
+
+func TestSendUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	cfg := (&model.NotifSyslog{}).GetDefaults()
+	cfg.Address = pc.LocalAddr().String()
+	cfg.Severity = map[string]string{
+		string(model.ImageStatusUpdate): "warning",
+	}
+	c := Client{cfg: cfg, meta: model.Meta{Hostname: "myserver"}}
+	require.NoError(t, c.Send(testEntry(t)))
+
+	buf := make([]byte, 2048)
+	require.NoError(t, pc.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(buf[:n]), "<28>1 "))
+	assert.True(t, strings.HasSuffix(string(buf[:n]), "Docker tag docker.io/crazymax/diun:latest has been updated (sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01)"))
+}
+
+func TestSendTCP(t *testing.T) {
+	for _, framing := range []string{model.SyslogFramingOctetCounting, model.SyslogFramingNonTransparent} {
+		t.Run(framing, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer ln.Close()
+
+			received := make(chan string, 1)
+			go func() {
+				conn, err := ln.Accept()
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				if framing == model.SyslogFramingNonTransparent {
+					line, err := r.ReadString('\n')
+					assert.NoError(t, err)
+					received <- strings.TrimSuffix(line, "\n")
+					return
+				}
+				length, err := r.ReadString(' ')
+				if !assert.NoError(t, err) {
+					return
+				}
+				n, err := strconv.Atoi(strings.TrimSpace(length))
+				if !assert.NoError(t, err) {
+					return
+				}
+				buf := make([]byte, n)
+				_, err = io.ReadFull(r, buf)
+				assert.NoError(t, err)
+				received <- string(buf)
+			}()
+
+			cfg := (&model.NotifSyslog{}).GetDefaults()
+			cfg.Network = model.SyslogNetworkTCP
+			cfg.Address = ln.Addr().String()
+			cfg.Framing = framing
+			cfg.TemplateBody = "{{ .Entry.Image }}"
+			c := Client{cfg: cfg, meta: model.Meta{Hostname: "myserver"}}
+			require.NoError(t, c.Send(testEntry(t)))
+
+			select {
+			case message := <-received:
+				assert.True(t, strings.HasPrefix(message, "<29>1 "))
+				assert.True(t, strings.HasSuffix(message, "] docker.io/crazymax/diun:latest"))
+			case <-time.After(2 * time.Second):
+				t.Fatal("timeout waiting for syslog message")
+			}
+		})
+	}
+}
+
+func TestSendUnixgram(t *testing.T) {
+	addr := filepath.Join(t.TempDir(), "log.sock")
+	pc, err := net.ListenPacket("unixgram", addr)
+	require.NoError(t, err)
+	defer pc.Close()
+
+	cfg := (&model.NotifSyslog{}).GetDefaults()
+	cfg.Network = model.SyslogNetworkUnixgram
+	cfg.Address = addr
+	c := Client{cfg: cfg, meta: model.Meta{Hostname: "myserver"}}
+	require.NoError(t, c.Send(testEntry(t)))
+
+	buf := make([]byte, 2048)
+	require.NoError(t, pc.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(buf[:n]), "<29>1 "))
+}
+
+func TestJournalFields(t *testing.T) {
+	c := Client{
+		cfg:  (&model.NotifSyslog{}).GetDefaults(),
+		meta: model.Meta{Hostname: "myserver"},
+	}
+
+	entry := testEntry(t)
+	entry.Metadata = map[string]string{
+		"ctn_name":          "diun",
+		"com.docker.stack":  "monitoring",
+		"io.kubernetes/pod": "diun-0",
+	}
+
+	assert.Equal(t, map[string]string{
+		"SYSLOG_IDENTIFIER":               "diun",
+		"SYSLOG_FACILITY":                 "3",
+		"DIUN_HOSTNAME":                   "myserver",
+		"DIUN_IMAGE":                      "docker.io/crazymax/diun:latest",
+		"DIUN_STATUS":                     "update",
+		"DIUN_PROVIDER":                   "docker",
+		"DIUN_DIGEST":                     "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+		"DIUN_METADATA_CTN_NAME":          "diun",
+		"DIUN_METADATA_COM_DOCKER_STACK":  "monitoring",
+		"DIUN_METADATA_IO_KUBERNETES_POD": "diun-0",
+	}, c.journalFields(entry, facilities["daemon"]))
+}
+
+func testEntry(t *testing.T) model.NotifEntry {
+	t.Helper()
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+	return model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+		Manifest: registry.Manifest{
+			Name:   "docker.io/crazymax/diun",
+			Tag:    "latest",
+			Digest: "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01",
+		},
+	}
+}
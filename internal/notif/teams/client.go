@@ -188,13 +188,21 @@ func (c *Client) facts(entry model.NotifEntry) []Fact {
 		return nil
 	}
 
-	return []Fact{
+	facts := []Fact{
 		{"Hostname", c.meta.Hostname},
 		{"Provider", entry.Provider},
-		{"Created", entry.Manifest.Created.Format("Jan 02, 2006 15:04:05 UTC")},
-		{"Digest", entry.Manifest.Digest.String()},
-		{"Platform", entry.Manifest.Platform},
 	}
+	if entry.Manifest.Created != nil {
+		facts = append(facts, Fact{"Created", entry.Manifest.Created.Format("Jan 02, 2006 15:04:05 UTC")})
+	}
+	facts = append(facts,
+		Fact{"Digest", entry.Manifest.Digest.String()},
+		Fact{"Platform", entry.Manifest.Platform},
+	)
+	if len(entry.Manifest.ArtifactType) > 0 {
+		facts = append(facts, Fact{"Artifact type", entry.Manifest.ArtifactType})
+	}
+	return facts
 }
 
 func (c *Client) messageCardPayload(entry model.NotifEntry, body string, facts []Fact) messageCardPayload {
@@ -3,14 +3,26 @@ package webhook
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/crazy-max/diun/v4/internal/httputil"
 	"github.com/crazy-max/diun/v4/internal/model"
 	"github.com/crazy-max/diun/v4/internal/msg"
 	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
 	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 // Client represents an active webhook notification object
@@ -18,6 +30,10 @@ type Client struct {
 	*notifier.Notifier
 	cfg  *model.NotifWebhook
 	meta model.Meta
+
+	// tokenSource caches the OAuth2 access token until it expires
+	mu          sync.Mutex
+	tokenSource oauth2.TokenSource
 }
 
 // New creates a new webhook notification instance
@@ -45,7 +61,12 @@ func (c *Client) Send(entry model.NotifEntry) error {
 		return err
 	}
 
-	body, err := message.RenderJSON()
+	endpoint, err := message.RenderTemplate("endpoint", c.cfg.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	body, headers, err := c.render(message)
 	if err != nil {
 		return err
 	}
@@ -59,26 +80,65 @@ func (c *Client) Send(entry model.NotifEntry) error {
 		return errors.Wrap(err, "cannot create HTTP client for Webhook notifier")
 	}
 
-	req, err := http.NewRequestWithContext(timeoutCtx, c.cfg.Method, c.cfg.Endpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(timeoutCtx, c.cfg.Method, string(endpoint), bytes.NewBuffer(body))
 	if err != nil {
 		return err
 	}
 
-	if len(c.cfg.Headers) > 0 {
-		for key, value := range c.cfg.Headers {
-			req.Header.Add(key, value)
+	for key, value := range c.cfg.Headers {
+		req.Header.Add(key, value)
+	}
+
+	for key, value := range c.cfg.HeaderTemplates {
+		headerValue, err := message.RenderTemplate("header "+key, value)
+		if err != nil {
+			return err
 		}
+		req.Header.Add(key, string(headerValue))
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	if c.cfg.ContentType != "" {
+		req.Header.Set("Content-Type", c.cfg.ContentType)
 	}
 
 	req.Header.Set("User-Agent", c.meta.UserAgent)
 
+	if c.cfg.Signature != nil {
+		if err := c.sign(req, body, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if c.cfg.OAuth2 != nil {
+		ts, err := c.oauth2TokenSource(hc)
+		if err != nil {
+			return err
+		}
+		token, err := ts.Token()
+		if err != nil {
+			return errors.Wrap(err, "cannot retrieve OAuth2 token for Webhook notifier")
+		}
+		token.SetAuthHeader(req)
+	}
+
 	resp, err := hc.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+	if resp.StatusCode == http.StatusUnauthorized && c.cfg.OAuth2 != nil {
+		// the token may have been revoked before its expiry
+		c.mu.Lock()
+		c.tokenSource = nil
+		c.mu.Unlock()
+	}
+
+	if !c.expectedStatus(resp.StatusCode) {
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return errors.Wrap(err, "cannot read Webhook error response")
@@ -88,3 +148,94 @@ func (c *Client) Send(entry model.NotifEntry) error {
 
 	return nil
 }
+
+// render returns the request body and the headers describing it. Without
+// CloudEvents, the body template is rendered if any or the JSON message is
+// sent as is.
+func (c *Client) render(message *msg.Client) ([]byte, map[string]string, error) {
+	if c.cfg.BodyTemplate != "" {
+		body, err := message.RenderTemplate("body", c.cfg.BodyTemplate)
+		return body, nil, err
+	}
+	if c.cfg.CloudEvents == "" {
+		body, err := message.RenderJSON()
+		return body, nil, err
+	}
+
+	event, err := message.RenderCloudEvent()
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.cfg.CloudEvents == model.NotifCloudEventsStructured {
+		body, err := json.Marshal(event)
+		return body, map[string]string{"Content-Type": msg.CloudEventsContentType}, err
+	}
+
+	headers := map[string]string{"Content-Type": event.DataContentType}
+	for key, value := range event.Attributes() {
+		headers["ce-"+key] = value
+	}
+	return event.Data, headers, nil
+}
+
+// expectedStatus checks the response status against the expected ones, any
+// 2xx status by default
+func (c *Client) expectedStatus(code int) bool {
+	if len(c.cfg.ExpectedStatus) > 0 {
+		return slices.Contains(c.cfg.ExpectedStatus, code)
+	}
+	return code >= http.StatusOK && code < http.StatusMultipleChoices
+}
+
+// sign sets the timestamp header and the HMAC-SHA256 signature of the
+// timestamp and body joined by a dot, so receivers can reject replayed
+// requests
+func (c *Client) sign(req *http.Request, body []byte, now time.Time) error {
+	key, err := secret.GetSecret(c.cfg.Signature.Secret, c.cfg.Signature.SecretFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve signature secret for Webhook notifier")
+	} else if key == "" {
+		return errors.New("signature secret for Webhook notifier is empty")
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	req.Header.Set(c.cfg.Signature.TimestampHeader, timestamp)
+	req.Header.Set(c.cfg.Signature.Header, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+// oauth2TokenSource returns the token source of the client credentials flow.
+// Tokens are requested with the same proxy and TLS settings as the webhook.
+func (c *Client) oauth2TokenSource(hc http.Client) (oauth2.TokenSource, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tokenSource != nil {
+		return c.tokenSource, nil
+	}
+
+	clientSecret, err := secret.GetSecret(c.cfg.OAuth2.ClientSecret, c.cfg.OAuth2.ClientSecretFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot retrieve OAuth2 client secret for Webhook notifier")
+	}
+
+	params := url.Values{}
+	for key, value := range c.cfg.OAuth2.EndpointParams {
+		params.Set(key, value)
+	}
+
+	hc.Timeout = *c.cfg.Timeout
+	cfg := clientcredentials.Config{
+		ClientID:       c.cfg.OAuth2.ClientID,
+		ClientSecret:   clientSecret,
+		TokenURL:       c.cfg.OAuth2.TokenURL,
+		Scopes:         c.cfg.OAuth2.Scopes,
+		EndpointParams: params,
+	}
+	c.tokenSource = cfg.TokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, &hc))
+	return c.tokenSource, nil
+}
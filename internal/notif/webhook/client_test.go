@@ -1,10 +1,17 @@
 package webhook
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -85,3 +92,281 @@ func TestSendReturnsErrorOnUnexpectedStatus(t *testing.T) {
 
 	require.ErrorContains(t, err, "unexpected HTTP status 500: failed")
 }
+
+func TestSendCloudEvents(t *testing.T) {
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/library/alpine:latest"})
+	require.NoError(t, err)
+
+	t.Run("structured", func(t *testing.T) {
+		var gotHeader http.Header
+		var gotBody []byte
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotHeader = r.Header.Clone()
+			gotBody, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			cfg: &model.NotifWebhook{
+				Endpoint:    ts.URL,
+				Method:      http.MethodPost,
+				CloudEvents: model.NotifCloudEventsStructured,
+				Timeout:     new(2 * time.Second),
+			},
+			meta: model.Meta{Hostname: "myserver", UserAgent: "diun-test"},
+		}
+		require.NoError(t, c.Send(model.NotifEntry{
+			Status:   model.ImageStatusNew,
+			Provider: "docker",
+			Image:    image,
+		}))
+
+		require.Equal(t, "application/cloudevents+json; charset=UTF-8", gotHeader.Get("Content-Type"))
+		require.Empty(t, gotHeader.Get("ce-type"))
+		var event map[string]any
+		require.NoError(t, json.Unmarshal(gotBody, &event))
+		require.Equal(t, "1.0", event["specversion"])
+		require.Equal(t, "io.diun.image.added", event["type"])
+		require.Equal(t, "myserver", event["source"])
+		require.Equal(t, "docker.io/library/alpine:latest", event["subject"])
+		require.Equal(t, "new", event["data"].(map[string]any)["status"])
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		var gotHeader http.Header
+		var gotBody []byte
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotHeader = r.Header.Clone()
+			gotBody, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer ts.Close()
+
+		c := Client{
+			cfg: &model.NotifWebhook{
+				Endpoint:    ts.URL,
+				Method:      http.MethodPost,
+				CloudEvents: model.NotifCloudEventsBinary,
+				Timeout:     new(2 * time.Second),
+			},
+			meta: model.Meta{Hostname: "myserver", UserAgent: "diun-test"},
+		}
+		require.NoError(t, c.Send(model.NotifEntry{
+			Status:   model.ImageStatusUpdate,
+			Provider: "docker",
+			Image:    image,
+		}))
+
+		require.Equal(t, "application/json", gotHeader.Get("Content-Type"))
+		require.Equal(t, "1.0", gotHeader.Get("ce-specversion"))
+		require.Equal(t, "io.diun.image.updated", gotHeader.Get("ce-type"))
+		require.Equal(t, "myserver", gotHeader.Get("ce-source"))
+		require.Equal(t, "docker.io/library/alpine:latest", gotHeader.Get("ce-subject"))
+		require.NotEmpty(t, gotHeader.Get("ce-id"))
+		require.NotEmpty(t, gotHeader.Get("ce-time"))
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(gotBody, &payload))
+		require.Equal(t, "update", payload["status"])
+	})
+}
+
+func TestSendBodyTemplate(t *testing.T) {
+	var gotBody []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/library/alpine:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifWebhook{
+			Endpoint:     ts.URL,
+			Method:       http.MethodPost,
+			BodyTemplate: `{{ .Entry.Status }} {{ .Entry.Image }} on {{ .Meta.Hostname }}`,
+			Timeout:      new(2 * time.Second),
+		},
+		meta: model.Meta{Hostname: "myserver", UserAgent: "diun-test"},
+	}
+	require.NoError(t, c.Send(model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+	}))
+	require.Equal(t, "update docker.io/library/alpine:latest on myserver", string(gotBody))
+}
+
+func TestSendSignature(t *testing.T) {
+	var gotHeader http.Header
+	var gotBody []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("s3cr3t"), 0o600))
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/library/alpine:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifWebhook{
+			Endpoint: ts.URL,
+			Method:   http.MethodPost,
+			Signature: &model.NotifWebhookSignature{
+				SecretFile:      secretFile,
+				Header:          "X-Hub-Signature-256",
+				TimestampHeader: "X-Diun-Timestamp",
+			},
+			Timeout: new(2 * time.Second),
+		},
+		meta: model.Meta{UserAgent: "diun-test"},
+	}
+	before := time.Now().Unix()
+	require.NoError(t, c.Send(model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+	}))
+
+	timestamp, err := strconv.ParseInt(gotHeader.Get("X-Diun-Timestamp"), 10, 64)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, timestamp, before)
+
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	_, _ = fmt.Fprintf(mac, "%d.%s", timestamp, gotBody)
+	require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), gotHeader.Get("X-Hub-Signature-256"))
+
+	c.cfg.Signature.SecretFile = ""
+	require.EqualError(t, c.Send(model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+	}), "signature secret for Webhook notifier is empty")
+}
+
+func TestSendOAuth2(t *testing.T) {
+	var tokenCalls int
+	var authorizations []string
+	unauthorized := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		require.NoError(t, r.ParseForm())
+		user, pass, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "diun", user)
+		require.Equal(t, "c1i3nt", pass)
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "webhooks:write", r.PostForm.Get("scope"))
+		require.Equal(t, "https://hooks.foo.com", r.PostForm.Get("audience"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"t0k3n-%d","token_type":"Bearer","expires_in":3600}`, tokenCalls)
+	})
+	mux.HandleFunc("POST /hook", func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		if unauthorized {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/library/alpine:latest"})
+	require.NoError(t, err)
+	entry := model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+	}
+
+	c := Client{
+		cfg: &model.NotifWebhook{
+			Endpoint: ts.URL + "/hook",
+			Method:   http.MethodPost,
+			OAuth2: &model.NotifWebhookOAuth2{
+				TokenURL:       ts.URL + "/token",
+				ClientID:       "diun",
+				ClientSecret:   "c1i3nt",
+				Scopes:         []string{"webhooks:write"},
+				EndpointParams: map[string]string{"audience": "https://hooks.foo.com"},
+			},
+			Timeout: new(2 * time.Second),
+		},
+		meta: model.Meta{UserAgent: "diun-test"},
+	}
+
+	require.NoError(t, c.Send(entry))
+	require.NoError(t, c.Send(entry))
+	require.Equal(t, 1, tokenCalls)
+
+	unauthorized = true
+	require.ErrorContains(t, c.Send(entry), "unexpected HTTP status 401")
+	unauthorized = false
+	require.NoError(t, c.Send(entry))
+	require.Equal(t, 2, tokenCalls)
+	require.Equal(t, []string{"Bearer t0k3n-1", "Bearer t0k3n-1", "Bearer t0k3n-1", "Bearer t0k3n-2"}, authorizations)
+}
+
+func TestSendRequestTemplate(t *testing.T) {
+	var gotPath, gotQuery string
+	var gotHeader http.Header
+	var gotBody []byte
+	status := http.StatusCreated
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.Query().Get("image")
+		gotHeader = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/library/alpine:latest"})
+	require.NoError(t, err)
+	entry := model.NotifEntry{
+		Status:   model.ImageStatusUpdate,
+		Provider: "docker",
+		Image:    image,
+		Metadata: map[string]string{
+			"ctn_names": `web "front"`,
+		},
+	}
+
+	c := Client{
+		cfg: &model.NotifWebhook{
+			Endpoint: ts.URL + "/hooks/{{ .Entry.Provider }}?image={{ urlquery .Entry.Image.String }}",
+			Method:   http.MethodPost,
+			Headers: map[string]string{
+				"X-Literal": "{{ not a template",
+			},
+			HeaderTemplates: map[string]string{
+				"X-Image-Tag": "{{ .Entry.Image.Tag }}",
+			},
+			BodyTemplate:   `{"content": {{ toJson (printf "%s updated" .Entry.Image) }}, "container": {{ toJson .Entry.Metadata.ctn_names }}}`,
+			ContentType:    "application/json; charset=utf-8",
+			ExpectedStatus: []int{http.StatusCreated},
+			Timeout:        new(2 * time.Second),
+		},
+		meta: model.Meta{UserAgent: "diun-test"},
+	}
+
+	require.NoError(t, c.Send(entry))
+	require.Equal(t, "/hooks/docker", gotPath)
+	require.Equal(t, "docker.io/library/alpine:latest", gotQuery)
+	require.Equal(t, "latest", gotHeader.Get("X-Image-Tag"))
+	require.Equal(t, "{{ not a template", gotHeader.Get("X-Literal"))
+	require.Equal(t, "application/json; charset=utf-8", gotHeader.Get("Content-Type"))
+	require.JSONEq(t, `{"content": "docker.io/library/alpine:latest updated", "container": "web \"front\""}`, string(gotBody))
+
+	status = http.StatusOK
+	require.EqualError(t, c.Send(entry), "unexpected HTTP status 200: ")
+}
@@ -0,0 +1,130 @@
+package zulip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/msg"
+	"github.com/crazy-max/diun/v4/internal/notif/notifier"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+)
+
+// maxTopicLength is the maximum length of a topic name allowed by Zulip
+const maxTopicLength = 60
+
+// Client represents an active zulip notification object
+type Client struct {
+	*notifier.Notifier
+	cfg  *model.NotifZulip
+	meta model.Meta
+}
+
+// New creates a new zulip notification instance
+func New(config *model.NotifZulip, meta model.Meta) notifier.Notifier {
+	return notifier.Notifier{
+		Handler: &Client{
+			cfg:  config,
+			meta: meta,
+		},
+	}
+}
+
+// Name returns notifier's name
+func (c *Client) Name() string {
+	return "zulip"
+}
+
+// Send creates and sends a zulip notification with an entry
+// https://zulip.com/api/send-message
+func (c *Client) Send(entry model.NotifEntry) error {
+	apiKey, err := secret.GetSecret(c.cfg.APIKey, c.cfg.APIKeyFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot retrieve API key secret for Zulip notifier")
+	}
+
+	message, err := msg.New(msg.Options{
+		Meta:          c.meta,
+		Entry:         entry,
+		TemplateTitle: c.cfg.TemplateTitle,
+		TemplateBody:  c.cfg.TemplateBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	title, body, err := message.RenderMarkdown()
+	if err != nil {
+		return err
+	}
+
+	topic, err := message.RenderTemplate("topic", c.cfg.Topic)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	form.Add("type", "stream")
+	form.Add("to", c.cfg.Stream)
+	form.Add("topic", truncate(string(topic), maxTopicLength))
+	form.Add("content", fmt.Sprintf("**%s**\n%s", title, body))
+
+	u, err := url.Parse(c.cfg.Endpoint)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "api/v1/messages")
+
+	cancelCtx, cancelCause := context.WithCancelCause(context.Background())
+	defer func() { cancelCause(errors.WithStack(context.Canceled)) }()
+	timeoutCtx, cancel := context.WithTimeoutCause(cancelCtx, *c.cfg.Timeout, errors.WithStack(context.DeadlineExceeded))
+	defer cancel()
+
+	hc, err := httputil.NewClient(c.cfg.Proxy, c.cfg.TLSSkipVerify, c.cfg.TLSCACertFiles)
+	if err != nil {
+		return errors.Wrap(err, "cannot create HTTP client for Zulip notifier")
+	}
+
+	req, err := http.NewRequestWithContext(timeoutCtx, "POST", u.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(c.cfg.Email, apiKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", c.meta.UserAgent)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var respBody struct {
+		Result string `json:"result"`
+		Msg    string `json:"msg"`
+		Code   string `json:"code,omitempty"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return errors.Wrapf(err, "cannot decode JSON body response for HTTP %d %s status", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if resp.StatusCode != http.StatusOK || respBody.Result != "success" {
+		return errors.Errorf("unexpected HTTP status %d: %s", resp.StatusCode, respBody.Msg)
+	}
+
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package zulip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	var form url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/messages", r.URL.Path)
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "diun-bot@zulip.example.com", username)
+		assert.Equal(t, "4p1k3y", password)
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		form = r.PostForm
+		_, _ = w.Write([]byte(`{"id":42,"msg":"","result":"success"}`))
+	}))
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifZulip{
+			Endpoint:      ts.URL,
+			Email:         "diun-bot@zulip.example.com",
+			APIKey:        "4p1k3y",
+			Stream:        "ops",
+			Topic:         model.NotifZulipDefaultTemplateTopic,
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: model.NotifDefaultTemplateTitle,
+			TemplateBody:  "Provider {{ .Entry.Provider }}",
+		},
+	}
+
+	require.NoError(t, c.Send(model.NotifEntry{
+		Status:   model.ImageStatusNew,
+		Provider: "docker",
+		Image:    image,
+	}))
+
+	assert.Equal(t, "stream", form.Get("type"))
+	assert.Equal(t, "ops", form.Get("to"))
+	assert.Equal(t, "docker.io/crazymax/diun", form.Get("topic"))
+	assert.Equal(t, "**docker.io/crazymax/diun:latest is available**\nProvider docker", form.Get("content"))
+}
+
+func TestSendError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":"STREAM_DOES_NOT_EXIST","msg":"Channel 'nope' does not exist","result":"error","stream":"nope"}`))
+	}))
+	defer ts.Close()
+
+	image, err := registry.ParseImage(registry.ParseImageOptions{Name: "docker.io/crazymax/diun:latest"})
+	require.NoError(t, err)
+
+	c := Client{
+		cfg: &model.NotifZulip{
+			Endpoint:      ts.URL,
+			Email:         "diun-bot@zulip.example.com",
+			APIKey:        "4p1k3y",
+			Stream:        "nope",
+			Topic:         model.NotifZulipDefaultTemplateTopic,
+			Timeout:       new(2 * time.Second),
+			TemplateTitle: model.NotifDefaultTemplateTitle,
+			TemplateBody:  model.NotifDefaultTemplateBody,
+		},
+	}
+
+	err = c.Send(model.NotifEntry{
+		Status: model.ImageStatusNew,
+		Image:  image,
+	})
+	require.Error(t, err)
+	assert.Equal(t, "unexpected HTTP status 400: Channel 'nope' does not exist", err.Error())
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "diun", truncate("diun", 60))
+	assert.Equal(t, "dockerh…", truncate("dockerhub", 8))
+}
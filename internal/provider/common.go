@@ -4,11 +4,13 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"dario.cat/mergo"
 	"github.com/containerd/platforms"
 	"github.com/crazy-max/diun/v4/internal/model"
 	"github.com/crazy-max/diun/v4/pkg/registry"
+	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 )
 
@@ -28,8 +30,12 @@ func ValidateImage(image string, metadata, labels map[string]string, watchByDef
 		img.NotifyOn = defaults.NotifyOn
 		img.MaxTags = defaults.MaxTags
 		img.SortTags = defaults.SortTags
+		img.SortRegex = defaults.SortRegex
+		img.MatchVariant = defaults.MatchVariant
 		img.IncludeTags = defaults.IncludeTags
 		img.ExcludeTags = defaults.ExcludeTags
+		img.MinAge = defaults.MinAge
+		img.MaxAge = defaults.MaxAge
 		img.Metadata = defaults.Metadata
 	}
 
@@ -76,6 +82,17 @@ func ValidateImage(image string, metadata, labels map[string]string, watchByDef
 				return img, errors.Errorf("unknown sort tags type %q", value)
 			}
 			img.SortTags = sortTags
+		case key == "diun.sort_regex":
+			if _, err := regexp.Compile(value); err != nil {
+				return img, errors.Wrapf(err, "cannot compile %q regex of label %s", value, key)
+			}
+			img.SortRegex = value
+		case key == "diun.match_variant":
+			if matchVariant, err := strconv.ParseBool(value); err == nil {
+				img.MatchVariant = new(matchVariant)
+			} else {
+				return img, errors.Wrapf(err, "cannot parse %q value of label %s", value, key)
+			}
 		case key == "diun.max_tags":
 			if img.MaxTags, err = strconv.Atoi(value); err != nil {
 				return img, errors.Wrapf(err, "cannot parse %q value of label %s", value, key)
@@ -88,6 +105,34 @@ func ValidateImage(image string, metadata, labels map[string]string, watchByDef
 			img.HubTpl = value
 		case key == "diun.hub_link":
 			img.HubLink = value
+		case key == "diun.cosign_key":
+			img.CosignKey = value
+		case key == "diun.require_signed":
+			if requireSigned, err := strconv.ParseBool(value); err == nil {
+				img.RequireSigned = new(requireSigned)
+			} else {
+				return img, errors.Wrapf(err, "cannot parse %q value of label %s", value, key)
+			}
+		case key == "diun.artifact":
+			if artifact, err := strconv.ParseBool(value); err == nil {
+				img.Artifact = new(artifact)
+			} else {
+				return img, errors.Wrapf(err, "cannot parse %q value of label %s", value, key)
+			}
+		case key == "diun.artifact_types":
+			img.ArtifactTypes = strings.Split(value, ";")
+		case key == "diun.min_age":
+			if minAge, err := time.ParseDuration(value); err == nil {
+				img.MinAge = new(minAge)
+			} else {
+				return img, errors.Wrapf(err, "cannot parse %q value of label %s", value, key)
+			}
+		case key == "diun.max_age":
+			if maxAge, err := time.ParseDuration(value); err == nil {
+				img.MaxAge = new(maxAge)
+			} else {
+				return img, errors.Wrapf(err, "cannot parse %q value of label %s", value, key)
+			}
 		case key == "diun.platform":
 			platform, err := platforms.Parse(value)
 			if err != nil {
@@ -121,6 +166,26 @@ func ValidateImage(image string, metadata, labels map[string]string, watchByDef
 	return img, nil
 }
 
+// RunningDigest returns the digest of the first reference pointing to the
+// repository of an image, such as the repo digests of the image run by a
+// container. It returns an empty digest if none matches.
+func RunningDigest(image string, refs ...string) digest.Digest {
+	img, err := registry.ParseImage(registry.ParseImageOptions{Name: image})
+	if err != nil {
+		return ""
+	}
+	for _, ref := range refs {
+		refImg, err := registry.ParseImage(registry.ParseImageOptions{Name: ref})
+		if err != nil || refImg.Digest == "" {
+			continue
+		}
+		if refImg.Domain == img.Domain && refImg.Path == img.Path {
+			return refImg.Digest
+		}
+	}
+	return ""
+}
+
 func validateMetadataKey(key string) error {
 	if !metadataKeyRegexp.MatchString(key) {
 		return errors.Errorf("only %q are allowed", metadataKeyChars)
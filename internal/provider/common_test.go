@@ -2,6 +2,7 @@ package provider
 
 import (
 	"testing"
+	"time"
 
 	"github.com/crazy-max/diun/v4/internal/model"
 	"github.com/crazy-max/diun/v4/pkg/registry"
@@ -529,6 +530,116 @@ func TestValidateImage(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name:  "Set cosign_key and require_signed",
+			image: "myimg",
+			labels: map[string]string{
+				"diun.cosign_key":     "/etc/diun/cosign.pub",
+				"diun.require_signed": "true",
+			},
+			watchByDef: true,
+			expectedImage: model.Image{
+				Name:          "myimg",
+				CosignKey:     "/etc/diun/cosign.pub",
+				RequireSigned: new(true),
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Set invalid require_signed",
+			image: "myimg",
+			labels: map[string]string{
+				"diun.require_signed": "chickens",
+			},
+			watchByDef: true,
+			expectedImage: model.Image{
+				Name: "myimg",
+			},
+			expectedErr: errors.New(`cannot parse "chickens" value of label diun.require_signed`),
+		},
+		{
+			name:  "Set artifact and artifact_types",
+			image: "myimg",
+			labels: map[string]string{
+				"diun.artifact":       "true",
+				"diun.artifact_types": "application/vnd.cncf.helm.config.v1+json;application/vnd.cncf.flux.config.v1+json",
+			},
+			watchByDef: true,
+			expectedImage: model.Image{
+				Name:          "myimg",
+				Artifact:      new(true),
+				ArtifactTypes: []string{"application/vnd.cncf.helm.config.v1+json", "application/vnd.cncf.flux.config.v1+json"},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Set invalid artifact",
+			image: "myimg",
+			labels: map[string]string{
+				"diun.artifact": "chickens",
+			},
+			watchByDef: true,
+			expectedImage: model.Image{
+				Name: "myimg",
+			},
+			expectedErr: errors.New(`cannot parse "chickens" value of label diun.artifact`),
+		},
+		{
+			name:  "Set sort_regex and match_variant",
+			image: "myimg",
+			labels: map[string]string{
+				"diun.sort_tags":     "regex",
+				"diun.sort_regex":    `^v(?P<major>\d+)\.(?P<minor>\d+)-r(?P<build>\d+)$`,
+				"diun.match_variant": "true",
+			},
+			watchByDef: true,
+			expectedImage: model.Image{
+				Name:         "myimg",
+				SortTags:     registry.SortTagRegex,
+				SortRegex:    `^v(?P<major>\d+)\.(?P<minor>\d+)-r(?P<build>\d+)$`,
+				MatchVariant: new(true),
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Set invalid sort_regex",
+			image: "myimg",
+			labels: map[string]string{
+				"diun.sort_regex": "(?P<major",
+			},
+			watchByDef: true,
+			expectedImage: model.Image{
+				Name: "myimg",
+			},
+			expectedErr: errors.New(`cannot compile "(?P<major" regex of label diun.sort_regex`),
+		},
+		{
+			name:  "Set min_age and max_age",
+			image: "myimg",
+			labels: map[string]string{
+				"diun.min_age": "24h",
+				"diun.max_age": "720h",
+			},
+			watchByDef: true,
+			expectedImage: model.Image{
+				Name:   "myimg",
+				MinAge: new(24 * time.Hour),
+				MaxAge: new(720 * time.Hour),
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Set invalid min_age",
+			image: "myimg",
+			labels: map[string]string{
+				"diun.min_age": "chickens",
+			},
+			watchByDef: true,
+			expectedImage: model.Image{
+				Name: "myimg",
+			},
+			expectedErr: errors.New(`cannot parse "chickens" value of label diun.min_age`),
+		},
 		{
 			name:  "Set valid platform",
 			image: "myimg",
@@ -699,3 +810,41 @@ func TestValidateImage(t *testing.T) {
 		})
 	}
 }
+
+func TestRunningDigest(t *testing.T) {
+	const dgst = "sha256:216e3ae7de4ca8b553eb11ef7abda00651e79e537e85c46108284e5e91673e01"
+	cases := []struct {
+		name     string
+		image    string
+		refs     []string
+		expected string
+	}{
+		{
+			name:     "repo digest",
+			image:    "crazymax/diun:latest",
+			refs:     []string{"ghcr.io/crazy-max/diun@sha256:3fca3dd86c2710586208b0f92d1ec4ce25382f4cad4ae76a2275db8e8bb24031", "crazymax/diun@" + dgst},
+			expected: dgst,
+		},
+		{
+			name:     "pinned image",
+			image:    "docker.io/crazymax/diun:latest@" + dgst,
+			refs:     []string{"docker.io/crazymax/diun:latest@" + dgst},
+			expected: dgst,
+		},
+		{
+			name:  "other repository",
+			image: "crazymax/diun:latest",
+			refs:  []string{"ghcr.io/crazy-max/diun@" + dgst},
+		},
+		{
+			name:  "image ID",
+			image: "crazymax/diun:latest",
+			refs:  []string{dgst},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, RunningDigest(tt.image, tt.refs...).String())
+		})
+	}
+}
@@ -108,6 +108,7 @@ func (c *Client) listContainerImage() []model.Image {
 				Msg("Watch disabled")
 			continue
 		}
+		image.RunningDigest = provider.RunningDigest(imageName, imageInfo.RepoDigests...)
 
 		list = append(list, image)
 	}
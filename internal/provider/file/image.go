@@ -83,6 +83,14 @@ func (c *Client) listFileImage() []model.Image {
 				}
 			}
 
+			// Set default SortRegex and MatchVariant
+			if item.SortRegex == "" {
+				item.SortRegex = c.defaults.SortRegex
+			}
+			if item.MatchVariant == nil {
+				item.MatchVariant = c.defaults.MatchVariant
+			}
+
 			// Set default MaxTags
 			if item.MaxTags == 0 {
 				item.MaxTags = c.defaults.MaxTags
@@ -98,6 +106,14 @@ func (c *Client) listFileImage() []model.Image {
 				item.ExcludeTags = c.defaults.ExcludeTags
 			}
 
+			// Set default MinAge and MaxAge
+			if item.MinAge == nil {
+				item.MinAge = c.defaults.MinAge
+			}
+			if item.MaxAge == nil {
+				item.MaxAge = c.defaults.MaxAge
+			}
+
 			images = append(images, item)
 		}
 	}
@@ -59,6 +59,7 @@ func (c *Client) listPodImage() []model.Image {
 					Msg("Watch disabled")
 				continue
 			}
+			image.RunningDigest = provider.RunningDigest(ctn.Image, imageID(pod, ctn))
 
 			list = append(list, image)
 		}
@@ -67,6 +68,17 @@ func (c *Client) listPodImage() []model.Image {
 	return list
 }
 
+// imageID returns the image reference resolved by the container runtime for a
+// container of a pod. Docker based runtimes prefix it with docker-pullable://
+func imageID(pod v1.Pod, ctn v1.Container) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == ctn.Name {
+			return strings.TrimPrefix(status.ImageID, "docker-pullable://")
+		}
+	}
+	return ""
+}
+
 func metadata(pod v1.Pod, ctn v1.Container) map[string]string {
 	return map[string]string{
 		"pod_name":      pod.Name,
@@ -53,6 +53,7 @@ func (c *Client) listServiceImage() []model.Image {
 				Msg("Watch disabled")
 			continue
 		}
+		image.RunningDigest = provider.RunningDigest(svc.Spec.TaskTemplate.ContainerSpec.Image, svc.Spec.TaskTemplate.ContainerSpec.Image)
 
 		list = append(list, image)
 	}
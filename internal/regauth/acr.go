@@ -0,0 +1,130 @@
+package regauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/types"
+)
+
+const (
+	acrUsername      = "00000000-0000-0000-0000-000000000000"
+	acrScope         = "https://management.azure.com/.default"
+	acrAuthorityHost = "https://login.microsoftonline.com/"
+	acrRefreshTTL    = 3 * time.Hour
+)
+
+// acr retrieves credentials for Azure Container Registry by exchanging an
+// Entra ID token of a service principal or workload identity.
+type acr struct {
+	cfg       model.RegOptACR
+	hc        *http.Client
+	userAgent string
+}
+
+func (p *acr) name() string {
+	return "acr"
+}
+
+func (p *acr) fetch(ctx context.Context, domain string) (types.DockerAuthConfig, time.Time, error) {
+	tenantID := firstNonEmpty(p.cfg.TenantID, os.Getenv("AZURE_TENANT_ID"))
+	if tenantID == "" {
+		return types.DockerAuthConfig{}, time.Time{}, errors.New("tenant ID is required")
+	}
+
+	accessToken, err := p.accessToken(ctx, tenantID)
+	if err != nil {
+		return types.DockerAuthConfig{}, time.Time{}, err
+	}
+
+	var res struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := postForm(ctx, p.hc, fmt.Sprintf("https://%s/oauth2/exchange", domain), p.userAgent, url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {domain},
+		"tenant":       {tenantID},
+		"access_token": {accessToken},
+	}, &res); err != nil {
+		return types.DockerAuthConfig{}, time.Time{}, errors.Wrap(err, "cannot exchange access token")
+	}
+	if res.RefreshToken == "" {
+		return types.DockerAuthConfig{}, time.Time{}, errors.New("no refresh token returned")
+	}
+
+	return types.DockerAuthConfig{
+		Username: acrUsername,
+		Password: res.RefreshToken,
+	}, tokenExpiration(res.RefreshToken, acrRefreshTTL), nil
+}
+
+// accessToken retrieves an Entra ID access token using the client secret or
+// the federated token of the workload identity.
+func (p *acr) accessToken(ctx context.Context, tenantID string) (string, error) {
+	clientID := firstNonEmpty(p.cfg.ClientID, os.Getenv("AZURE_CLIENT_ID"))
+	if clientID == "" {
+		return "", errors.New("client ID is required")
+	}
+
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+		"client_id":  {clientID},
+		"scope":      {acrScope},
+	}
+	clientSecret, err := secret.GetSecret(p.cfg.ClientSecret, p.cfg.ClientSecretFile)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot retrieve client secret")
+	}
+	if clientSecret = firstNonEmpty(strings.TrimSpace(clientSecret), os.Getenv("AZURE_CLIENT_SECRET")); clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	} else if tokenFile := firstNonEmpty(p.cfg.FederatedTokenFile, os.Getenv("AZURE_FEDERATED_TOKEN_FILE")); tokenFile != "" {
+		assertion, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", errors.Wrap(err, "cannot read federated token")
+		}
+		form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+		form.Set("client_assertion", strings.TrimSpace(string(assertion)))
+	} else {
+		return "", errors.New("client secret or federated token is required")
+	}
+
+	authorityHost := firstNonEmpty(p.cfg.AuthorityHost, os.Getenv("AZURE_AUTHORITY_HOST"), acrAuthorityHost)
+	var res struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := postForm(ctx, p.hc, fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authorityHost, "/"), tenantID), p.userAgent, form, &res); err != nil {
+		return "", err
+	}
+	if res.AccessToken == "" {
+		return "", errors.New("no access token returned")
+	}
+
+	return res.AccessToken, nil
+}
+
+// tokenExpiration returns the expiration time of a JWT or falls back to the
+// given lifetime if it cannot be decoded.
+func tokenExpiration(jwt string, fallback time.Duration) time.Time {
+	parts := strings.Split(jwt, ".")
+	if len(parts) == 3 {
+		if payload, err := base64.RawURLEncoding.DecodeString(parts[1]); err == nil {
+			var claims struct {
+				Exp int64 `json:"exp"`
+			}
+			if json.Unmarshal(payload, &claims) == nil && claims.Exp > 0 {
+				return time.Unix(claims.Exp, 0)
+			}
+		}
+	}
+	return time.Now().Add(fallback)
+}
@@ -0,0 +1,93 @@
+package regauth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACRCredentials(t *testing.T) {
+	expiresAt := time.Now().Add(3 * time.Hour).Truncate(time.Second)
+	refreshToken := "header." + base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, `{"exp":%d}`, expiresAt.Unix())) + ".signature"
+
+	var gotClientSecret, gotAssertion string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		switch r.URL.Path {
+		case "/tenant/oauth2/v2.0/token":
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "client", r.PostForm.Get("client_id"))
+			assert.Equal(t, acrScope, r.PostForm.Get("scope"))
+			gotClientSecret = r.PostForm.Get("client_secret")
+			gotAssertion = r.PostForm.Get("client_assertion")
+			_, _ = w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"aad"}`))
+		case "/oauth2/exchange":
+			assert.Equal(t, "access_token", r.PostForm.Get("grant_type"))
+			assert.Equal(t, r.Host, r.PostForm.Get("service"))
+			assert.Equal(t, "tenant", r.PostForm.Get("tenant"))
+			assert.Equal(t, "aad", r.PostForm.Get("access_token"))
+			_, _ = fmt.Fprintf(w, `{"refresh_token":%q}`, refreshToken)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	t.Setenv("AZURE_CLIENT_SECRET", "")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	domain := strings.TrimPrefix(ts.URL, "https://")
+
+	t.Run("client secret", func(t *testing.T) {
+		prv, err := New(model.RegOpt{
+			Name:        "acr",
+			InsecureTLS: new(true),
+			ACR: &model.RegOptACR{
+				TenantID:      "tenant",
+				ClientID:      "client",
+				ClientSecret:  "secret",
+				AuthorityHost: ts.URL,
+			},
+		}, "diun/test")
+		require.NoError(t, err)
+
+		auth, err := prv.Credentials(domain)
+		require.NoError(t, err)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000000", auth.Username)
+		assert.Equal(t, refreshToken, auth.Password)
+		assert.Equal(t, "secret", gotClientSecret)
+		assert.Empty(t, gotAssertion)
+	})
+
+	t.Run("workload identity", func(t *testing.T) {
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("federated\n"), 0o600))
+		t.Setenv("AZURE_TENANT_ID", "tenant")
+		t.Setenv("AZURE_CLIENT_ID", "client")
+		t.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenFile)
+		t.Setenv("AZURE_AUTHORITY_HOST", ts.URL)
+
+		p := &acr{hc: ts.Client()}
+		auth, exp, err := p.fetch(t.Context(), domain)
+		require.NoError(t, err)
+		assert.Equal(t, refreshToken, auth.Password)
+		assert.Equal(t, "federated", gotAssertion)
+		assert.True(t, expiresAt.Equal(exp))
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		t.Setenv("AZURE_TENANT_ID", "")
+		p := &acr{cfg: model.RegOptACR{TenantID: "tenant", ClientID: "client"}, hc: ts.Client()}
+		_, _, err := p.fetch(t.Context(), domain)
+		require.EqualError(t, err, "client secret or federated token is required")
+	})
+}
@@ -0,0 +1,65 @@
+package regauth
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/docker/docker-credential-helpers/credentials"
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/pkg/docker/config"
+	"go.podman.io/image/v5/types"
+)
+
+// dockerHubServerURL is the server URL under which Docker stores Docker Hub
+// credentials
+const dockerHubServerURL = "https://index.docker.io/v1/"
+
+// FromAuthFile retrieves credentials for the registry domain from a Docker
+// config.json file, including the credential helpers it references through
+// credsStore and credHelpers.
+func FromAuthFile(filename, domain string) (types.DockerAuthConfig, error) {
+	auth, err := config.GetCredentials(&types.SystemContext{
+		DockerCompatAuthFilePath: filename,
+	}, domain)
+	return auth, errors.Wrapf(err, "cannot retrieve credentials from %s", filename)
+}
+
+// FromCredentialHelper retrieves credentials for the registry domain from a
+// Docker credential helper. The helper can be a name such as "pass", a binary
+// name such as "docker-credential-pass" or a path to the binary.
+func FromCredentialHelper(helper, domain string) (types.DockerAuthConfig, error) {
+	program := helperProgram(helper)
+
+	serverURL := domain
+	if domain == "docker.io" {
+		serverURL = dockerHubServerURL
+	}
+
+	creds, err := client.Get(client.NewShellProgramFunc(program), serverURL)
+	if err != nil {
+		if credentials.IsErrCredentialsNotFound(err) {
+			return types.DockerAuthConfig{}, nil
+		}
+		return types.DockerAuthConfig{}, errors.Wrapf(err, "cannot retrieve credentials from %s", program)
+	}
+
+	// Username "<token>" denotes an identity token
+	if creds.Username == "<token>" {
+		return types.DockerAuthConfig{
+			IdentityToken: creds.Secret,
+		}, nil
+	}
+
+	return types.DockerAuthConfig{
+		Username: creds.Username,
+		Password: creds.Secret,
+	}, nil
+}
+
+func helperProgram(helper string) string {
+	if strings.ContainsRune(helper, filepath.Separator) || strings.HasPrefix(helper, "docker-credential-") {
+		return helper
+	}
+	return "docker-credential-" + helper
+}
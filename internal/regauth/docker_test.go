@@ -0,0 +1,67 @@
+package regauth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/types"
+)
+
+func TestFromAuthFile(t *testing.T) {
+	authFile := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(authFile, fmt.Appendf(nil, `{"auths":{"registry.test":{"auth":%q}}}`,
+		base64.StdEncoding.EncodeToString([]byte("foo:bar"))), 0o600))
+
+	auth, err := FromAuthFile(authFile, "registry.test")
+	require.NoError(t, err)
+	assert.Equal(t, types.DockerAuthConfig{Username: "foo", Password: "bar"}, auth)
+
+	auth, err = FromAuthFile(authFile, "other.test")
+	require.NoError(t, err)
+	assert.Equal(t, types.DockerAuthConfig{}, auth)
+}
+
+func TestFromCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper stand-in requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	helper := filepath.Join(dir, "docker-credential-test")
+	require.NoError(t, os.WriteFile(helper, []byte(`#!/bin/sh
+read server
+case "$server" in
+  registry.test) echo '{"ServerURL":"registry.test","Username":"foo","Secret":"bar"}' ;;
+  https://index.docker.io/v1/) echo '{"ServerURL":"https://index.docker.io/v1/","Username":"<token>","Secret":"identity"}' ;;
+  *) echo "credentials not found in native keychain"; exit 1 ;;
+esac
+`), 0o700)) //nolint:gosec // test helper must be executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	auth, err := FromCredentialHelper("test", "registry.test")
+	require.NoError(t, err)
+	assert.Equal(t, types.DockerAuthConfig{Username: "foo", Password: "bar"}, auth)
+
+	auth, err = FromCredentialHelper(helper, "docker.io")
+	require.NoError(t, err)
+	assert.Equal(t, types.DockerAuthConfig{IdentityToken: "identity"}, auth)
+
+	auth, err = FromCredentialHelper("docker-credential-test", "other.test")
+	require.NoError(t, err)
+	assert.Equal(t, types.DockerAuthConfig{}, auth)
+
+	_, err = FromCredentialHelper("missing", "registry.test")
+	require.Error(t, err)
+}
+
+func TestHelperProgram(t *testing.T) {
+	assert.Equal(t, "docker-credential-pass", helperProgram("pass"))
+	assert.Equal(t, "docker-credential-pass", helperProgram("docker-credential-pass"))
+	assert.Equal(t, filepath.Join("usr", "bin", "helper"), helperProgram(filepath.Join("usr", "bin", "helper")))
+}
@@ -0,0 +1,380 @@
+package regauth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/crazy-max/diun/v4/internal/secret"
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/types"
+)
+
+// imdsTimeout bounds the instance metadata lookup, which hangs outside EC2
+const imdsTimeout = 5 * time.Second
+
+var ecrDomainRegexp = regexp.MustCompile(`^\d{12}\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// ecr retrieves credentials from AWS Elastic Container Registry
+type ecr struct {
+	cfg       model.RegOptECR
+	hc        *http.Client
+	userAgent string
+	now       func() time.Time
+}
+
+func (p *ecr) name() string {
+	return "ecr"
+}
+
+func (p *ecr) fetch(ctx context.Context, domain string) (types.DockerAuthConfig, time.Time, error) {
+	region := p.region(domain)
+	if region == "" {
+		return types.DockerAuthConfig{}, time.Time{}, errors.Errorf("cannot determine AWS region for %s", domain)
+	}
+
+	creds, err := p.credentials(ctx, region)
+	if err != nil {
+		return types.DockerAuthConfig{}, time.Time{}, err
+	}
+
+	endpoint := firstNonEmpty(p.cfg.Endpoint, fmt.Sprintf("https://api.ecr.%s.amazonaws.com", region))
+	payload := []byte("{}")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return types.DockerAuthConfig{}, time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken")
+	signV4(req, payload, creds, region, "ecr", p.now())
+
+	var res struct {
+		AuthorizationData []struct {
+			AuthorizationToken string  `json:"authorizationToken"`
+			ExpiresAt          float64 `json:"expiresAt"`
+		} `json:"authorizationData"`
+	}
+	if err := doJSON(p.hc, req, p.userAgent, &res); err != nil {
+		return types.DockerAuthConfig{}, time.Time{}, err
+	}
+	if len(res.AuthorizationData) == 0 {
+		return types.DockerAuthConfig{}, time.Time{}, errors.New("no authorization data returned")
+	}
+
+	data := res.AuthorizationData[0]
+	decoded, err := base64.StdEncoding.DecodeString(data.AuthorizationToken)
+	if err != nil {
+		return types.DockerAuthConfig{}, time.Time{}, errors.Wrap(err, "cannot decode authorization token")
+	}
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return types.DockerAuthConfig{}, time.Time{}, errors.New("invalid authorization token")
+	}
+
+	return types.DockerAuthConfig{
+		Username: username,
+		Password: password,
+	}, time.Unix(int64(data.ExpiresAt), 0), nil
+}
+
+// region returns the AWS region of the registry
+func (p *ecr) region(domain string) string {
+	if p.cfg.Region != "" {
+		return p.cfg.Region
+	}
+	if match := ecrDomainRegexp.FindStringSubmatch(domain); match != nil {
+		return match[1]
+	}
+	return firstNonEmpty(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+}
+
+// credentials resolves AWS credentials from registry options, environment
+// variables, web identity, the shared credentials file, the ECS container
+// credentials endpoint or the EC2 instance metadata service, in this order.
+func (p *ecr) credentials(ctx context.Context, region string) (awsCredentials, error) {
+	if p.cfg.AccessKeyID != "" {
+		secretAccessKey, err := secret.GetSecret(p.cfg.SecretAccessKey, p.cfg.SecretAccessKeyFile)
+		if err != nil {
+			return awsCredentials{}, errors.Wrap(err, "cannot retrieve secret access key")
+		}
+		return awsCredentials{
+			AccessKeyID:     p.cfg.AccessKeyID,
+			SecretAccessKey: strings.TrimSpace(secretAccessKey),
+			SessionToken:    p.cfg.SessionToken,
+		}, nil
+	}
+
+	if accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID"); accessKeyID != "" {
+		return awsCredentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+
+	roleARN := firstNonEmpty(p.cfg.RoleARN, os.Getenv("AWS_ROLE_ARN"))
+	tokenFile := firstNonEmpty(p.cfg.WebIdentityTokenFile, os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"))
+	if roleARN != "" && tokenFile != "" {
+		return p.assumeRoleWithWebIdentity(ctx, region, roleARN, tokenFile)
+	}
+
+	creds, err := sharedCredentials()
+	if err == nil {
+		return creds, nil
+	}
+
+	if os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI") != "" || os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI") != "" {
+		return p.containerCredentials(ctx)
+	}
+
+	if disabled, _ := strconv.ParseBool(os.Getenv("AWS_EC2_METADATA_DISABLED")); disabled {
+		return awsCredentials{}, err
+	}
+	creds, imdsErr := p.instanceCredentials(ctx)
+	if imdsErr != nil {
+		return awsCredentials{}, errors.Errorf("%v (instance metadata: %v)", err, imdsErr)
+	}
+	return creds, nil
+}
+
+// awsRoleCredentials is the credentials document returned by the ECS
+// container credentials endpoint and the EC2 instance metadata service.
+type awsRoleCredentials struct {
+	Code            string `json:"Code"`
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	Token           string `json:"Token"`
+}
+
+// containerCredentials retrieves the credentials of the ECS task role (or
+// EKS pod identity) from the container credentials endpoint.
+func (p *ecr) containerCredentials(ctx context.Context) (awsCredentials, error) {
+	endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri != "" {
+		endpoint = "http://169.254.170.2" + uri
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+
+	token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if tokenFile := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); tokenFile != "" {
+		b, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return awsCredentials{}, errors.Wrap(err, "cannot read container authorization token")
+		}
+		token = string(b)
+	}
+	if token = strings.TrimSpace(token); token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	var res awsRoleCredentials
+	if err := doJSON(p.hc, req, p.userAgent, &res); err != nil {
+		return awsCredentials{}, errors.Wrap(err, "cannot retrieve container credentials")
+	}
+	if res.AccessKeyID == "" {
+		return awsCredentials{}, errors.New("no container credentials returned")
+	}
+
+	return awsCredentials{
+		AccessKeyID:     res.AccessKeyID,
+		SecretAccessKey: res.SecretAccessKey,
+		SessionToken:    res.Token,
+	}, nil
+}
+
+// instanceCredentials retrieves the credentials of the EC2 instance profile
+// from the instance metadata service. IMDSv2 is used when available.
+func (p *ecr) instanceCredentials(ctx context.Context) (awsCredentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, imdsTimeout)
+	defer cancel()
+
+	endpoint := strings.TrimSuffix(firstNonEmpty(os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT"), "http://169.254.169.254"), "/")
+
+	// a session token is required by IMDSv2, IMDSv1 is used as a fallback
+	var token string
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "21600")
+	req.Header.Set("User-Agent", p.userAgent)
+	resp, err := p.hc.Do(req)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		b, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if err != nil {
+			resp.Body.Close()
+			return awsCredentials{}, err
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	resp.Body.Close()
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/", nil)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	if token != "" {
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+	resp, err = p.hc.Do(req)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body.Close()
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return awsCredentials{}, errors.Errorf("unexpected HTTP status %d from %s", resp.StatusCode, req.URL.Host)
+	}
+	role, _, _ := strings.Cut(strings.TrimSpace(string(b)), "\n")
+	if role == "" {
+		return awsCredentials{}, errors.New("no instance profile attached")
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/"+url.PathEscape(role), nil)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	if token != "" {
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+	}
+	var res awsRoleCredentials
+	if err := doJSON(p.hc, req, p.userAgent, &res); err != nil {
+		return awsCredentials{}, err
+	}
+	if res.Code != "" && res.Code != "Success" {
+		return awsCredentials{}, errors.Errorf("cannot retrieve instance profile credentials: %s", res.Code)
+	}
+
+	return awsCredentials{
+		AccessKeyID:     res.AccessKeyID,
+		SecretAccessKey: res.SecretAccessKey,
+		SessionToken:    res.Token,
+	}, nil
+}
+
+// assumeRoleWithWebIdentity exchanges a web identity token (e.g. IRSA on EKS)
+// for temporary credentials through AWS STS.
+func (p *ecr) assumeRoleWithWebIdentity(ctx context.Context, region, roleARN, tokenFile string) (awsCredentials, error) {
+	webIdentityToken, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return awsCredentials{}, errors.Wrap(err, "cannot read web identity token")
+	}
+
+	endpoint := firstNonEmpty(p.cfg.STSEndpoint, fmt.Sprintf("https://sts.%s.amazonaws.com", region))
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {roleARN},
+		"RoleSessionName":  {firstNonEmpty(os.Getenv("AWS_ROLE_SESSION_NAME"), "diun")},
+		"WebIdentityToken": {strings.TrimSpace(string(webIdentityToken))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", p.userAgent)
+
+	resp, err := p.hc.Do(req)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return awsCredentials{}, errors.Errorf("unexpected HTTP status %d from %s", resp.StatusCode, req.URL.Host)
+	}
+
+	var res struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return awsCredentials{}, errors.Wrap(err, "cannot decode STS response")
+	}
+
+	return awsCredentials{
+		AccessKeyID:     res.Credentials.AccessKeyID,
+		SecretAccessKey: res.Credentials.SecretAccessKey,
+		SessionToken:    res.Credentials.SessionToken,
+	}, nil
+}
+
+// sharedCredentials reads credentials of the current profile from the AWS
+// shared credentials file.
+func sharedCredentials() (awsCredentials, error) {
+	filename := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if filename == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return awsCredentials{}, errors.New("no AWS credentials found")
+		}
+		filename = filepath.Join(home, ".aws", "credentials")
+	}
+	profile := firstNonEmpty(os.Getenv("AWS_PROFILE"), "default")
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return awsCredentials{}, errors.New("no AWS credentials found")
+	}
+	defer f.Close()
+
+	var creds awsCredentials
+	var section string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if section != profile {
+			continue
+		}
+		key, value, _ := strings.Cut(line, "=")
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			creds.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return awsCredentials{}, err
+	}
+	if creds.AccessKeyID == "" {
+		return awsCredentials{}, errors.Errorf("no AWS credentials found for profile %s", profile)
+	}
+
+	return creds, nil
+}
@@ -0,0 +1,214 @@
+package regauth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignV4(t *testing.T) {
+	// get-vanilla from the AWS Signature Version 4 test suite
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	signV4(req, nil, awsCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get("Authorization"))
+}
+
+func TestECRRegion(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	p := &ecr{}
+	assert.Equal(t, "eu-west-1", p.region("123456789012.dkr.ecr.eu-west-1.amazonaws.com"))
+	assert.Equal(t, "us-gov-west-1", p.region("123456789012.dkr.ecr-fips.us-gov-west-1.amazonaws.com"))
+	assert.Equal(t, "cn-north-1", p.region("123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn"))
+	assert.Empty(t, p.region("docker.io"))
+
+	t.Setenv("AWS_DEFAULT_REGION", "us-east-2")
+	assert.Equal(t, "us-east-2", p.region("registry.example.com"))
+
+	p = &ecr{cfg: model.RegOptECR{Region: "ap-south-1"}}
+	assert.Equal(t, "ap-south-1", p.region("123456789012.dkr.ecr.eu-west-1.amazonaws.com"))
+}
+
+func TestECRFetch(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "{}", string(body))
+		assert.Equal(t, "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken", r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "application/x-amz-json-1.1", r.Header.Get("Content-Type"))
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/20240101/eu-west-1/ecr/aws4_request, "))
+		_, _ = fmt.Fprintf(w, `{"authorizationData":[{"authorizationToken":%q,"expiresAt":%d,"proxyEndpoint":"https://123456789012.dkr.ecr.eu-west-1.amazonaws.com"}]}`,
+			base64.StdEncoding.EncodeToString([]byte("AWS:password")), expiresAt.Unix())
+	}))
+	defer ts.Close()
+
+	p := &ecr{
+		cfg: model.RegOptECR{
+			AccessKeyID:     "AKID",
+			SecretAccessKey: "secret",
+			SessionToken:    "session",
+			Endpoint:        ts.URL,
+		},
+		hc:  ts.Client(),
+		now: func() time.Time { return expiresAt.Add(-12 * time.Hour) },
+	}
+
+	auth, exp, err := p.fetch(t.Context(), "123456789012.dkr.ecr.eu-west-1.amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, "AWS", auth.Username)
+	assert.Equal(t, "password", auth.Password)
+	assert.True(t, expiresAt.Equal(exp))
+}
+
+func TestECRCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_ROLE_ARN", "")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	t.Run("regopt", func(t *testing.T) {
+		secretFile := filepath.Join(t.TempDir(), "secret")
+		require.NoError(t, os.WriteFile(secretFile, []byte("secret\n"), 0o600))
+
+		p := &ecr{cfg: model.RegOptECR{AccessKeyID: "AKID", SecretAccessKeyFile: secretFile}}
+		creds, err := p.credentials(t.Context(), "eu-west-1")
+		require.NoError(t, err)
+		assert.Equal(t, awsCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, creds)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "secretenv")
+		t.Setenv("AWS_SESSION_TOKEN", "")
+
+		creds, err := (&ecr{}).credentials(t.Context(), "eu-west-1")
+		require.NoError(t, err)
+		assert.Equal(t, awsCredentials{AccessKeyID: "AKIDENV", SecretAccessKey: "secretenv"}, creds)
+	})
+
+	t.Run("web identity", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "AssumeRoleWithWebIdentity", r.PostForm.Get("Action"))
+			assert.Equal(t, "arn:aws:iam::123456789012:role/diun", r.PostForm.Get("RoleArn"))
+			assert.Equal(t, "jwt", r.PostForm.Get("WebIdentityToken"))
+			_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIA</AccessKeyId>
+      <SecretAccessKey>stssecret</SecretAccessKey>
+      <SessionToken>stssession</SessionToken>
+      <Expiration>2024-01-01T01:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`))
+		}))
+		defer ts.Close()
+
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("jwt\n"), 0o600))
+		t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/diun")
+		t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tokenFile)
+
+		p := &ecr{cfg: model.RegOptECR{STSEndpoint: ts.URL}, hc: ts.Client()}
+		creds, err := p.credentials(t.Context(), "eu-west-1")
+		require.NoError(t, err)
+		assert.Equal(t, awsCredentials{AccessKeyID: "ASIA", SecretAccessKey: "stssecret", SessionToken: "stssession"}, creds)
+	})
+
+	t.Run("shared credentials", func(t *testing.T) {
+		credsFile := filepath.Join(t.TempDir(), "credentials")
+		require.NoError(t, os.WriteFile(credsFile, []byte(`[default]
+aws_access_key_id = AKIDDEFAULT
+aws_secret_access_key = secretdefault
+
+[prod]
+aws_access_key_id = AKIDPROD
+aws_secret_access_key = secretprod
+aws_session_token = sessionprod
+`), 0o600))
+		t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credsFile)
+		t.Setenv("AWS_PROFILE", "prod")
+
+		creds, err := (&ecr{}).credentials(t.Context(), "eu-west-1")
+		require.NoError(t, err)
+		assert.Equal(t, awsCredentials{AccessKeyID: "AKIDPROD", SecretAccessKey: "secretprod", SessionToken: "sessionprod"}, creds)
+
+		t.Setenv("AWS_PROFILE", "unknown")
+		_, err = (&ecr{}).credentials(t.Context(), "eu-west-1")
+		require.EqualError(t, err, "no AWS credentials found for profile unknown")
+	})
+
+	t.Run("container credentials", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v2/credentials/task", r.URL.Path)
+			assert.Equal(t, "authtoken", r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"AccessKeyId":"ASIAECS","SecretAccessKey":"ecssecret","Token":"ecssession","Expiration":"2024-01-01T01:00:00Z"}`))
+		}))
+		defer ts.Close()
+
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("authtoken\n"), 0o600))
+		t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+		t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", ts.URL+"/v2/credentials/task")
+		t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", tokenFile)
+
+		creds, err := (&ecr{hc: ts.Client()}).credentials(t.Context(), "eu-west-1")
+		require.NoError(t, err)
+		assert.Equal(t, awsCredentials{AccessKeyID: "ASIAECS", SecretAccessKey: "ecssecret", SessionToken: "ecssession"}, creds)
+	})
+
+	t.Run("instance metadata", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut {
+				assert.Equal(t, "/latest/api/token", r.URL.Path)
+				assert.Equal(t, "21600", r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds"))
+				_, _ = w.Write([]byte("imdstoken"))
+				return
+			}
+			assert.Equal(t, "imdstoken", r.Header.Get("X-aws-ec2-metadata-token"))
+			switch r.URL.Path {
+			case "/latest/meta-data/iam/security-credentials/":
+				_, _ = w.Write([]byte("diun-role"))
+			case "/latest/meta-data/iam/security-credentials/diun-role":
+				_, _ = w.Write([]byte(`{"Code":"Success","AccessKeyId":"ASIAEC2","SecretAccessKey":"ec2secret","Token":"ec2session"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer ts.Close()
+
+		t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+		t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+		t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", ts.URL)
+
+		creds, err := (&ecr{hc: ts.Client()}).credentials(t.Context(), "eu-west-1")
+		require.NoError(t, err)
+		assert.Equal(t, awsCredentials{AccessKeyID: "ASIAEC2", SecretAccessKey: "ec2secret", SessionToken: "ec2session"}, creds)
+	})
+}
@@ -0,0 +1,166 @@
+package regauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/types"
+)
+
+const (
+	garUsername = "oauth2accesstoken"
+	garScope    = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// gar retrieves credentials for Google Artifact Registry and Container
+// Registry from a service account key or the metadata server.
+type gar struct {
+	cfg       model.RegOptGAR
+	hc        *http.Client
+	userAgent string
+	now       func() time.Time
+}
+
+type garServiceAccount struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+}
+
+type garToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (p *gar) name() string {
+	return "gar"
+}
+
+func (p *gar) fetch(ctx context.Context, _ string) (types.DockerAuthConfig, time.Time, error) {
+	var tok garToken
+	var err error
+	if filename := firstNonEmpty(p.cfg.ServiceAccountFile, os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")); filename != "" {
+		tok, err = p.serviceAccountToken(ctx, filename)
+	} else {
+		tok, err = p.metadataToken(ctx)
+	}
+	if err != nil {
+		return types.DockerAuthConfig{}, time.Time{}, err
+	}
+	if tok.AccessToken == "" {
+		return types.DockerAuthConfig{}, time.Time{}, errors.New("no access token returned")
+	}
+
+	return types.DockerAuthConfig{
+		Username: garUsername,
+		Password: tok.AccessToken,
+	}, expiresIn(p.now(), tok.ExpiresIn), nil
+}
+
+// serviceAccountToken exchanges a self-signed JWT of a service account key
+// for an access token.
+func (p *gar) serviceAccountToken(ctx context.Context, filename string) (garToken, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return garToken{}, errors.Wrap(err, "cannot read service account file")
+	}
+	var sa garServiceAccount
+	if err := json.Unmarshal(b, &sa); err != nil {
+		return garToken{}, errors.Wrap(err, "cannot decode service account file")
+	}
+	if sa.Type != "service_account" {
+		return garToken{}, errors.Errorf("unsupported credentials type %q", sa.Type)
+	}
+	if sa.TokenURI == "" {
+		sa.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	assertion, err := p.signJWT(sa)
+	if err != nil {
+		return garToken{}, err
+	}
+
+	var tok garToken
+	err = postForm(ctx, p.hc, sa.TokenURI, p.userAgent, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}, &tok)
+	return tok, err
+}
+
+func (p *gar) signJWT(sa garServiceAccount) (string, error) {
+	block, _ := pem.Decode([]byte(sa.PrivateKey))
+	if block == nil {
+		return "", errors.New("cannot decode service account private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return "", errors.Wrap(err, "cannot parse service account private key")
+		}
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return "", errors.New("service account private key is not an RSA key")
+	}
+
+	now := p.now()
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": sa.PrivateKeyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":   sa.ClientEmail,
+		"scope": garScope,
+		"aud":   sa.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrap(err, "cannot sign service account assertion")
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// metadataToken retrieves an access token of the attached service account
+// from the metadata server (GCE, GKE workload identity, Cloud Run).
+func (p *gar) metadataToken(ctx context.Context) (garToken, error) {
+	host := firstNonEmpty(os.Getenv("GCE_METADATA_HOST"), "metadata.google.internal")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/computeMetadata/v1/instance/service-accounts/default/token", host), nil)
+	if err != nil {
+		return garToken{}, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var tok garToken
+	err = doJSON(p.hc, req, p.userAgent, &tok)
+	return tok, err
+}
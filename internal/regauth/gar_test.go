@@ -0,0 +1,100 @@
+package regauth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGARServiceAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		require.Len(t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		var claims map[string]any
+		require.NoError(t, json.Unmarshal(payload, &claims))
+		assert.Equal(t, "diun@project.iam.gserviceaccount.com", claims["iss"])
+		assert.Equal(t, garScope, claims["scope"])
+
+		_, _ = w.Write([]byte(`{"access_token":"ya29.token","expires_in":3599,"token_type":"Bearer"}`))
+	}))
+	defer ts.Close()
+
+	saFile := filepath.Join(t.TempDir(), "sa.json")
+	sa, err := json.Marshal(garServiceAccount{
+		Type:         "service_account",
+		ClientEmail:  "diun@project.iam.gserviceaccount.com",
+		PrivateKeyID: "kid",
+		PrivateKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		TokenURI:     ts.URL,
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(saFile, sa, 0o600))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &gar{
+		cfg: model.RegOptGAR{ServiceAccountFile: saFile},
+		hc:  ts.Client(),
+		now: func() time.Time { return now },
+	}
+
+	auth, exp, err := p.fetch(t.Context(), "europe-docker.pkg.dev")
+	require.NoError(t, err)
+	assert.Equal(t, "oauth2accesstoken", auth.Username)
+	assert.Equal(t, "ya29.token", auth.Password)
+	assert.Equal(t, now.Add(3599*time.Second), exp)
+}
+
+func TestGARMetadata(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/computeMetadata/v1/instance/service-accounts/default/token", r.URL.Path)
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"ya29.metadata","expires_in":1800,"token_type":"Bearer"}`))
+	}))
+	defer ts.Close()
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(ts.URL, "http://"))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &gar{hc: ts.Client(), now: func() time.Time { return now }}
+
+	auth, exp, err := p.fetch(t.Context(), "gcr.io")
+	require.NoError(t, err)
+	assert.Equal(t, "oauth2accesstoken", auth.Username)
+	assert.Equal(t, "ya29.metadata", auth.Password)
+	assert.Equal(t, now.Add(30*time.Minute), exp)
+}
@@ -0,0 +1,154 @@
+package regauth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/httputil"
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/types"
+)
+
+// refreshMargin is the delay before expiration at which cached credentials
+// are refreshed so a registry check never uses an expired token.
+const refreshMargin = 5 * time.Minute
+
+// fetchTimeout is the timeout applied to token requests
+const fetchTimeout = 30 * time.Second
+
+// Provider retrieves short-lived registry credentials from a cloud provider
+type Provider interface {
+	Name() string
+	Credentials(domain string) (types.DockerAuthConfig, error)
+}
+
+type fetcher interface {
+	name() string
+	fetch(ctx context.Context, domain string) (types.DockerAuthConfig, time.Time, error)
+}
+
+type token struct {
+	auth      types.DockerAuthConfig
+	expiresAt time.Time
+}
+
+// cache caches credentials per registry domain until they are about to expire
+type cache struct {
+	mu      sync.Mutex
+	fetcher fetcher
+	tokens  map[string]token
+	now     func() time.Time
+}
+
+// New creates a credentials provider for the cloud authentication configured
+// in registry options. It returns nil if none is configured.
+func New(regopt model.RegOpt, userAgent string) (Provider, error) {
+	hc, err := httputil.NewClient(regopt.Proxy, regopt.InsecureTLS != nil && *regopt.InsecureTLS, regopt.TLSCACertFiles)
+	if err != nil {
+		return nil, err
+	}
+	hc.Timeout = fetchTimeout
+
+	var f fetcher
+	switch {
+	case regopt.ECR != nil:
+		f = &ecr{cfg: *regopt.ECR, hc: &hc, userAgent: userAgent, now: time.Now}
+	case regopt.GAR != nil:
+		f = &gar{cfg: *regopt.GAR, hc: &hc, userAgent: userAgent, now: time.Now}
+	case regopt.ACR != nil:
+		f = &acr{cfg: *regopt.ACR, hc: &hc, userAgent: userAgent}
+	default:
+		return nil, nil
+	}
+
+	return newCache(f), nil
+}
+
+func newCache(f fetcher) *cache {
+	return &cache{
+		fetcher: f,
+		tokens:  make(map[string]token),
+		now:     time.Now,
+	}
+}
+
+// Name returns the name of the cloud provider
+func (c *cache) Name() string {
+	return c.fetcher.name()
+}
+
+// Credentials returns cached credentials for the registry domain or fetches
+// new ones if missing or about to expire.
+func (c *cache) Credentials(domain string) (types.DockerAuthConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if tok, ok := c.tokens[domain]; ok && tok.expiresAt.Sub(c.now()) > refreshMargin {
+		return tok.auth, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	auth, expiresAt, err := c.fetcher.fetch(ctx, domain)
+	if err != nil {
+		return types.DockerAuthConfig{}, errors.Wrapf(err, "cannot retrieve %s credentials", c.fetcher.name())
+	}
+	c.tokens[domain] = token{
+		auth:      auth,
+		expiresAt: expiresAt,
+	}
+
+	return auth, nil
+}
+
+// postForm sends a form to an endpoint and decodes the JSON response
+func postForm(ctx context.Context, hc *http.Client, endpoint string, userAgent string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doJSON(hc, req, userAgent, v)
+}
+
+// doJSON sends a request and decodes the JSON response
+func doJSON(hc *http.Client, req *http.Request, userAgent string, v any) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("unexpected HTTP status %d from %s: %s", resp.StatusCode, req.URL.Host, strings.TrimSpace(string(body)))
+	}
+
+	return errors.Wrapf(json.NewDecoder(resp.Body).Decode(v), "cannot decode response from %s", req.URL.Host)
+}
+
+// expiresIn returns the expiration time of a token valid for a number of seconds
+func expiresIn(now time.Time, seconds int64) time.Time {
+	return now.Add(time.Duration(seconds) * time.Second)
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
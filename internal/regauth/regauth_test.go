@@ -0,0 +1,98 @@
+package regauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/diun/v4/internal/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/types"
+)
+
+type fakeFetcher struct {
+	calls     int
+	expiresIn time.Duration
+	err       error
+	now       func() time.Time
+}
+
+func (f *fakeFetcher) name() string {
+	return "fake"
+}
+
+func (f *fakeFetcher) fetch(_ context.Context, domain string) (types.DockerAuthConfig, time.Time, error) {
+	f.calls++
+	if f.err != nil {
+		return types.DockerAuthConfig{}, time.Time{}, f.err
+	}
+	return types.DockerAuthConfig{
+		Username: domain,
+		Password: "token",
+	}, f.now().Add(f.expiresIn), nil
+}
+
+func TestNew(t *testing.T) {
+	prv, err := New(model.RegOpt{Name: "foo"}, "diun/test")
+	require.NoError(t, err)
+	assert.Nil(t, prv)
+
+	prv, err = New(model.RegOpt{Name: "foo", ECR: &model.RegOptECR{}}, "diun/test")
+	require.NoError(t, err)
+	require.NotNil(t, prv)
+	assert.Equal(t, "ecr", prv.Name())
+
+	prv, err = New(model.RegOpt{Name: "foo", GAR: &model.RegOptGAR{}}, "diun/test")
+	require.NoError(t, err)
+	require.NotNil(t, prv)
+	assert.Equal(t, "gar", prv.Name())
+
+	prv, err = New(model.RegOpt{Name: "foo", ACR: &model.RegOptACR{}}, "diun/test")
+	require.NoError(t, err)
+	require.NotNil(t, prv)
+	assert.Equal(t, "acr", prv.Name())
+}
+
+func TestCacheCredentials(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	f := &fakeFetcher{expiresIn: time.Hour, now: clock}
+	c := newCache(f)
+	c.now = clock
+
+	auth, err := c.Credentials("registry.test")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.test", auth.Username)
+	assert.Equal(t, 1, f.calls)
+
+	// cached
+	now = now.Add(30 * time.Minute)
+	_, err = c.Credentials("registry.test")
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.calls)
+
+	// cached per domain
+	_, err = c.Credentials("other.test")
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.calls)
+
+	// refreshed before expiration
+	now = now.Add(26 * time.Minute)
+	_, err = c.Credentials("registry.test")
+	require.NoError(t, err)
+	assert.Equal(t, 3, f.calls)
+}
+
+func TestCacheCredentialsError(t *testing.T) {
+	f := &fakeFetcher{err: errors.New("denied"), now: time.Now}
+	c := newCache(f)
+
+	_, err := c.Credentials("registry.test")
+	require.EqualError(t, err, "cannot retrieve fake credentials: denied")
+
+	_, err = c.Credentials("registry.test")
+	require.Error(t, err)
+	assert.Equal(t, 2, f.calls)
+}
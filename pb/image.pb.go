@@ -31,6 +31,7 @@ type Manifest struct {
 	Labels        map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Platform      string                 `protobuf:"bytes,6,opt,name=platform,proto3" json:"platform,omitempty"`
 	Size          int64                  `protobuf:"varint,7,opt,name=size,proto3" json:"size,omitempty"`
+	ArtifactType  string                 `protobuf:"bytes,8,opt,name=artifact_type,json=artifactType,proto3" json:"artifact_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -114,6 +115,13 @@ func (x *Manifest) GetSize() int64 {
 	return 0
 }
 
+func (x *Manifest) GetArtifactType() string {
+	if x != nil {
+		return x.ArtifactType
+	}
+	return ""
+}
+
 type ImageListRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -466,6 +474,166 @@ func (x *ImagePruneResponse) GetImages() []*ImagePruneResponse_Image {
 	return nil
 }
 
+type ImageDiffRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageDiffRequest) Reset() {
+	*x = ImageDiffRequest{}
+	mi := &file_image_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageDiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageDiffRequest) ProtoMessage() {}
+
+func (x *ImageDiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_image_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageDiffRequest.ProtoReflect.Descriptor instead.
+func (*ImageDiffRequest) Descriptor() ([]byte, []int) {
+	return file_image_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ImageDiffRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ImageDiffResponse struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Stored        *Manifest                   `protobuf:"bytes,1,opt,name=stored,proto3" json:"stored,omitempty"`
+	Remote        *Manifest                   `protobuf:"bytes,2,opt,name=remote,proto3" json:"remote,omitempty"`
+	LayersAdded   []string                    `protobuf:"bytes,3,rep,name=layers_added,json=layersAdded,proto3" json:"layers_added,omitempty"`
+	LayersRemoved []string                    `protobuf:"bytes,4,rep,name=layers_removed,json=layersRemoved,proto3" json:"layers_removed,omitempty"`
+	SizeDelta     int64                       `protobuf:"varint,5,opt,name=size_delta,json=sizeDelta,proto3" json:"size_delta,omitempty"`
+	Labels        []*ImageDiffResponse_Change `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty"`
+	Env           []*ImageDiffResponse_Change `protobuf:"bytes,7,rep,name=env,proto3" json:"env,omitempty"`
+	Entrypoint    *ImageDiffResponse_Change   `protobuf:"bytes,8,opt,name=entrypoint,proto3" json:"entrypoint,omitempty"`
+	Cmd           *ImageDiffResponse_Change   `protobuf:"bytes,9,opt,name=cmd,proto3" json:"cmd,omitempty"`
+	Annotations   []*ImageDiffResponse_Change `protobuf:"bytes,10,rep,name=annotations,proto3" json:"annotations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageDiffResponse) Reset() {
+	*x = ImageDiffResponse{}
+	mi := &file_image_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageDiffResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageDiffResponse) ProtoMessage() {}
+
+func (x *ImageDiffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_image_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageDiffResponse.ProtoReflect.Descriptor instead.
+func (*ImageDiffResponse) Descriptor() ([]byte, []int) {
+	return file_image_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ImageDiffResponse) GetStored() *Manifest {
+	if x != nil {
+		return x.Stored
+	}
+	return nil
+}
+
+func (x *ImageDiffResponse) GetRemote() *Manifest {
+	if x != nil {
+		return x.Remote
+	}
+	return nil
+}
+
+func (x *ImageDiffResponse) GetLayersAdded() []string {
+	if x != nil {
+		return x.LayersAdded
+	}
+	return nil
+}
+
+func (x *ImageDiffResponse) GetLayersRemoved() []string {
+	if x != nil {
+		return x.LayersRemoved
+	}
+	return nil
+}
+
+func (x *ImageDiffResponse) GetSizeDelta() int64 {
+	if x != nil {
+		return x.SizeDelta
+	}
+	return 0
+}
+
+func (x *ImageDiffResponse) GetLabels() []*ImageDiffResponse_Change {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ImageDiffResponse) GetEnv() []*ImageDiffResponse_Change {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ImageDiffResponse) GetEntrypoint() *ImageDiffResponse_Change {
+	if x != nil {
+		return x.Entrypoint
+	}
+	return nil
+}
+
+func (x *ImageDiffResponse) GetCmd() *ImageDiffResponse_Change {
+	if x != nil {
+		return x.Cmd
+	}
+	return nil
+}
+
+func (x *ImageDiffResponse) GetAnnotations() []*ImageDiffResponse_Change {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
 type ImageListResponse_Image struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -477,7 +645,7 @@ type ImageListResponse_Image struct {
 
 func (x *ImageListResponse_Image) Reset() {
 	*x = ImageListResponse_Image{}
-	mi := &file_image_proto_msgTypes[10]
+	mi := &file_image_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -489,7 +657,7 @@ func (x *ImageListResponse_Image) String() string {
 func (*ImageListResponse_Image) ProtoMessage() {}
 
 func (x *ImageListResponse_Image) ProtoReflect() protoreflect.Message {
-	mi := &file_image_proto_msgTypes[10]
+	mi := &file_image_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -536,7 +704,7 @@ type ImageInspectResponse_Image struct {
 
 func (x *ImageInspectResponse_Image) Reset() {
 	*x = ImageInspectResponse_Image{}
-	mi := &file_image_proto_msgTypes[11]
+	mi := &file_image_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -548,7 +716,7 @@ func (x *ImageInspectResponse_Image) String() string {
 func (*ImageInspectResponse_Image) ProtoMessage() {}
 
 func (x *ImageInspectResponse_Image) ProtoReflect() protoreflect.Message {
-	mi := &file_image_proto_msgTypes[11]
+	mi := &file_image_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -588,7 +756,7 @@ type ImagePruneResponse_Image struct {
 
 func (x *ImagePruneResponse_Image) Reset() {
 	*x = ImagePruneResponse_Image{}
-	mi := &file_image_proto_msgTypes[12]
+	mi := &file_image_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -600,7 +768,7 @@ func (x *ImagePruneResponse_Image) String() string {
 func (*ImagePruneResponse_Image) ProtoMessage() {}
 
 func (x *ImagePruneResponse_Image) ProtoReflect() protoreflect.Message {
-	mi := &file_image_proto_msgTypes[12]
+	mi := &file_image_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -630,11 +798,71 @@ func (x *ImagePruneResponse_Image) GetManifests() []*Manifest {
 	return nil
 }
 
+type ImageDiffResponse_Change struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageDiffResponse_Change) Reset() {
+	*x = ImageDiffResponse_Change{}
+	mi := &file_image_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageDiffResponse_Change) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageDiffResponse_Change) ProtoMessage() {}
+
+func (x *ImageDiffResponse_Change) ProtoReflect() protoreflect.Message {
+	mi := &file_image_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageDiffResponse_Change.ProtoReflect.Descriptor instead.
+func (*ImageDiffResponse_Change) Descriptor() ([]byte, []int) {
+	return file_image_proto_rawDescGZIP(), []int{10, 0}
+}
+
+func (x *ImageDiffResponse_Change) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImageDiffResponse_Change) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ImageDiffResponse_Change) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
 var File_image_proto protoreflect.FileDescriptor
 
 const file_image_proto_rawDesc = "" +
 	"\n" +
-	"\vimage.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa4\x02\n" +
+	"\vimage.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc9\x02\n" +
 	"\bManifest\x12\x10\n" +
 	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x1b\n" +
 	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x16\n" +
@@ -642,7 +870,8 @@ const file_image_proto_rawDesc = "" +
 	"\acreated\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x120\n" +
 	"\x06labels\x18\x05 \x03(\v2\x18.pb.Manifest.LabelsEntryR\x06labels\x12\x1a\n" +
 	"\bplatform\x18\x06 \x01(\tR\bplatform\x12\x12\n" +
-	"\x04size\x18\a \x01(\x03R\x04size\x1a9\n" +
+	"\x04size\x18\a \x01(\x03R\x04size\x12#\n" +
+	"\rartifact_type\x18\b \x01(\tR\fartifactType\x1a9\n" +
 	"\vLabelsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x12\n" +
@@ -671,13 +900,35 @@ const file_image_proto_rawDesc = "" +
 	"\x06images\x18\x01 \x03(\v2\x1c.pb.ImagePruneResponse.ImageR\x06images\x1aG\n" +
 	"\x05Image\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12*\n" +
-	"\tmanifests\x18\x02 \x03(\v2\f.pb.ManifestR\tmanifests2\x90\x02\n" +
+	"\tmanifests\x18\x02 \x03(\v2\f.pb.ManifestR\tmanifests\"&\n" +
+	"\x10ImageDiffRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x9e\x04\n" +
+	"\x11ImageDiffResponse\x12$\n" +
+	"\x06stored\x18\x01 \x01(\v2\f.pb.ManifestR\x06stored\x12$\n" +
+	"\x06remote\x18\x02 \x01(\v2\f.pb.ManifestR\x06remote\x12!\n" +
+	"\flayers_added\x18\x03 \x03(\tR\vlayersAdded\x12%\n" +
+	"\x0elayers_removed\x18\x04 \x03(\tR\rlayersRemoved\x12\x1d\n" +
+	"\n" +
+	"size_delta\x18\x05 \x01(\x03R\tsizeDelta\x124\n" +
+	"\x06labels\x18\x06 \x03(\v2\x1c.pb.ImageDiffResponse.ChangeR\x06labels\x12.\n" +
+	"\x03env\x18\a \x03(\v2\x1c.pb.ImageDiffResponse.ChangeR\x03env\x12<\n" +
+	"\n" +
+	"entrypoint\x18\b \x01(\v2\x1c.pb.ImageDiffResponse.ChangeR\n" +
+	"entrypoint\x12.\n" +
+	"\x03cmd\x18\t \x01(\v2\x1c.pb.ImageDiffResponse.ChangeR\x03cmd\x12>\n" +
+	"\vannotations\x18\n" +
+	" \x03(\v2\x1c.pb.ImageDiffResponse.ChangeR\vannotations\x1a@\n" +
+	"\x06Change\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to2\xcc\x02\n" +
 	"\fImageService\x12:\n" +
 	"\tImageList\x12\x14.pb.ImageListRequest\x1a\x15.pb.ImageListResponse\"\x00\x12C\n" +
 	"\fImageInspect\x12\x17.pb.ImageInspectRequest\x1a\x18.pb.ImageInspectResponse\"\x00\x12@\n" +
 	"\vImageRemove\x12\x16.pb.ImageRemoveRequest\x1a\x17.pb.ImageRemoveResponse\"\x00\x12=\n" +
 	"\n" +
-	"ImagePrune\x12\x15.pb.ImagePruneRequest\x1a\x16.pb.ImagePruneResponse\"\x00B\x1eZ\x1cgithub.com/crazy-max/diun/pbb\x06proto3"
+	"ImagePrune\x12\x15.pb.ImagePruneRequest\x1a\x16.pb.ImagePruneResponse\"\x00\x12:\n" +
+	"\tImageDiff\x12\x14.pb.ImageDiffRequest\x1a\x15.pb.ImageDiffResponse\"\x00B\x1eZ\x1cgithub.com/crazy-max/diun/pbb\x06proto3"
 
 var (
 	file_image_proto_rawDescOnce sync.Once
@@ -691,7 +942,7 @@ func file_image_proto_rawDescGZIP() []byte {
 	return file_image_proto_rawDescData
 }
 
-var file_image_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_image_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_image_proto_goTypes = []any{
 	(*Manifest)(nil),                   // 0: pb.Manifest
 	(*ImageListRequest)(nil),           // 1: pb.ImageListRequest
@@ -702,35 +953,47 @@ var file_image_proto_goTypes = []any{
 	(*ImageRemoveResponse)(nil),        // 6: pb.ImageRemoveResponse
 	(*ImagePruneRequest)(nil),          // 7: pb.ImagePruneRequest
 	(*ImagePruneResponse)(nil),         // 8: pb.ImagePruneResponse
-	nil,                                // 9: pb.Manifest.LabelsEntry
-	(*ImageListResponse_Image)(nil),    // 10: pb.ImageListResponse.Image
-	(*ImageInspectResponse_Image)(nil), // 11: pb.ImageInspectResponse.Image
-	(*ImagePruneResponse_Image)(nil),   // 12: pb.ImagePruneResponse.Image
-	(*timestamppb.Timestamp)(nil),      // 13: google.protobuf.Timestamp
+	(*ImageDiffRequest)(nil),           // 9: pb.ImageDiffRequest
+	(*ImageDiffResponse)(nil),          // 10: pb.ImageDiffResponse
+	nil,                                // 11: pb.Manifest.LabelsEntry
+	(*ImageListResponse_Image)(nil),    // 12: pb.ImageListResponse.Image
+	(*ImageInspectResponse_Image)(nil), // 13: pb.ImageInspectResponse.Image
+	(*ImagePruneResponse_Image)(nil),   // 14: pb.ImagePruneResponse.Image
+	(*ImageDiffResponse_Change)(nil),   // 15: pb.ImageDiffResponse.Change
+	(*timestamppb.Timestamp)(nil),      // 16: google.protobuf.Timestamp
 }
 var file_image_proto_depIdxs = []int32{
-	13, // 0: pb.Manifest.created:type_name -> google.protobuf.Timestamp
-	9,  // 1: pb.Manifest.labels:type_name -> pb.Manifest.LabelsEntry
-	10, // 2: pb.ImageListResponse.images:type_name -> pb.ImageListResponse.Image
-	11, // 3: pb.ImageInspectResponse.image:type_name -> pb.ImageInspectResponse.Image
+	16, // 0: pb.Manifest.created:type_name -> google.protobuf.Timestamp
+	11, // 1: pb.Manifest.labels:type_name -> pb.Manifest.LabelsEntry
+	12, // 2: pb.ImageListResponse.images:type_name -> pb.ImageListResponse.Image
+	13, // 3: pb.ImageInspectResponse.image:type_name -> pb.ImageInspectResponse.Image
 	0,  // 4: pb.ImageRemoveResponse.manifests:type_name -> pb.Manifest
-	12, // 5: pb.ImagePruneResponse.images:type_name -> pb.ImagePruneResponse.Image
-	0,  // 6: pb.ImageListResponse.Image.latest:type_name -> pb.Manifest
-	0,  // 7: pb.ImageInspectResponse.Image.manifests:type_name -> pb.Manifest
-	0,  // 8: pb.ImagePruneResponse.Image.manifests:type_name -> pb.Manifest
-	1,  // 9: pb.ImageService.ImageList:input_type -> pb.ImageListRequest
-	3,  // 10: pb.ImageService.ImageInspect:input_type -> pb.ImageInspectRequest
-	5,  // 11: pb.ImageService.ImageRemove:input_type -> pb.ImageRemoveRequest
-	7,  // 12: pb.ImageService.ImagePrune:input_type -> pb.ImagePruneRequest
-	2,  // 13: pb.ImageService.ImageList:output_type -> pb.ImageListResponse
-	4,  // 14: pb.ImageService.ImageInspect:output_type -> pb.ImageInspectResponse
-	6,  // 15: pb.ImageService.ImageRemove:output_type -> pb.ImageRemoveResponse
-	8,  // 16: pb.ImageService.ImagePrune:output_type -> pb.ImagePruneResponse
-	13, // [13:17] is the sub-list for method output_type
-	9,  // [9:13] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	14, // 5: pb.ImagePruneResponse.images:type_name -> pb.ImagePruneResponse.Image
+	0,  // 6: pb.ImageDiffResponse.stored:type_name -> pb.Manifest
+	0,  // 7: pb.ImageDiffResponse.remote:type_name -> pb.Manifest
+	15, // 8: pb.ImageDiffResponse.labels:type_name -> pb.ImageDiffResponse.Change
+	15, // 9: pb.ImageDiffResponse.env:type_name -> pb.ImageDiffResponse.Change
+	15, // 10: pb.ImageDiffResponse.entrypoint:type_name -> pb.ImageDiffResponse.Change
+	15, // 11: pb.ImageDiffResponse.cmd:type_name -> pb.ImageDiffResponse.Change
+	15, // 12: pb.ImageDiffResponse.annotations:type_name -> pb.ImageDiffResponse.Change
+	0,  // 13: pb.ImageListResponse.Image.latest:type_name -> pb.Manifest
+	0,  // 14: pb.ImageInspectResponse.Image.manifests:type_name -> pb.Manifest
+	0,  // 15: pb.ImagePruneResponse.Image.manifests:type_name -> pb.Manifest
+	1,  // 16: pb.ImageService.ImageList:input_type -> pb.ImageListRequest
+	3,  // 17: pb.ImageService.ImageInspect:input_type -> pb.ImageInspectRequest
+	5,  // 18: pb.ImageService.ImageRemove:input_type -> pb.ImageRemoveRequest
+	7,  // 19: pb.ImageService.ImagePrune:input_type -> pb.ImagePruneRequest
+	9,  // 20: pb.ImageService.ImageDiff:input_type -> pb.ImageDiffRequest
+	2,  // 21: pb.ImageService.ImageList:output_type -> pb.ImageListResponse
+	4,  // 22: pb.ImageService.ImageInspect:output_type -> pb.ImageInspectResponse
+	6,  // 23: pb.ImageService.ImageRemove:output_type -> pb.ImageRemoveResponse
+	8,  // 24: pb.ImageService.ImagePrune:output_type -> pb.ImagePruneResponse
+	10, // 25: pb.ImageService.ImageDiff:output_type -> pb.ImageDiffResponse
+	21, // [21:26] is the sub-list for method output_type
+	16, // [16:21] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_image_proto_init() }
@@ -744,7 +1007,7 @@ func file_image_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_image_proto_rawDesc), len(file_image_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
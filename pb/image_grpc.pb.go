@@ -23,6 +23,7 @@ const (
 	ImageService_ImageInspect_FullMethodName = "/pb.ImageService/ImageInspect"
 	ImageService_ImageRemove_FullMethodName  = "/pb.ImageService/ImageRemove"
 	ImageService_ImagePrune_FullMethodName   = "/pb.ImageService/ImagePrune"
+	ImageService_ImageDiff_FullMethodName    = "/pb.ImageService/ImageDiff"
 )
 
 // ImageServiceClient is the client API for ImageService service.
@@ -33,6 +34,7 @@ type ImageServiceClient interface {
 	ImageInspect(ctx context.Context, in *ImageInspectRequest, opts ...grpc.CallOption) (*ImageInspectResponse, error)
 	ImageRemove(ctx context.Context, in *ImageRemoveRequest, opts ...grpc.CallOption) (*ImageRemoveResponse, error)
 	ImagePrune(ctx context.Context, in *ImagePruneRequest, opts ...grpc.CallOption) (*ImagePruneResponse, error)
+	ImageDiff(ctx context.Context, in *ImageDiffRequest, opts ...grpc.CallOption) (*ImageDiffResponse, error)
 }
 
 type imageServiceClient struct {
@@ -83,6 +85,16 @@ func (c *imageServiceClient) ImagePrune(ctx context.Context, in *ImagePruneReque
 	return out, nil
 }
 
+func (c *imageServiceClient) ImageDiff(ctx context.Context, in *ImageDiffRequest, opts ...grpc.CallOption) (*ImageDiffResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImageDiffResponse)
+	err := c.cc.Invoke(ctx, ImageService_ImageDiff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ImageServiceServer is the server API for ImageService service.
 // All implementations must embed UnimplementedImageServiceServer
 // for forward compatibility.
@@ -91,6 +103,7 @@ type ImageServiceServer interface {
 	ImageInspect(context.Context, *ImageInspectRequest) (*ImageInspectResponse, error)
 	ImageRemove(context.Context, *ImageRemoveRequest) (*ImageRemoveResponse, error)
 	ImagePrune(context.Context, *ImagePruneRequest) (*ImagePruneResponse, error)
+	ImageDiff(context.Context, *ImageDiffRequest) (*ImageDiffResponse, error)
 	mustEmbedUnimplementedImageServiceServer()
 }
 
@@ -113,6 +126,9 @@ func (UnimplementedImageServiceServer) ImageRemove(context.Context, *ImageRemove
 func (UnimplementedImageServiceServer) ImagePrune(context.Context, *ImagePruneRequest) (*ImagePruneResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ImagePrune not implemented")
 }
+func (UnimplementedImageServiceServer) ImageDiff(context.Context, *ImageDiffRequest) (*ImageDiffResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImageDiff not implemented")
+}
 func (UnimplementedImageServiceServer) mustEmbedUnimplementedImageServiceServer() {}
 func (UnimplementedImageServiceServer) testEmbeddedByValue()                      {}
 
@@ -206,6 +222,24 @@ func _ImageService_ImagePrune_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ImageService_ImageDiff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImageDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImageServiceServer).ImageDiff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImageService_ImageDiff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImageServiceServer).ImageDiff(ctx, req.(*ImageDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ImageService_ServiceDesc is the grpc.ServiceDesc for ImageService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +263,10 @@ var ImageService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ImagePrune",
 			Handler:    _ImageService_ImagePrune_Handler,
 		},
+		{
+			MethodName: "ImageDiff",
+			Handler:    _ImageService_ImageDiff_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "image.proto",
@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// Artifact types of well-known OCI artifacts
+const (
+	HelmChartArtifactType = "application/vnd.cncf.helm.config.v1+json"
+	FluxArtifactType      = "application/vnd.cncf.flux.config.v1+json"
+	WasmArtifactType      = "application/vnd.wasm.config.v0+json"
+)
+
+// artifactManifest returns the manifest of a non-image OCI artifact. The
+// creation date is the one of the org.opencontainers.image.created
+// annotation, or unknown if missing. It returns errArtifact if the artifact
+// type is not allowed.
+func (c *Client) artifactManifest(name, tag string, dgst digest.Digest, raw []byte, mimeType string, errArtifact error) (Manifest, error) {
+	if mimeType != imgspecv1.MediaTypeImageManifest {
+		return Manifest{}, errArtifact
+	}
+
+	var m imgspecv1.Manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return Manifest{}, errors.Wrap(err, "cannot decode artifact manifest")
+	}
+	artifactType := m.ArtifactType
+	if artifactType == "" {
+		artifactType = m.Config.MediaType
+	}
+	if len(c.opts.ArtifactTypes) > 0 && !slices.Contains(c.opts.ArtifactTypes, artifactType) {
+		return Manifest{}, errArtifact
+	}
+
+	var created *time.Time
+	if v, ok := m.Annotations[imgspecv1.AnnotationCreated]; ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			created = &t
+		}
+	}
+
+	var size int64
+	layers := make([]string, 0, len(m.Layers))
+	for _, layer := range m.Layers {
+		layers = append(layers, layer.Digest.String())
+		size += layer.Size
+	}
+
+	return Manifest{
+		Name:         name,
+		Tag:          tag,
+		MIMEType:     mimeType,
+		Digest:       dgst,
+		Created:      created,
+		ArtifactType: artifactType,
+		Annotations:  m.Annotations,
+		Layers:       layers,
+		Size:         size,
+		Raw:          raw,
+	}, nil
+}
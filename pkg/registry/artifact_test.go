@@ -0,0 +1,90 @@
+package registry
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	podmanmanifest "go.podman.io/image/v5/manifest"
+)
+
+func TestManifestArtifact(t *testing.T) {
+	reg := newTestRegistry(t, "acme/charts/app")
+	chart := reg.addHelmChart(t, map[string]string{
+		imgspecv1.AnnotationCreated: "2026-05-24T00:00:00Z",
+		imgspecv1.AnnotationVersion: "1.2.0",
+	})
+	reg.addManifest("1.2.0", chart)
+
+	image, err := ParseImage(ParseImageOptions{Name: reg.imageName("1.2.0")})
+	require.NoError(t, err)
+
+	_, _, err = newTestRegistryClient(t, Options{}).Manifest(image, Manifest{})
+	_, ok := stderrors.AsType[podmanmanifest.NonImageArtifactError](err)
+	require.True(t, ok, "image mode must fail on artifacts")
+
+	manifest, updated, err := newTestRegistryClient(t, Options{Artifacts: true}).Manifest(image, Manifest{})
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, "1.2.0", manifest.Tag)
+	assert.Equal(t, chart.digest, manifest.Digest)
+	assert.Equal(t, HelmChartArtifactType, manifest.ArtifactType)
+	assert.Equal(t, "1.2.0", manifest.Annotations[imgspecv1.AnnotationVersion])
+	assert.Equal(t, time.Date(2026, 5, 24, 0, 0, 0, 0, time.UTC), *manifest.Created)
+	assert.Len(t, manifest.Layers, 1)
+	assert.Equal(t, int64(len("chart")), manifest.Size)
+	assert.Empty(t, manifest.Platform)
+
+	_, _, err = newTestRegistryClient(t, Options{Artifacts: true, ArtifactTypes: []string{FluxArtifactType}}).Manifest(image, Manifest{})
+	_, ok = stderrors.AsType[podmanmanifest.NonImageArtifactError](err)
+	assert.True(t, ok, "artifact type not allowed")
+}
+
+func TestManifestArtifactWithoutCreated(t *testing.T) {
+	reg := newTestRegistry(t, "acme/charts/app")
+	reg.addManifest("1.2.0", reg.addHelmChart(t, nil))
+
+	image, err := ParseImage(ParseImageOptions{Name: reg.imageName("1.2.0")})
+	require.NoError(t, err)
+
+	manifest, _, err := newTestRegistryClient(t, Options{Artifacts: true}).Manifest(image, Manifest{})
+	require.NoError(t, err)
+	assert.Nil(t, manifest.Created)
+}
+
+func (r *testRegistry) addHelmChart(t *testing.T, annotations map[string]string) testRegistryBlob {
+	t.Helper()
+
+	config := []byte(`{"name":"app","version":"1.2.0"}`)
+	chart := []byte("chart")
+	r.configBlobs[digest.FromBytes(config).String()] = config
+	r.configBlobs[digest.FromBytes(chart).String()] = chart
+
+	body, err := json.Marshal(imgspecv1.Manifest{
+		Versioned: specsVersioned,
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config: imgspecv1.Descriptor{
+			MediaType: HelmChartArtifactType,
+			Digest:    digest.FromBytes(config),
+			Size:      int64(len(config)),
+		},
+		Layers: []imgspecv1.Descriptor{{
+			MediaType: "application/vnd.cncf.helm.chart.content.v1.tar+gzip",
+			Digest:    digest.FromBytes(chart),
+			Size:      int64(len(chart)),
+		}},
+		Annotations: annotations,
+	})
+	require.NoError(t, err)
+
+	return testRegistryBlob{
+		mediaType: imgspecv1.MediaTypeImageManifest,
+		body:      body,
+		digest:    digest.FromBytes(body),
+	}
+}
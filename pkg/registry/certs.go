@@ -0,0 +1,82 @@
+package registry
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/pkg/tlsclientconfig"
+)
+
+// NewCertDir lays out CA certificates and a client certificate in a new
+// temporary directory the way containers/image expects them so it can be
+// used as Options.CertDir. Files are symlinked so renewed certificates are
+// picked up, or copied if symlinks are not supported.
+func NewCertDir(caCertFiles []string, clientCert, clientKey string) (string, error) {
+	if (clientCert == "") != (clientKey == "") {
+		return "", errors.New("client certificate and key must be set together")
+	}
+
+	dir, err := os.MkdirTemp("", "diun-certs-")
+	if err != nil {
+		return "", errors.Wrap(err, "cannot create certificates directory")
+	}
+
+	files := make(map[string]string)
+	for i, caCertFile := range caCertFiles {
+		files[fmt.Sprintf("ca-%d.crt", i)] = caCertFile
+	}
+	if clientCert != "" {
+		files["client.cert"] = clientCert
+		files["client.key"] = clientKey
+	}
+	for name, src := range files {
+		if err := linkFile(src, filepath.Join(dir, name)); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", err
+		}
+	}
+
+	if err := tlsclientconfig.SetupCertificates(dir, &tls.Config{}); err != nil { //nolint:gosec // only used to check certificates
+		_ = os.RemoveAll(dir)
+		return "", errors.Wrap(err, "invalid certificates")
+	}
+
+	return dir, nil
+}
+
+func linkFile(src, dst string) error {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	if err := os.Symlink(src, dst); err == nil {
+		return nil
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, b, 0o600)
+}
+
+// transport returns an HTTP transport honoring the TLS and proxy options of
+// the client
+func (c *Client) transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: c.opts.InsecureTLS, //nolint:gosec // user-defined
+	}
+	if c.opts.CertDir != "" {
+		if err := tlsclientconfig.SetupCertificates(c.opts.CertDir, transport.TLSClientConfig); err != nil {
+			return nil, err
+		}
+	}
+	if c.sysCtx.DockerProxyURL != nil {
+		transport.Proxy = http.ProxyURL(c.sysCtx.DockerProxyURL)
+	}
+	return transport, nil
+}
@@ -0,0 +1,155 @@
+package registry
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCerts struct {
+	pool       *x509.CertPool
+	caCert     string
+	clientCert string
+	clientKey  string
+}
+
+func newTestCerts(t *testing.T) testCerts {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "diun test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTpl, caTpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	clientDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "diun"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, &clientKey.PublicKey, caKey)
+	require.NoError(t, err)
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+
+	certs := testCerts{
+		pool:       x509.NewCertPool(),
+		caCert:     filepath.Join(dir, "ca.pem"),
+		clientCert: filepath.Join(dir, "client.pem"),
+		clientKey:  filepath.Join(dir, "client-key.pem"),
+	}
+	certs.pool.AddCert(ca)
+	require.NoError(t, os.WriteFile(certs.caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0o600))
+	require.NoError(t, os.WriteFile(certs.clientCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER}), 0o600))
+	require.NoError(t, os.WriteFile(certs.clientKey, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: clientKeyDER}), 0o600))
+
+	return certs
+}
+
+func TestNewCertDir(t *testing.T) {
+	certs := newTestCerts(t)
+
+	dir, err := NewCertDir([]string{certs.caCert}, certs.clientCert, certs.clientKey)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"ca-0.crt", "client.cert", "client.key"}, names)
+
+	_, err = NewCertDir(nil, certs.clientCert, "")
+	require.EqualError(t, err, "client certificate and key must be set together")
+
+	_, err = NewCertDir(nil, certs.clientCert, certs.caCert)
+	require.Error(t, err)
+}
+
+func TestClientCertificate(t *testing.T) {
+	certs := newTestCerts(t)
+
+	registry := newTestRegistry(t, "acme/diun")
+	registry.addTagsPage("", []string{"latest", "1.0.0"}, "")
+	registry.server.Close()
+	registry.server = httptest.NewUnstartedServer(http.HandlerFunc(registry.handle))
+	registry.server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  certs.pool,
+	}
+	registry.server.StartTLS()
+	t.Cleanup(registry.server.Close)
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: registry.imageName("latest"),
+	})
+	require.NoError(t, err)
+
+	_, err = newTestRegistryClient(t, Options{}).Tags(TagsOptions{Image: image})
+	require.Error(t, err)
+
+	certDir, err := NewCertDir(nil, certs.clientCert, certs.clientKey)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(certDir) })
+
+	client := newTestRegistryClient(t, Options{CertDir: certDir})
+	tags, err := client.Tags(TagsOptions{Image: image})
+	require.NoError(t, err)
+	assert.Equal(t, 2, tags.Total)
+
+	hc, err := client.httpClient()
+	require.NoError(t, err)
+	resp, err := hc.Get(registry.server.URL + "/v2/acme/diun/tags/list")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestProxy(t *testing.T) {
+	_, err := New(Options{Proxy: "proxy.example.com:3128"})
+	require.Error(t, err)
+
+	client, err := New(Options{Proxy: "http://proxy.example.com:3128"})
+	require.NoError(t, err)
+	require.NotNil(t, client.sysCtx.DockerProxyURL)
+	assert.Equal(t, "http://proxy.example.com:3128", client.sysCtx.DockerProxyURL.String())
+
+	transport, err := client.transport()
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, "https://registry.example.com/v2/", nil)
+	require.NoError(t, err)
+	proxyURL, err := transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String())
+}
@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// OCI annotations highlighted in manifest diffs
+const (
+	LabelVersion  = "org.opencontainers.image.version"
+	LabelRevision = "org.opencontainers.image.revision"
+)
+
+// ManifestDiff holds the changes between two manifests of an image
+type ManifestDiff struct {
+	Digest        *ValueChange  `json:"digest,omitempty"`
+	LayersAdded   []string      `json:"layers_added,omitempty"`
+	LayersRemoved []string      `json:"layers_removed,omitempty"`
+	SizeDelta     int64         `json:"size_delta,omitempty"`
+	Version       *ValueChange  `json:"version,omitempty"`
+	Revision      *ValueChange  `json:"revision,omitempty"`
+	Labels        []ValueChange `json:"labels,omitempty"`
+	Annotations   []ValueChange `json:"annotations,omitempty"`
+	Env           []ValueChange `json:"env,omitempty"`
+	Entrypoint    *ValueChange  `json:"entrypoint,omitempty"`
+	Cmd           *ValueChange  `json:"cmd,omitempty"`
+}
+
+// ValueChange holds the previous and new value of a named attribute. From is
+// empty if the attribute has been added and To if it has been removed.
+type ValueChange struct {
+	Name string `json:"name,omitempty"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Empty checks if the diff holds no change
+func (d ManifestDiff) Empty() bool {
+	return len(d.LayersAdded) == 0 && len(d.LayersRemoved) == 0 && d.SizeDelta == 0 &&
+		len(d.Labels) == 0 && len(d.Annotations) == 0 && len(d.Env) == 0 && d.Entrypoint == nil && d.Cmd == nil
+}
+
+// DiffManifests computes the changes between two manifests of an image. The
+// size delta and config changes are only computed if both manifests hold
+// them, which is not the case of manifests stored by older releases.
+func DiffManifests(oldManifest, newManifest Manifest) ManifestDiff {
+	var diff ManifestDiff
+	if oldManifest.Digest != newManifest.Digest {
+		diff.Digest = &ValueChange{
+			Name: "digest",
+			From: oldManifest.Digest.String(),
+			To:   newManifest.Digest.String(),
+		}
+	}
+	for _, layer := range newManifest.Layers {
+		if !slices.Contains(oldManifest.Layers, layer) {
+			diff.LayersAdded = append(diff.LayersAdded, layer)
+		}
+	}
+	for _, layer := range oldManifest.Layers {
+		if !slices.Contains(newManifest.Layers, layer) {
+			diff.LayersRemoved = append(diff.LayersRemoved, layer)
+		}
+	}
+	if oldManifest.Size > 0 && newManifest.Size > 0 {
+		diff.SizeDelta = newManifest.Size - oldManifest.Size
+	}
+
+	diff.Labels = diffMaps(oldManifest.Labels, newManifest.Labels)
+	diff.Annotations = diffMaps(oldManifest.Annotations, newManifest.Annotations)
+	for _, changes := range [][]ValueChange{diff.Annotations, diff.Labels} {
+		for i, change := range changes {
+			switch change.Name {
+			case LabelVersion:
+				diff.Version = &changes[i]
+			case LabelRevision:
+				diff.Revision = &changes[i]
+			}
+		}
+	}
+
+	if oldManifest.Config != nil && newManifest.Config != nil {
+		diff.Env = diffMaps(envMap(oldManifest.Config.Env), envMap(newManifest.Config.Env))
+		diff.Entrypoint = diffCommand("entrypoint", oldManifest.Config.Entrypoint, newManifest.Config.Entrypoint)
+		diff.Cmd = diffCommand("cmd", oldManifest.Config.Cmd, newManifest.Config.Cmd)
+	}
+
+	return diff
+}
+
+// diffMaps returns the changed keys between two maps sorted by name
+func diffMaps(oldValues, newValues map[string]string) []ValueChange {
+	var changes []ValueChange
+	for name, value := range newValues {
+		if oldValue, ok := oldValues[name]; !ok || oldValue != value {
+			changes = append(changes, ValueChange{Name: name, From: oldValue, To: value})
+		}
+	}
+	for name, value := range oldValues {
+		if _, ok := newValues[name]; !ok {
+			changes = append(changes, ValueChange{Name: name, From: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+func diffCommand(name string, oldCmd, newCmd []string) *ValueChange {
+	if slices.Equal(oldCmd, newCmd) {
+		return nil
+	}
+	return &ValueChange{
+		Name: name,
+		From: strings.Join(oldCmd, " "),
+		To:   strings.Join(newCmd, " "),
+	}
+}
+
+// envMap converts a list of KEY=value environment variables to a map
+func envMap(env []string) map[string]string {
+	m := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		m[k] = v
+	}
+	return m
+}
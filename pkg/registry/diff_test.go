@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffManifests(t *testing.T) {
+	oldManifest := Manifest{
+		Digest: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Labels: map[string]string{
+			LabelVersion:                     "1.0.0",
+			LabelRevision:                    "0123456",
+			"org.opencontainers.image.title": "diun",
+			"maintainer":                     "crazy-max",
+		},
+		Layers: []string{"sha256:base", "sha256:app-1.0.0"},
+		Size:   1000,
+		Config: &ManifestConfig{
+			Env:        []string{"PATH=/usr/bin", "APP_VERSION=1.0.0", "DEBUG=false"},
+			Entrypoint: []string{"/entrypoint.sh"},
+			Cmd:        []string{"serve"},
+		},
+	}
+	newManifest := Manifest{
+		Digest: "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		Labels: map[string]string{
+			LabelVersion:                     "1.1.0",
+			LabelRevision:                    "89abcde",
+			"org.opencontainers.image.title": "diun",
+		},
+		Layers: []string{"sha256:base", "sha256:app-1.1.0", "sha256:assets"},
+		Size:   1500,
+		Config: &ManifestConfig{
+			Env:        []string{"PATH=/usr/bin", "APP_VERSION=1.1.0", "TZ=UTC"},
+			Entrypoint: []string{"/usr/local/bin/app", "--config", "/etc/app.yml"},
+			Cmd:        []string{"serve"},
+		},
+	}
+
+	diff := DiffManifests(oldManifest, newManifest)
+	assert.Equal(t, &ValueChange{
+		Name: "digest",
+		From: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		To:   "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+	}, diff.Digest)
+	assert.Equal(t, []string{"sha256:app-1.1.0", "sha256:assets"}, diff.LayersAdded)
+	assert.Equal(t, []string{"sha256:app-1.0.0"}, diff.LayersRemoved)
+	assert.Equal(t, int64(500), diff.SizeDelta)
+	assert.Equal(t, &ValueChange{Name: LabelVersion, From: "1.0.0", To: "1.1.0"}, diff.Version)
+	assert.Equal(t, &ValueChange{Name: LabelRevision, From: "0123456", To: "89abcde"}, diff.Revision)
+	assert.Equal(t, []ValueChange{
+		{Name: "maintainer", From: "crazy-max"},
+		{Name: LabelRevision, From: "0123456", To: "89abcde"},
+		{Name: LabelVersion, From: "1.0.0", To: "1.1.0"},
+	}, diff.Labels)
+	assert.Equal(t, []ValueChange{
+		{Name: "APP_VERSION", From: "1.0.0", To: "1.1.0"},
+		{Name: "DEBUG", From: "false"},
+		{Name: "TZ", To: "UTC"},
+	}, diff.Env)
+	assert.Equal(t, &ValueChange{Name: "entrypoint", From: "/entrypoint.sh", To: "/usr/local/bin/app --config /etc/app.yml"}, diff.Entrypoint)
+	assert.Nil(t, diff.Cmd)
+	assert.False(t, diff.Empty())
+
+	assert.True(t, DiffManifests(newManifest, newManifest).Empty())
+}
+
+func TestDiffManifestsWithoutConfig(t *testing.T) {
+	diff := DiffManifests(Manifest{
+		Layers: []string{"sha256:a"},
+	}, Manifest{
+		Layers: []string{"sha256:b"},
+		Size:   1500,
+		Config: &ManifestConfig{Env: []string{"TZ=UTC"}},
+	})
+	assert.Equal(t, []string{"sha256:b"}, diff.LayersAdded)
+	assert.Equal(t, []string{"sha256:a"}, diff.LayersRemoved)
+	assert.Zero(t, diff.SizeDelta)
+	assert.Nil(t, diff.Env)
+	assert.Nil(t, diff.Entrypoint)
+}
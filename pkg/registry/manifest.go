@@ -1,6 +1,7 @@
 package registry
 
 import (
+	stderrors "errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"go.podman.io/image/v5/docker"
 	"go.podman.io/image/v5/manifest"
+	"go.podman.io/image/v5/types"
 )
 
 // Manifest is the Docker image manifest information
@@ -20,13 +22,37 @@ type Manifest struct {
 	Created       *time.Time
 	DockerVersion string
 	Labels        map[string]string
+	ArtifactType  string
+	Annotations   map[string]string
 	Layers        []string
+	Size          int64
+	Config        *ManifestConfig
 	Platform      string
 	Raw           []byte
 }
 
+// ManifestConfig holds the runtime configuration of an image
+type ManifestConfig struct {
+	Env        []string
+	Entrypoint []string
+	Cmd        []string
+}
+
 // Manifest returns the manifest for a specific image
-func (c *Client) Manifest(image Image, dbManifest Manifest) (Manifest, bool, error) {
+func (c *Client) Manifest(image Image, dbManifest Manifest) (manifest Manifest, updated bool, err error) {
+	err = c.withMirrors(image, func(client *Client, img Image) error {
+		return client.do(func() error {
+			manifest, updated, err = client.manifest(img, dbManifest)
+			if err == nil && client != c && manifest.Name == img.Name() {
+				manifest.Name = image.Name()
+			}
+			return err
+		})
+	})
+	return manifest, updated, err
+}
+
+func (c *Client) manifest(image Image, dbManifest Manifest) (Manifest, bool, error) {
 	ctx, cancel := c.timeoutContext()
 	defer cancel()
 
@@ -82,12 +108,20 @@ func (c *Client) Manifest(image Image, dbManifest Manifest) (Manifest, bool, err
 	// Metadata describing the Docker image
 	rmInspect, err := rmCloser.Inspect(ctx)
 	if err != nil {
+		if _, ok := stderrors.AsType[manifest.NonImageArtifactError](err); ok && c.opts.Artifacts {
+			rmArtifact, err := c.artifactManifest(rmCloser.Reference().DockerReference().Name(), image.Tag, rmDigest, rmRawManifest, rmManifestMimeType, err)
+			return rmArtifact, updated, err
+		}
 		return Manifest{}, false, errors.Wrap(err, "cannot inspect")
 	}
 	rmTag := rmInspect.Tag
 	if len(rmTag) == 0 {
 		rmTag = image.Tag
 	}
+	rmConfig, err := rmCloser.OCIConfig(ctx)
+	if err != nil {
+		return Manifest{}, false, errors.Wrap(err, "cannot get image config")
+	}
 	rmPlatform := fmt.Sprintf("%s/%s", rmInspect.Os, rmInspect.Architecture)
 	if rmInspect.Variant != "" {
 		rmPlatform = fmt.Sprintf("%s/%s", rmPlatform, rmInspect.Variant)
@@ -102,11 +136,29 @@ func (c *Client) Manifest(image Image, dbManifest Manifest) (Manifest, bool, err
 		DockerVersion: rmInspect.DockerVersion,
 		Labels:        rmInspect.Labels,
 		Layers:        rmInspect.Layers,
-		Platform:      rmPlatform,
-		Raw:           rmRawManifest,
+		Size:          layersSize(rmInspect.LayersData),
+		Config: &ManifestConfig{
+			Env:        rmConfig.Config.Env,
+			Entrypoint: rmConfig.Config.Entrypoint,
+			Cmd:        rmConfig.Config.Cmd,
+		},
+		Platform: rmPlatform,
+		Raw:      rmRawManifest,
 	}, updated, nil
 }
 
+// layersSize returns the compressed size of the layers, 0 if unknown
+func layersSize(layers []types.ImageInspectLayer) int64 {
+	var size int64
+	for _, layer := range layers {
+		if layer.Size < 0 {
+			return 0
+		}
+		size += layer.Size
+	}
+	return size
+}
+
 func (m Manifest) isManifestList() bool {
 	return isManifestList(m.MIMEType)
 }
@@ -75,6 +75,8 @@ func TestManifest(t *testing.T) {
 		"org.opencontainers.image.title": "diun",
 	}, manifest.Labels)
 	assert.Equal(t, []string{remoteImage.layer.String()}, manifest.Layers)
+	assert.Equal(t, int64(42), manifest.Size)
+	assert.Equal(t, &ManifestConfig{}, manifest.Config)
 	assert.Equal(t, "linux/amd64", manifest.Platform)
 	assert.NotEmpty(t, manifest.Raw)
 }
@@ -0,0 +1,60 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/types"
+)
+
+// withMirrors runs fn against the registry mirrors first and falls back to
+// the upstream registry if they all fail. Requests against a mirror are not
+// retried and do not use the credentials of the upstream registry.
+func (c *Client) withMirrors(image Image, fn func(client *Client, image Image) error) error {
+	for _, mirror := range c.opts.Mirrors {
+		mirrored, err := image.mirror(mirror)
+		if err == nil {
+			if err = fn(c.mirrorClient(), mirrored); err == nil {
+				return nil
+			}
+		}
+		if c.opts.OnMirrorError != nil {
+			c.opts.OnMirrorError(mirror, err)
+		}
+	}
+	return fn(c, image)
+}
+
+func (c *Client) mirrorClient() *Client {
+	sysCtx := *c.sysCtx
+	sysCtx.DockerAuthConfig = nil
+
+	opts := c.opts
+	opts.Auth = types.DockerAuthConfig{}
+	opts.Mirrors = nil
+	opts.Limiter = nil
+	opts.Retry = RetryOptions{}
+
+	return &Client{
+		opts:   opts,
+		sysCtx: &sysCtx,
+	}
+}
+
+// mirror returns the image as served by a registry mirror such as
+// "mirror.example.com" or "mirror.example.com/dockerhub"
+func (i Image) mirror(mirror string) (Image, error) {
+	name := fmt.Sprintf("%s/%s", strings.TrimSuffix(mirror, "/"), i.Path)
+	if i.Tag != "" {
+		name = fmt.Sprintf("%s:%s", name, i.Tag)
+	}
+	if i.Digest != "" {
+		name = fmt.Sprintf("%s@%s", name, i.Digest)
+	}
+	image, err := ParseImage(ParseImageOptions{Name: name})
+	if err != nil {
+		return Image{}, errors.Wrapf(err, "invalid mirror %s", mirror)
+	}
+	return image, nil
+}
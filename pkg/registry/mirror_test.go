@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"testing"
+
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageMirror(t *testing.T) {
+	image, err := ParseImage(ParseImageOptions{Name: "alpine:3.20"})
+	require.NoError(t, err)
+
+	mirrored, err := image.mirror("mirror.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "mirror.example.com/library/alpine:3.20", mirrored.String())
+
+	mirrored, err = image.mirror("mirror.example.com/dockerhub/")
+	require.NoError(t, err)
+	assert.Equal(t, "mirror.example.com/dockerhub/library/alpine:3.20", mirrored.String())
+
+	_, err = image.mirror("Mirror Example")
+	require.Error(t, err)
+}
+
+func TestManifestMirror(t *testing.T) {
+	upstream := newTestRegistry(t, "acme/diun")
+	mirror := newTestRegistry(t, "acme/diun")
+	remoteImage := newTestRegistryImage(t, imgspecv1.Platform{
+		Architecture: "amd64",
+		OS:           "linux",
+	}, "25.0.0", nil)
+	upstream.addImage("1.0.0", remoteImage)
+	mirror.addImage("1.0.0", remoteImage)
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: upstream.imageName("1.0.0"),
+	})
+	require.NoError(t, err)
+
+	var mirrorErrs []string
+	client := newTestRegistryClient(t, Options{
+		Mirrors: []string{"Mirror Example", mirror.host()},
+		OnMirrorError: func(mirror string, err error) {
+			mirrorErrs = append(mirrorErrs, mirror)
+		},
+	})
+
+	manifest, _, err := client.Manifest(image, Manifest{})
+	require.NoError(t, err)
+	assert.Equal(t, upstream.host()+"/acme/diun", manifest.Name)
+	assert.Equal(t, remoteImage.manifest.digest, manifest.Digest)
+	assert.Equal(t, []string{"Mirror Example"}, mirrorErrs)
+	assert.Positive(t, mirror.requestCount("GET", "/v2/acme/diun/manifests/1.0.0"))
+	assert.Zero(t, upstream.requestCount("GET", "/v2/acme/diun/manifests/1.0.0"))
+	assert.Zero(t, upstream.requestCount("HEAD", "/v2/acme/diun/manifests/1.0.0"))
+}
+
+func TestManifestMirrorFallback(t *testing.T) {
+	upstream := newTestRegistry(t, "acme/diun")
+	mirror := newTestRegistry(t, "acme/diun")
+	remoteImage := newTestRegistryImage(t, imgspecv1.Platform{
+		Architecture: "amd64",
+		OS:           "linux",
+	}, "25.0.0", nil)
+	upstream.addImage("1.0.0", remoteImage)
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: upstream.imageName("1.0.0"),
+	})
+	require.NoError(t, err)
+
+	var mirrorErrs []string
+	client := newTestRegistryClient(t, Options{
+		Mirrors: []string{mirror.host()},
+		OnMirrorError: func(mirror string, err error) {
+			mirrorErrs = append(mirrorErrs, mirror)
+		},
+	})
+
+	manifest, _, err := client.Manifest(image, Manifest{})
+	require.NoError(t, err)
+	assert.Equal(t, upstream.host()+"/acme/diun", manifest.Name)
+	assert.Equal(t, remoteImage.manifest.digest, manifest.Digest)
+	assert.Equal(t, []string{mirror.host()}, mirrorErrs)
+}
+
+func TestTagsMirror(t *testing.T) {
+	upstream := newTestRegistry(t, "acme/diun")
+	upstream.addTagsPage("", []string{"1.0.0"}, "")
+	mirror := newTestRegistry(t, "acme/diun")
+	mirror.addTagsPage("", []string{"1.0.0", "1.1.0"}, "")
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: upstream.imageName("1.0.0"),
+	})
+	require.NoError(t, err)
+
+	client := newTestRegistryClient(t, Options{
+		Mirrors: []string{mirror.host()},
+	})
+	tags, err := client.Tags(TagsOptions{Image: image})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "1.1.0"}, tags.List)
+}
@@ -0,0 +1,227 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/manifest"
+)
+
+// RateLimit holds the pull rate limit reported by a registry
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Window    time.Duration
+	Source    string
+}
+
+// Exhausted checks if the remaining budget is at or below the threshold
+func (r *RateLimit) Exhausted(threshold int) bool {
+	return r.Remaining <= threshold
+}
+
+// RateLimit returns the pull rate limit of the registry hosting the image.
+// It relies on a HEAD request against the manifest which does not count
+// toward the limit on Docker Hub. A nil value is returned if the registry
+// does not report any rate limit.
+func (c *Client) RateLimit(image Image) (*RateLimit, error) {
+	ctx, cancel := c.timeoutContext()
+	defer cancel()
+
+	hc, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registryHost(image.Domain), image.Path, image.Reference())
+	resp, err := c.headManifest(ctx, hc, manifestURL, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := c.authorize(ctx, hc, resp.Header.Get("WWW-Authenticate"), image)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot authenticate against registry")
+		}
+		if resp, err = c.headManifest(ctx, hc, manifestURL, authorization); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusTooManyRequests {
+		return nil, errors.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	return parseRateLimit(resp.Header), nil
+}
+
+func (c *Client) headManifest(ctx context.Context, hc *http.Client, manifestURL string, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifest.DefaultRequestedManifestMIMETypes, ", "))
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, resp.Body.Close()
+}
+
+// authorize returns the Authorization header value answering a registry challenge
+func (c *Client) authorize(ctx context.Context, hc *http.Client, challenge string, image Image) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if c.opts.Auth.Username == "" {
+			return "", errors.New("basic authentication required but no credentials provided")
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(c.opts.Auth.Username, c.opts.Auth.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+		realm, err := url.Parse(params["realm"])
+		if err != nil || realm.Host == "" {
+			return "", errors.Errorf("invalid bearer realm %q", params["realm"])
+		}
+		query := realm.Query()
+		if service, ok := params["service"]; ok {
+			query.Set("service", service)
+		}
+		scope := params["scope"]
+		if scope == "" {
+			scope = fmt.Sprintf("repository:%s:pull", image.Path)
+		}
+		query.Set("scope", scope)
+		realm.RawQuery = query.Encode()
+
+		var req *http.Request
+		if c.opts.Auth.IdentityToken != "" {
+			// OAuth2 refresh token grant of the token authentication spec
+			form := url.Values{
+				"grant_type":    {"refresh_token"},
+				"refresh_token": {c.opts.Auth.IdentityToken},
+				"client_id":     {"diun"},
+				"scope":         {scope},
+			}
+			if service, ok := params["service"]; ok {
+				form.Set("service", service)
+			}
+			realm.RawQuery = ""
+			if req, err = http.NewRequestWithContext(ctx, http.MethodPost, realm.String(), strings.NewReader(form.Encode())); err != nil {
+				return "", err
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		} else {
+			if req, err = http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil); err != nil {
+				return "", err
+			}
+			if c.opts.Auth.Username != "" {
+				req.SetBasicAuth(c.opts.Auth.Username, c.opts.Auth.Password)
+			}
+		}
+		req.Header.Set("User-Agent", c.opts.UserAgent)
+		resp, err := hc.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", errors.Errorf("unexpected HTTP status %d from token endpoint", resp.StatusCode)
+		}
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			return "", errors.Wrap(err, "cannot decode token response")
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		return "Bearer " + token.Token, nil
+	default:
+		return "", errors.Errorf("unsupported authentication challenge %q", challenge)
+	}
+}
+
+// parseChallenge parses a WWW-Authenticate header value
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, ", "), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			params[key] = value
+		}
+	}
+	return strings.ToLower(scheme), params
+}
+
+// parseRateLimit parses ratelimit headers such as "ratelimit-remaining: 76;w=21600"
+func parseRateLimit(header http.Header) *RateLimit {
+	limit, window, ok := parseRateLimitHeader(header.Get("ratelimit-limit"))
+	if !ok {
+		return nil
+	}
+	remaining, _, ok := parseRateLimitHeader(header.Get("ratelimit-remaining"))
+	if !ok {
+		return nil
+	}
+	return &RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Window:    window,
+		Source:    header.Get("docker-ratelimit-source"),
+	}
+}
+
+func parseRateLimitHeader(value string) (int, time.Duration, bool) {
+	if value == "" {
+		return 0, 0, false
+	}
+	parts := strings.Split(value, ";")
+	count, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, false
+	}
+	var window time.Duration
+	for _, part := range parts[1:] {
+		if w, ok := strings.CutPrefix(strings.TrimSpace(part), "w="); ok {
+			if seconds, err := strconv.Atoi(w); err == nil {
+				window = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return count, window, true
+}
+
+// IsTooManyRequests checks if an error is due to the registry rate limit
+func IsTooManyRequests(err error) bool {
+	status, ok := httpStatus(err)
+	return ok && status == http.StatusTooManyRequests
+}
+
+// registryHost returns the host serving the registry API for a domain
+func registryHost(domain string) string {
+	if domain == "docker.io" {
+		return "registry-1.docker.io"
+	}
+	return domain
+}
@@ -0,0 +1,99 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/docker"
+	"go.podman.io/image/v5/types"
+)
+
+func TestRateLimit(t *testing.T) {
+	var gotBasicUser, gotScope, gotService string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			gotBasicUser, _, _ = r.BasicAuth()
+			gotScope = r.URL.Query().Get("scope")
+			gotService = r.URL.Query().Get("service")
+			_, _ = w.Write([]byte(`{"token":"s3cr3t"}`))
+		case "/v2/library/alpine/manifests/latest":
+			require.Equal(t, http.MethodHead, r.Method)
+			if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="registry.test",scope="repository:library/alpine:pull"`, r.Host))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("ratelimit-limit", "100;w=21600")
+			w.Header().Set("ratelimit-remaining", "76;w=21600")
+			w.Header().Set("docker-ratelimit-source", "203.0.113.7")
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: strings.TrimPrefix(ts.URL, "https://") + "/library/alpine:latest",
+	})
+	require.NoError(t, err)
+
+	client := newTestRegistryClient(t, Options{
+		Auth: types.DockerAuthConfig{Username: "foo", Password: "bar"},
+	})
+	rl, err := client.RateLimit(image)
+	require.NoError(t, err)
+	require.NotNil(t, rl)
+
+	assert.Equal(t, &RateLimit{
+		Limit:     100,
+		Remaining: 76,
+		Window:    6 * time.Hour,
+		Source:    "203.0.113.7",
+	}, rl)
+	assert.False(t, rl.Exhausted(10))
+	assert.True(t, rl.Exhausted(76))
+	assert.Equal(t, "foo", gotBasicUser)
+	assert.Equal(t, "repository:library/alpine:pull", gotScope)
+	assert.Equal(t, "registry.test", gotService)
+}
+
+func TestRateLimitNotReported(t *testing.T) {
+	registry := newTestRegistry(t, "acme/diun")
+	registry.addManifest("1.0.0", newTestOCIArtifactManifest(t, "application/vnd.acme.test"))
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: registry.imageName("1.0.0"),
+	})
+	require.NoError(t, err)
+
+	rl, err := newTestRegistryClient(t, Options{}).RateLimit(image)
+	require.NoError(t, err)
+	assert.Nil(t, rl)
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull,push"`)
+	assert.Equal(t, "bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/alpine:pull,push",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm="registry"`)
+	assert.Equal(t, "basic", scheme)
+	assert.Equal(t, map[string]string{"realm": "registry"}, params)
+}
+
+func TestIsTooManyRequests(t *testing.T) {
+	assert.True(t, IsTooManyRequests(fmt.Errorf("cannot get image digest from HEAD request: %w", docker.ErrTooManyRequests)))
+	assert.False(t, IsTooManyRequests(fmt.Errorf("cannot get image digest from HEAD request")))
+}
@@ -0,0 +1,231 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/docker"
+	"go.podman.io/image/v5/pkg/blobinfocache/none"
+	"go.podman.io/image/v5/types"
+)
+
+const maxArtifactBlobSize = 4 << 20
+
+// artifactLayer holds a layer of an artifact manifest along with its content
+type artifactLayer struct {
+	imgspecv1.Descriptor
+	Payload []byte
+}
+
+// referrers returns the descriptors of manifests referring to a manifest
+// through the OCI referrers API, or the referrers tag schema
+// (sha256-<hex>) if the registry does not support this API. Only referrers
+// of the given artifact types are returned, all of them if none is given.
+func (c *Client) referrers(ctx context.Context, image Image, dgst digest.Digest, artifactTypes ...string) ([]imgspecv1.Descriptor, error) {
+	index, supported, err := c.referrersAPI(ctx, image, dgst, artifactTypes)
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		if index, err = c.referrersTag(ctx, image, dgst); err != nil {
+			return nil, err
+		}
+	}
+
+	var descs []imgspecv1.Descriptor
+	for _, desc := range index.Manifests {
+		if len(artifactTypes) == 0 || slices.Contains(artifactTypes, desc.ArtifactType) {
+			descs = append(descs, desc)
+		}
+	}
+	return descs, nil
+}
+
+// referrersAPI queries the OCI referrers API. It returns false if the
+// registry does not support it.
+func (c *Client) referrersAPI(ctx context.Context, image Image, dgst digest.Digest, artifactTypes []string) (imgspecv1.Index, bool, error) {
+	hc, err := c.httpClient()
+	if err != nil {
+		return imgspecv1.Index{}, false, err
+	}
+
+	referrersURL := fmt.Sprintf("https://%s/v2/%s/referrers/%s", registryHost(image.Domain), image.Path, dgst)
+	if len(artifactTypes) == 1 {
+		referrersURL += "?artifactType=" + url.QueryEscape(artifactTypes[0])
+	}
+	resp, err := c.get(ctx, hc, image, referrersURL, imgspecv1.MediaTypeImageIndex)
+	if err != nil {
+		return imgspecv1.Index{}, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusBadRequest:
+		return imgspecv1.Index{}, false, nil
+	default:
+		return imgspecv1.Index{}, false, errors.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), imgspecv1.MediaTypeImageIndex) {
+		return imgspecv1.Index{}, false, nil
+	}
+
+	var index imgspecv1.Index
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxArtifactBlobSize)).Decode(&index); err != nil {
+		return imgspecv1.Index{}, false, errors.Wrap(err, "cannot decode referrers index")
+	}
+	return index, true, nil
+}
+
+// referrersTag returns the index pushed by clients under the referrers tag
+// schema. Registries without such tag return an empty index.
+func (c *Client) referrersTag(ctx context.Context, image Image, dgst digest.Digest) (imgspecv1.Index, error) {
+	ref, err := ImageReference(fmt.Sprintf("%s:%s-%s", image.Name(), dgst.Algorithm(), dgst.Encoded()))
+	if err != nil {
+		return imgspecv1.Index{}, err
+	}
+	raw, mimeType, err := c.rawManifest(ctx, ref)
+	if err != nil {
+		if isNotFound(err) {
+			return imgspecv1.Index{}, nil
+		}
+		return imgspecv1.Index{}, errors.Wrap(err, "cannot retrieve referrers tag")
+	}
+	if mimeType != imgspecv1.MediaTypeImageIndex {
+		return imgspecv1.Index{}, nil
+	}
+
+	var index imgspecv1.Index
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return imgspecv1.Index{}, errors.Wrap(err, "cannot decode referrers tag index")
+	}
+	return index, nil
+}
+
+// rawManifest returns the raw manifest of a reference
+func (c *Client) rawManifest(ctx context.Context, ref types.ImageReference) ([]byte, string, error) {
+	src, err := ref.NewImageSource(ctx, c.sysCtx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer src.Close()
+	return src.GetManifest(ctx, nil)
+}
+
+// artifactLayers returns the layers of an artifact manifest matching one of
+// the given media types along with their verified content. Layers larger
+// than maxArtifactBlobSize are not retrieved and returned as skipped.
+func (c *Client) artifactLayers(ctx context.Context, ref types.ImageReference, mediaTypes ...string) ([]artifactLayer, []imgspecv1.Descriptor, error) {
+	src, err := ref.NewImageSource(ctx, c.sysCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer src.Close()
+
+	raw, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var manifest imgspecv1.Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, nil, errors.Wrap(err, "cannot decode artifact manifest")
+	}
+
+	var layers []artifactLayer
+	var skipped []imgspecv1.Descriptor
+	for _, layer := range manifest.Layers {
+		if !slices.Contains(mediaTypes, layer.MediaType) {
+			continue
+		}
+		if layer.Size > maxArtifactBlobSize {
+			skipped = append(skipped, layer)
+			continue
+		}
+		rc, _, err := src.GetBlob(ctx, types.BlobInfo{Digest: layer.Digest, Size: layer.Size}, none.NoCache)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot retrieve artifact layer %s", layer.Digest)
+		}
+		payload, err := io.ReadAll(io.LimitReader(rc, maxArtifactBlobSize))
+		_ = rc.Close()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot read artifact layer %s", layer.Digest)
+		}
+		if layer.Digest.Validate() != nil || layer.Digest.Algorithm().FromBytes(payload) != layer.Digest {
+			return nil, nil, errors.Errorf("artifact layer digest mismatch for %s", layer.Digest)
+		}
+		layers = append(layers, artifactLayer{
+			Descriptor: layer,
+			Payload:    payload,
+		})
+	}
+
+	return layers, skipped, nil
+}
+
+// digestReference returns the reference of a manifest by digest in the
+// repository of an image
+func digestReference(image Image, dgst digest.Digest) (types.ImageReference, error) {
+	named, err := reference.ParseNormalizedNamed(image.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse reference")
+	}
+	canonical, err := reference.WithDigest(reference.TrimNamed(named), dgst)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse reference")
+	}
+	return docker.NewReference(canonical)
+}
+
+// get sends a GET request against the registry answering the authentication
+// challenge if required
+func (c *Client) get(ctx context.Context, hc *http.Client, image Image, u string, accept string) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("Accept", accept)
+	return c.request(ctx, hc, image, u, header)
+}
+
+// request sends a GET request with the given headers to the registry,
+// answering the authentication challenge if any
+func (c *Client) request(ctx context.Context, hc *http.Client, image Image, u string, header http.Header) (*http.Response, error) {
+	var authorization string
+	return c.authorizedRequest(ctx, hc, image, u, header, &authorization)
+}
+
+// authorizedRequest sends a GET request with the given headers to the
+// registry. The authorization is reused across requests if set and renewed
+// when the registry answers with an authentication challenge.
+func (c *Client) authorizedRequest(ctx context.Context, hc *http.Client, image Image, u string, header http.Header, authorization *string) (*http.Response, error) {
+	send := func(authorization string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header = header.Clone()
+		req.Header.Set("User-Agent", c.opts.UserAgent)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return hc.Do(req)
+	}
+
+	resp, err := send(*authorization)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	_ = resp.Body.Close()
+
+	if *authorization, err = c.authorize(ctx, hc, resp.Header.Get("WWW-Authenticate"), image); err != nil {
+		return nil, errors.Wrap(err, "cannot authenticate against registry")
+	}
+	return send(*authorization)
+}
@@ -2,6 +2,8 @@ package registry
 
 import (
 	"context"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/pkg/errors"
@@ -18,22 +20,43 @@ type Client struct {
 type Options struct {
 	Auth          types.DockerAuthConfig
 	InsecureTLS   bool
+	CertDir       string
+	Proxy         string
 	Timeout       time.Duration
 	UserAgent     string
 	CompareDigest bool
 	ImageOs       string
 	ImageArch     string
 	ImageVariant  string
+	Artifacts     bool
+	ArtifactTypes []string
+	Limiter       Limiter
+	Retry         RetryOptions
+	Mirrors       []string
+	OnMirrorError func(mirror string, err error)
 }
 
 // New creates new docker registry client instance
 func New(opts Options) (*Client, error) {
+	var proxyURL *url.URL
+	if opts.Proxy != "" {
+		var err error
+		if proxyURL, err = url.Parse(opts.Proxy); err != nil {
+			return nil, errors.Wrap(err, "invalid proxy URL")
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, errors.New("proxy URL must include scheme and host")
+		}
+	}
+
 	return &Client{
 		opts: opts,
 		sysCtx: &types.SystemContext{
 			DockerAuthConfig:                  new(opts.Auth),
 			DockerDaemonInsecureSkipTLSVerify: opts.InsecureTLS,
 			DockerInsecureSkipTLSVerify:       types.NewOptionalBool(opts.InsecureTLS),
+			DockerCertPath:                    opts.CertDir,
+			DockerProxyURL:                    proxyURL,
 			DockerRegistryUserAgent:           opts.UserAgent,
 			OSChoice:                          opts.ImageOs,
 			ArchitectureChoice:                opts.ImageArch,
@@ -52,3 +75,14 @@ func (c *Client) timeoutContext() (context.Context, context.CancelFunc) {
 	}
 	return ctx, cancelFunc
 }
+
+// httpClient returns an HTTP client for requests not handled by containers/image
+func (c *Client) httpClient() (*http.Client, error) {
+	transport, err := c.transport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Transport: transport,
+	}, nil
+}
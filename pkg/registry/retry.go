@@ -0,0 +1,94 @@
+package registry
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/docker/distribution/registry/api/errcode"
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/docker"
+)
+
+// Limiter limits the number of concurrent requests against a registry. A nil
+// limiter does not enforce any limit.
+type Limiter chan struct{}
+
+// NewLimiter creates a new limiter allowing up to n concurrent requests
+func NewLimiter(n int) Limiter {
+	if n <= 0 {
+		return nil
+	}
+	return make(Limiter, n)
+}
+
+func (l Limiter) acquire() {
+	if l != nil {
+		l <- struct{}{}
+	}
+}
+
+func (l Limiter) release() {
+	if l != nil {
+		<-l
+	}
+}
+
+// RetryOptions holds retry options for registry requests
+type RetryOptions struct {
+	Attempts int
+	Backoff  time.Duration
+	Statuses []int
+	OnRetry  func(attempt int, delay time.Duration, err error)
+}
+
+// do runs fn within the concurrency limit of the registry and retries it
+// with an exponential backoff on timeouts and retryable HTTP statuses.
+func (c *Client) do(fn func() error) error {
+	attempts := max(c.opts.Retry.Attempts, 1)
+	for attempt := 1; ; attempt++ {
+		c.opts.Limiter.acquire()
+		err := fn()
+		c.opts.Limiter.release()
+		if err == nil || attempt >= attempts || !c.retryable(err) {
+			return err
+		}
+		delay := c.opts.Retry.Backoff * time.Duration(1<<(attempt-1))
+		if c.opts.Retry.OnRetry != nil {
+			c.opts.Retry.OnRetry(attempt, delay, err)
+		}
+		time.Sleep(delay)
+	}
+}
+
+func (c *Client) retryable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if status, ok := httpStatus(err); ok {
+		return slices.Contains(c.opts.Retry.Statuses, status)
+	}
+	return false
+}
+
+// httpStatus returns the HTTP status code returned by the registry for an error
+func httpStatus(err error) (int, bool) {
+	var hse docker.UnexpectedHTTPStatusError
+	if errors.As(err, &hse) {
+		return hse.StatusCode, true
+	}
+	var ec errcode.Error
+	if errors.As(err, &ec) {
+		return ec.Code.Descriptor().HTTPStatusCode, true
+	}
+	if errors.Is(err, docker.ErrTooManyRequests) {
+		return http.StatusTooManyRequests, true
+	}
+	return 0, false
+}
@@ -0,0 +1,98 @@
+package registry
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/docker"
+)
+
+func TestDoRetriesRetryableStatus(t *testing.T) {
+	var retries []int
+	client := newTestRegistryClient(t, Options{
+		Retry: RetryOptions{
+			Attempts: 3,
+			Backoff:  time.Millisecond,
+			Statuses: []int{503},
+			OnRetry: func(attempt int, delay time.Duration, err error) {
+				retries = append(retries, attempt)
+			},
+		},
+	})
+
+	var calls int
+	err := client.do(func() error {
+		calls++
+		if calls < 3 {
+			return errors.Wrap(docker.UnexpectedHTTPStatusError{StatusCode: 503}, "cannot get image digest from HEAD request")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int{1, 2}, retries)
+}
+
+func TestDoStopsOnNonRetryableError(t *testing.T) {
+	client := newTestRegistryClient(t, Options{
+		Retry: RetryOptions{
+			Attempts: 3,
+			Backoff:  time.Millisecond,
+			Statuses: []int{503},
+		},
+	})
+
+	var calls int
+	err := client.do(func() error {
+		calls++
+		return docker.UnexpectedHTTPStatusError{StatusCode: 404}
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDoWithoutRetry(t *testing.T) {
+	client := newTestRegistryClient(t, Options{})
+
+	var calls int
+	err := client.do(func() error {
+		calls++
+		return docker.UnexpectedHTTPStatusError{StatusCode: 503}
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestLimiter(t *testing.T) {
+	assert.Nil(t, NewLimiter(0))
+
+	client := newTestRegistryClient(t, Options{
+		Limiter: NewLimiter(2),
+	})
+
+	var current, peak int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			_ = client.do(func() error {
+				n := atomic.AddInt32(&current, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&current, -1)
+				return nil
+			})
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), peak)
+}
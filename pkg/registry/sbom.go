@@ -0,0 +1,390 @@
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	podmanmanifest "go.podman.io/image/v5/manifest"
+	"go.podman.io/image/v5/types"
+)
+
+// SBOM and provenance media types
+const (
+	SPDXMediaType      = "application/spdx+json"
+	CycloneDXMediaType = "application/vnd.cyclonedx+json"
+	InTotoMediaType    = "application/vnd.in-toto+json"
+
+	cosignSPDXMediaType = "text/spdx+json"
+
+	buildkitReferenceTypeAnnotation   = "vnd.docker.reference.type"
+	buildkitReferenceDigestAnnotation = "vnd.docker.reference.digest"
+	buildkitAttestationManifest       = "attestation-manifest"
+)
+
+// SBOM formats
+const (
+	SBOMFormatSPDX      = "spdx"
+	SBOMFormatCycloneDX = "cyclonedx"
+)
+
+var supplyChainMediaTypes = []string{
+	SPDXMediaType,
+	cosignSPDXMediaType,
+	CycloneDXMediaType,
+	InTotoMediaType,
+	DSSEEnvelopeType,
+}
+
+// SupplyChain holds the software bill of materials and build provenance
+// attached to a manifest
+type SupplyChain struct {
+	SBOM       *SBOM       `json:"sbom,omitempty"`
+	Provenance *Provenance `json:"provenance,omitempty"`
+
+	// Skipped holds the artifact layers too large to be retrieved
+	Skipped []imgspecv1.Descriptor `json:"-"`
+}
+
+// SBOM holds the packages listed in a software bill of materials
+type SBOM struct {
+	Format   string    `json:"format"`
+	Packages []Package `json:"packages,omitempty"`
+}
+
+// Package holds a package listed in a software bill of materials
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Provenance holds the SLSA build provenance of a manifest
+type Provenance struct {
+	PredicateType string `json:"predicate_type"`
+	BuilderID     string `json:"builder_id,omitempty"`
+	BuildType     string `json:"build_type,omitempty"`
+	Source        string `json:"source,omitempty"`
+	Revision      string `json:"revision,omitempty"`
+}
+
+// PackageDiff holds the package changes between two software bills of
+// materials
+type PackageDiff struct {
+	Added   []Package       `json:"added,omitempty"`
+	Removed []Package       `json:"removed,omitempty"`
+	Updated []PackageUpdate `json:"updated,omitempty"`
+}
+
+// PackageUpdate holds a package whose version changed
+type PackageUpdate struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Empty checks if there is no package change
+func (d PackageDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Updated) == 0
+}
+
+// SupplyChain returns the SBOM and SLSA provenance attached to a manifest.
+// They are looked up through the OCI referrers API, the cosign tag scheme
+// (sha256-<hex>.sbom and .att) and BuildKit attestation manifests. For a
+// manifest list, the instance matching the platform is also looked up.
+func (c *Client) SupplyChain(image Image, manifest Manifest) (*SupplyChain, error) {
+	var res *SupplyChain
+	err := c.do(func() (err error) {
+		res, err = c.supplyChain(image, manifest)
+		return err
+	})
+	return res, err
+}
+
+func (c *Client) supplyChain(image Image, manifest Manifest) (*SupplyChain, error) {
+	ctx, cancel := c.timeoutContext()
+	defer cancel()
+
+	subjects := []digest.Digest{manifest.Digest}
+	var refs []types.ImageReference
+	if manifest.isManifestList() && len(manifest.Raw) > 0 {
+		list, err := podmanmanifest.ListFromBlob(manifest.Raw, manifest.MIMEType)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse manifest list")
+		}
+		if instance, err := list.ChooseInstance(c.sysCtx); err == nil {
+			subjects = append(subjects, instance)
+			for _, dgst := range buildkitAttestations(manifest, instance) {
+				ref, err := digestReference(image, dgst)
+				if err != nil {
+					return nil, err
+				}
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	for _, subject := range subjects {
+		for _, suffix := range []string{"sbom", "att"} {
+			ref, err := ImageReference(fmt.Sprintf("%s:%s-%s.%s", image.Name(), subject.Algorithm(), subject.Encoded(), suffix))
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, ref)
+		}
+		referrers, err := c.referrers(ctx, image, subject)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot retrieve referrers")
+		}
+		for _, referrer := range referrers {
+			if !slices.Contains(supplyChainMediaTypes, referrer.ArtifactType) {
+				continue
+			}
+			ref, err := digestReference(image, referrer.Digest)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, ref)
+		}
+	}
+
+	res := &SupplyChain{}
+	for _, ref := range refs {
+		layers, skipped, err := c.artifactLayers(ctx, ref, supplyChainMediaTypes...)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "cannot retrieve %s", ref.StringWithinTransport())
+		}
+		res.Skipped = append(res.Skipped, skipped...)
+		for _, layer := range layers {
+			res.add(layer)
+		}
+		if res.SBOM != nil && res.Provenance != nil {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// buildkitAttestations returns the attestation manifests of a platform
+// instance stored by BuildKit in a manifest list
+func buildkitAttestations(manifest Manifest, instance digest.Digest) []digest.Digest {
+	var index imgspecv1.Index
+	if err := json.Unmarshal(manifest.Raw, &index); err != nil {
+		return nil
+	}
+	var dgsts []digest.Digest
+	for _, desc := range index.Manifests {
+		if desc.Annotations[buildkitReferenceTypeAnnotation] == buildkitAttestationManifest &&
+			desc.Annotations[buildkitReferenceDigestAnnotation] == instance.String() {
+			dgsts = append(dgsts, desc.Digest)
+		}
+	}
+	return dgsts
+}
+
+// add parses an artifact layer and keeps the first SBOM and provenance found
+func (s *SupplyChain) add(layer artifactLayer) {
+	switch layer.MediaType {
+	case SPDXMediaType, cosignSPDXMediaType:
+		s.addSBOM(parseSPDX(layer.Payload))
+	case CycloneDXMediaType:
+		s.addSBOM(parseCycloneDX(layer.Payload))
+	case InTotoMediaType:
+		s.addStatement(layer.Payload)
+	case DSSEEnvelopeType:
+		var env struct {
+			PayloadType string `json:"payloadType"`
+			Payload     string `json:"payload"`
+		}
+		if err := json.Unmarshal(layer.Payload, &env); err != nil {
+			return
+		}
+		if payload, err := base64.StdEncoding.DecodeString(env.Payload); err == nil {
+			s.addStatement(payload)
+		}
+	}
+}
+
+func (s *SupplyChain) addSBOM(sbom *SBOM) {
+	if s.SBOM == nil && sbom != nil {
+		s.SBOM = sbom
+	}
+}
+
+// addStatement parses an in-toto statement holding an SBOM or a SLSA
+// provenance predicate
+func (s *SupplyChain) addStatement(payload []byte) {
+	var statement struct {
+		PredicateType string          `json:"predicateType"`
+		Predicate     json.RawMessage `json:"predicate"`
+	}
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return
+	}
+	switch {
+	case strings.HasPrefix(statement.PredicateType, "https://spdx.dev/Document"):
+		s.addSBOM(parseSPDX(statement.Predicate))
+	case strings.HasPrefix(statement.PredicateType, "https://cyclonedx.org/bom"):
+		s.addSBOM(parseCycloneDX(statement.Predicate))
+	case strings.HasPrefix(statement.PredicateType, "https://slsa.dev/provenance/"):
+		if s.Provenance == nil {
+			s.Provenance = parseProvenance(statement.PredicateType, statement.Predicate)
+		}
+	}
+}
+
+func parseSPDX(b []byte) *SBOM {
+	var doc struct {
+		SPDXVersion string `json:"spdxVersion"`
+		Packages    []struct {
+			Name        string `json:"name"`
+			VersionInfo string `json:"versionInfo"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil || doc.SPDXVersion == "" {
+		return nil
+	}
+	sbom := &SBOM{Format: SBOMFormatSPDX}
+	for _, pkg := range doc.Packages {
+		sbom.Packages = append(sbom.Packages, Package{Name: pkg.Name, Version: pkg.VersionInfo})
+	}
+	return sbom
+}
+
+func parseCycloneDX(b []byte) *SBOM {
+	var doc struct {
+		BOMFormat  string `json:"bomFormat"`
+		Components []struct {
+			Group   string `json:"group"`
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil || doc.BOMFormat != "CycloneDX" {
+		return nil
+	}
+	sbom := &SBOM{Format: SBOMFormatCycloneDX}
+	for _, cpt := range doc.Components {
+		name := cpt.Name
+		if cpt.Group != "" {
+			name = cpt.Group + "/" + cpt.Name
+		}
+		sbom.Packages = append(sbom.Packages, Package{Name: name, Version: cpt.Version})
+	}
+	return sbom
+}
+
+func parseProvenance(predicateType string, b []byte) *Provenance {
+	var predicate struct {
+		// SLSA v0.2
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+		BuildType  string `json:"buildType"`
+		Invocation struct {
+			ConfigSource struct {
+				URI    string            `json:"uri"`
+				Digest map[string]string `json:"digest"`
+			} `json:"configSource"`
+		} `json:"invocation"`
+		// SLSA v1
+		BuildDefinition struct {
+			BuildType            string `json:"buildType"`
+			ResolvedDependencies []struct {
+				URI    string            `json:"uri"`
+				Digest map[string]string `json:"digest"`
+			} `json:"resolvedDependencies"`
+		} `json:"buildDefinition"`
+		RunDetails struct {
+			Builder struct {
+				ID string `json:"id"`
+			} `json:"builder"`
+		} `json:"runDetails"`
+	}
+	if err := json.Unmarshal(b, &predicate); err != nil {
+		return nil
+	}
+
+	prov := &Provenance{
+		PredicateType: predicateType,
+		BuilderID:     predicate.Builder.ID,
+		BuildType:     predicate.BuildType,
+		Source:        predicate.Invocation.ConfigSource.URI,
+		Revision:      sourceRevision(predicate.Invocation.ConfigSource.Digest),
+	}
+	if predicate.RunDetails.Builder.ID != "" {
+		prov.BuilderID = predicate.RunDetails.Builder.ID
+	}
+	if predicate.BuildDefinition.BuildType != "" {
+		prov.BuildType = predicate.BuildDefinition.BuildType
+	}
+	if prov.Source == "" && len(predicate.BuildDefinition.ResolvedDependencies) > 0 {
+		prov.Source = predicate.BuildDefinition.ResolvedDependencies[0].URI
+		prov.Revision = sourceRevision(predicate.BuildDefinition.ResolvedDependencies[0].Digest)
+	}
+	return prov
+}
+
+func sourceRevision(dgst map[string]string) string {
+	for _, alg := range []string{"gitCommit", "sha1", "sha256"} {
+		if v, ok := dgst[alg]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// DiffPackages returns the package changes from an old list of packages to
+// a new one. Packages are matched by name.
+func DiffPackages(oldPkgs, newPkgs []Package) PackageDiff {
+	oldVersions := packageVersions(oldPkgs)
+	newVersions := packageVersions(newPkgs)
+
+	var diff PackageDiff
+	for name, version := range newVersions {
+		oldVersion, ok := oldVersions[name]
+		if !ok {
+			diff.Added = append(diff.Added, Package{Name: name, Version: version})
+		} else if oldVersion != version {
+			diff.Updated = append(diff.Updated, PackageUpdate{Name: name, From: oldVersion, To: version})
+		}
+	}
+	for name, version := range oldVersions {
+		if _, ok := newVersions[name]; !ok {
+			diff.Removed = append(diff.Removed, Package{Name: name, Version: version})
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Updated, func(i, j int) bool { return diff.Updated[i].Name < diff.Updated[j].Name })
+
+	return diff
+}
+
+// packageVersions returns the versions of each package. Several versions of
+// the same package are joined with a comma.
+func packageVersions(pkgs []Package) map[string]string {
+	versions := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if pkg.Name == "" || slices.Contains(versions[pkg.Name], pkg.Version) {
+			continue
+		}
+		versions[pkg.Name] = append(versions[pkg.Name], pkg.Version)
+	}
+	res := make(map[string]string, len(versions))
+	for name, v := range versions {
+		sort.Strings(v)
+		res[name] = strings.Join(v, ", ")
+	}
+	return res
+}
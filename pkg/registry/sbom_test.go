@@ -0,0 +1,202 @@
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSPDX = `{"spdxVersion":"SPDX-2.3","packages":[{"name":"busybox","versionInfo":"1.36.1-r15"},{"name":"musl","versionInfo":"1.2.4-r2"}]}`
+	testSLSA = `{"builder":{"id":"https://github.com/actions/runner"},"buildType":"https://mobyproject.org/buildkit@v1","invocation":{"configSource":{"uri":"https://github.com/crazy-max/diun.git","digest":{"sha1":"0123456789abcdef"}}}}`
+)
+
+func TestSupplyChainReferrers(t *testing.T) {
+	reg := newTestRegistry(t, "acme/app")
+	img := newTestRegistryImage(t, imgspecv1.Platform{OS: "linux", Architecture: "amd64"}, "", nil)
+	reg.addImage("1.0.0", img)
+	reg.addReferrer(img.manifest.digest, SPDXMediaType, reg.addArtifactManifest(t, SPDXMediaType, []byte(testSPDX), nil))
+	reg.addReferrer(img.manifest.digest, DSSEEnvelopeType, reg.addArtifactManifest(t, DSSEEnvelopeType, testDSSEStatement(t, "https://slsa.dev/provenance/v0.2", testSLSA), nil))
+	reg.addReferrer(img.manifest.digest, CosignSignatureArtifactType, reg.addArtifactManifest(t, CosignSimpleSigningType, []byte(`{}`), nil))
+
+	image, err := ParseImage(ParseImageOptions{Name: reg.imageName("1.0.0")})
+	require.NoError(t, err)
+
+	sc, err := newTestRegistryClient(t, Options{}).SupplyChain(image, Manifest{
+		MIMEType: img.manifest.mediaType,
+		Digest:   img.manifest.digest,
+		Raw:      img.manifest.body,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &SupplyChain{
+		SBOM: &SBOM{
+			Format: SBOMFormatSPDX,
+			Packages: []Package{
+				{Name: "busybox", Version: "1.36.1-r15"},
+				{Name: "musl", Version: "1.2.4-r2"},
+			},
+		},
+		Provenance: &Provenance{
+			PredicateType: "https://slsa.dev/provenance/v0.2",
+			BuilderID:     "https://github.com/actions/runner",
+			BuildType:     "https://mobyproject.org/buildkit@v1",
+			Source:        "https://github.com/crazy-max/diun.git",
+			Revision:      "0123456789abcdef",
+		},
+	}, sc)
+}
+
+func TestSupplyChainSkipsLargeLayer(t *testing.T) {
+	reg := newTestRegistry(t, "acme/app")
+	img := newTestRegistryImage(t, imgspecv1.Platform{OS: "linux", Architecture: "amd64"}, "", nil)
+	reg.addImage("1.0.0", img)
+	large := reg.addArtifactManifest(t, SPDXMediaType, make([]byte, maxArtifactBlobSize+1), nil)
+	reg.addReferrer(img.manifest.digest, SPDXMediaType, large)
+	reg.addReferrer(img.manifest.digest, DSSEEnvelopeType, reg.addArtifactManifest(t, DSSEEnvelopeType, testDSSEStatement(t, "https://slsa.dev/provenance/v0.2", testSLSA), nil))
+
+	image, err := ParseImage(ParseImageOptions{Name: reg.imageName("1.0.0")})
+	require.NoError(t, err)
+
+	sc, err := newTestRegistryClient(t, Options{}).SupplyChain(image, Manifest{
+		MIMEType: img.manifest.mediaType,
+		Digest:   img.manifest.digest,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, sc.SBOM)
+	require.NotNil(t, sc.Provenance)
+	assert.Equal(t, "https://github.com/actions/runner", sc.Provenance.BuilderID)
+	require.Len(t, sc.Skipped, 1)
+	assert.Equal(t, digest.FromBytes(make([]byte, maxArtifactBlobSize+1)), sc.Skipped[0].Digest)
+}
+
+func TestSupplyChainCosignTag(t *testing.T) {
+	reg := newTestRegistry(t, "acme/app")
+	img := newTestRegistryImage(t, imgspecv1.Platform{OS: "linux", Architecture: "amd64"}, "", nil)
+	reg.addImage("1.0.0", img)
+	reg.addManifest(fmt.Sprintf("sha256-%s.sbom", img.manifest.digest.Encoded()), reg.addArtifactManifest(t, CycloneDXMediaType,
+		[]byte(`{"bomFormat":"CycloneDX","components":[{"group":"org.apache","name":"log4j-core","version":"2.17.1"}]}`), nil))
+
+	image, err := ParseImage(ParseImageOptions{Name: reg.imageName("1.0.0")})
+	require.NoError(t, err)
+
+	sc, err := newTestRegistryClient(t, Options{}).SupplyChain(image, Manifest{
+		MIMEType: img.manifest.mediaType,
+		Digest:   img.manifest.digest,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &SupplyChain{
+		SBOM: &SBOM{
+			Format:   SBOMFormatCycloneDX,
+			Packages: []Package{{Name: "org.apache/log4j-core", Version: "2.17.1"}},
+		},
+	}, sc)
+}
+
+func TestSupplyChainBuildKitAttestations(t *testing.T) {
+	reg := newTestRegistry(t, "acme/app")
+	img := newTestRegistryImage(t, imgspecv1.Platform{OS: "linux", Architecture: "amd64"}, "", nil)
+	reg.addManifest(img.manifest.digest.String(), img.manifest)
+
+	spdxStatement := fmt.Appendf(nil, `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://spdx.dev/Document","predicate":%s}`, testSPDX)
+	attestation := reg.addArtifactManifest(t, InTotoMediaType, spdxStatement, map[string]string{
+		"in-toto.io/predicate-type": "https://spdx.dev/Document",
+	})
+	reg.addManifest(attestation.digest.String(), attestation)
+
+	index, err := json.Marshal(imgspecv1.Index{
+		Versioned: specsVersioned,
+		MediaType: imgspecv1.MediaTypeImageIndex,
+		Manifests: []imgspecv1.Descriptor{
+			{
+				MediaType: img.manifest.mediaType,
+				Digest:    img.manifest.digest,
+				Size:      int64(len(img.manifest.body)),
+				Platform:  &imgspecv1.Platform{OS: "linux", Architecture: "amd64"},
+			},
+			{
+				MediaType: imgspecv1.MediaTypeImageManifest,
+				Digest:    attestation.digest,
+				Size:      int64(len(attestation.body)),
+				Platform:  &imgspecv1.Platform{OS: "unknown", Architecture: "unknown"},
+				Annotations: map[string]string{
+					buildkitReferenceTypeAnnotation:   buildkitAttestationManifest,
+					buildkitReferenceDigestAnnotation: img.manifest.digest.String(),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	image, err := ParseImage(ParseImageOptions{Name: reg.imageName("1.0.0")})
+	require.NoError(t, err)
+
+	sc, err := newTestRegistryClient(t, Options{}).SupplyChain(image, Manifest{
+		MIMEType: imgspecv1.MediaTypeImageIndex,
+		Digest:   digest.FromBytes(index),
+		Raw:      index,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, sc.SBOM)
+	assert.Equal(t, SBOMFormatSPDX, sc.SBOM.Format)
+	assert.Len(t, sc.SBOM.Packages, 2)
+	assert.Nil(t, sc.Provenance)
+}
+
+func TestDiffPackages(t *testing.T) {
+	diff := DiffPackages([]Package{
+		{Name: "busybox", Version: "1.36.1-r15"},
+		{Name: "musl", Version: "1.2.4-r2"},
+		{Name: "zlib", Version: "1.3-r0"},
+		{Name: "ssl_client", Version: "1.36.1-r15"},
+	}, []Package{
+		{Name: "busybox", Version: "1.36.1-r29"},
+		{Name: "musl", Version: "1.2.4-r2"},
+		{Name: "ssl_client", Version: "1.36.1-r29"},
+		{Name: "libcrypto3", Version: "3.1.4-r5"},
+	})
+	assert.Equal(t, PackageDiff{
+		Added:   []Package{{Name: "libcrypto3", Version: "3.1.4-r5"}},
+		Removed: []Package{{Name: "zlib", Version: "1.3-r0"}},
+		Updated: []PackageUpdate{
+			{Name: "busybox", From: "1.36.1-r15", To: "1.36.1-r29"},
+			{Name: "ssl_client", From: "1.36.1-r15", To: "1.36.1-r29"},
+		},
+	}, diff)
+	assert.False(t, diff.Empty())
+	assert.True(t, DiffPackages([]Package{{Name: "musl", Version: "1.2.4-r2"}}, []Package{{Name: "musl", Version: "1.2.4-r2"}}).Empty())
+}
+
+func TestParseProvenanceV1(t *testing.T) {
+	prov := parseProvenance("https://slsa.dev/provenance/v1", []byte(`{
+		"buildDefinition": {
+			"buildType": "https://actions.github.io/buildtypes/workflow/v1",
+			"resolvedDependencies": [{"uri": "git+https://github.com/crazy-max/diun@refs/heads/master", "digest": {"gitCommit": "abcdef"}}]
+		},
+		"runDetails": {"builder": {"id": "https://github.com/actions/runner/github-hosted"}}
+	}`))
+	assert.Equal(t, &Provenance{
+		PredicateType: "https://slsa.dev/provenance/v1",
+		BuilderID:     "https://github.com/actions/runner/github-hosted",
+		BuildType:     "https://actions.github.io/buildtypes/workflow/v1",
+		Source:        "git+https://github.com/crazy-max/diun@refs/heads/master",
+		Revision:      "abcdef",
+	}, prov)
+}
+
+func testDSSEStatement(t *testing.T, predicateType string, predicate string) []byte {
+	t.Helper()
+	statement := fmt.Appendf(nil, `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":%q,"predicate":%s}`, predicateType, predicate)
+	env, err := json.Marshal(map[string]any{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString(statement),
+		"signatures":  []any{},
+	})
+	require.NoError(t, err)
+	return env
+}
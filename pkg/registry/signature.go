@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/types"
+)
+
+// Cosign media types and annotations
+const (
+	CosignSignatureArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	CosignSimpleSigningType     = "application/vnd.dev.cosign.simplesigning.v1+json"
+	DSSEEnvelopeType            = "application/vnd.dsse.envelope.v1+json"
+
+	cosignSignatureAnnotation   = "dev.cosignproject.cosign/signature"
+	cosignCertificateAnnotation = "dev.sigstore.cosign/certificate"
+	cosignChainAnnotation       = "dev.sigstore.cosign/chain"
+	cosignBundleAnnotation      = "dev.sigstore.cosign/bundle"
+)
+
+// Signature holds a cosign signature or attestation attached to a manifest.
+// For attestations, Payload is a DSSE envelope and Signature is empty.
+type Signature struct {
+	MediaType   string
+	Payload     []byte
+	Signature   string
+	Certificate string
+	Chain       string
+	Bundle      string
+}
+
+// Signatures returns the cosign signatures of a manifest found through the
+// tag scheme (sha256-<hex>.sig) and the OCI referrers API.
+func (c *Client) Signatures(image Image, dgst digest.Digest) ([]Signature, error) {
+	return c.cosignArtifacts(image, dgst, "sig", CosignSignatureArtifactType)
+}
+
+// Attestations returns the cosign attestations of a manifest found through
+// the tag scheme (sha256-<hex>.att) and the OCI referrers API.
+func (c *Client) Attestations(image Image, dgst digest.Digest) ([]Signature, error) {
+	return c.cosignArtifacts(image, dgst, "att", DSSEEnvelopeType)
+}
+
+func (c *Client) cosignArtifacts(image Image, dgst digest.Digest, suffix string, artifactType string) ([]Signature, error) {
+	var sigs []Signature
+	err := c.do(func() (err error) {
+		sigs, err = c.cosignArtifactsOnce(image, dgst, suffix, artifactType)
+		return err
+	})
+	return sigs, err
+}
+
+func (c *Client) cosignArtifactsOnce(image Image, dgst digest.Digest, suffix string, artifactType string) ([]Signature, error) {
+	ctx, cancel := c.timeoutContext()
+	defer cancel()
+
+	var sigs []Signature
+	tag := fmt.Sprintf("%s-%s.%s", dgst.Algorithm(), dgst.Encoded(), suffix)
+	tagRef, err := ImageReference(fmt.Sprintf("%s:%s", image.Name(), tag))
+	if err != nil {
+		return nil, err
+	}
+	tagSigs, err := c.signatureManifest(ctx, tagRef)
+	if err != nil && !isNotFound(err) {
+		return nil, errors.Wrapf(err, "cannot retrieve %s", tag)
+	}
+	sigs = append(sigs, tagSigs...)
+
+	referrers, err := c.referrers(ctx, image, dgst, artifactType)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot retrieve referrers")
+	}
+	for _, referrer := range referrers {
+		ref, err := digestReference(image, referrer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		refSigs, err := c.signatureManifest(ctx, ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot retrieve referrer %s", referrer.Digest)
+		}
+		sigs = append(sigs, refSigs...)
+	}
+
+	return sigs, nil
+}
+
+// signatureManifest returns the signatures held by the layers of a cosign
+// signature or attestation manifest
+func (c *Client) signatureManifest(ctx context.Context, ref types.ImageReference) ([]Signature, error) {
+	layers, _, err := c.artifactLayers(ctx, ref, CosignSimpleSigningType, DSSEEnvelopeType)
+	if err != nil {
+		return nil, err
+	}
+	sigs := make([]Signature, 0, len(layers))
+	for _, layer := range layers {
+		sigs = append(sigs, Signature{
+			MediaType:   layer.MediaType,
+			Payload:     layer.Payload,
+			Signature:   layer.Annotations[cosignSignatureAnnotation],
+			Certificate: layer.Annotations[cosignCertificateAnnotation],
+			Chain:       layer.Annotations[cosignChainAnnotation],
+			Bundle:      layer.Annotations[cosignBundleAnnotation],
+		})
+	}
+	return sigs, nil
+}
+
+// isNotFound checks if an error is due to a missing manifest
+func isNotFound(err error) bool {
+	if status, ok := httpStatus(err); ok {
+		return status == http.StatusNotFound
+	}
+	return strings.Contains(err.Error(), "manifest unknown")
+}
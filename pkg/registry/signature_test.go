@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatures(t *testing.T) {
+	reg := newTestRegistry(t, "acme/app")
+	img := newTestRegistryImage(t, imgspecv1.Platform{OS: "linux", Architecture: "amd64"}, "", nil)
+	reg.addImage("1.0.0", img)
+
+	tagSig := reg.addArtifactManifest(t, CosignSimpleSigningType, []byte(`{"critical":{"type":"tag"}}`), map[string]string{
+		cosignSignatureAnnotation:   "MEUCIQ==",
+		cosignCertificateAnnotation: "-----BEGIN CERTIFICATE-----",
+		cosignBundleAnnotation:      `{"SignedEntryTimestamp":""}`,
+	})
+	reg.addManifest(fmt.Sprintf("sha256-%s.sig", img.manifest.digest.Encoded()), tagSig)
+	reg.addReferrer(img.manifest.digest, CosignSignatureArtifactType, reg.addArtifactManifest(t, CosignSimpleSigningType, []byte(`{"critical":{"type":"referrer"}}`), map[string]string{
+		cosignSignatureAnnotation: "MEYCIQ==",
+	}))
+	reg.addReferrer(img.manifest.digest, "application/spdx+json", reg.addArtifactManifest(t, "application/spdx+json", []byte(`{}`), nil))
+
+	image, err := ParseImage(ParseImageOptions{Name: reg.imageName("1.0.0")})
+	require.NoError(t, err)
+	client := newTestRegistryClient(t, Options{})
+
+	sigs, err := client.Signatures(image, img.manifest.digest)
+	require.NoError(t, err)
+	assert.Equal(t, []Signature{
+		{
+			MediaType:   CosignSimpleSigningType,
+			Payload:     []byte(`{"critical":{"type":"tag"}}`),
+			Signature:   "MEUCIQ==",
+			Certificate: "-----BEGIN CERTIFICATE-----",
+			Bundle:      `{"SignedEntryTimestamp":""}`,
+		},
+		{
+			MediaType: CosignSimpleSigningType,
+			Payload:   []byte(`{"critical":{"type":"referrer"}}`),
+			Signature: "MEYCIQ==",
+		},
+	}, sigs)
+
+	atts, err := client.Attestations(image, img.manifest.digest)
+	require.NoError(t, err)
+	assert.Empty(t, atts)
+}
+
+func TestSignaturesUnsigned(t *testing.T) {
+	reg := newTestRegistry(t, "acme/app")
+	img := newTestRegistryImage(t, imgspecv1.Platform{OS: "linux", Architecture: "amd64"}, "", nil)
+	reg.addImage("1.0.0", img)
+
+	image, err := ParseImage(ParseImageOptions{Name: reg.imageName("1.0.0")})
+	require.NoError(t, err)
+
+	sigs, err := newTestRegistryClient(t, Options{}).Signatures(image, img.manifest.digest)
+	require.NoError(t, err)
+	assert.Empty(t, sigs)
+}
+
+func TestSignaturesLayerDigestMismatch(t *testing.T) {
+	reg := newTestRegistry(t, "acme/app")
+	img := newTestRegistryImage(t, imgspecv1.Platform{OS: "linux", Architecture: "amd64"}, "", nil)
+	reg.addImage("1.0.0", img)
+
+	payload := []byte(`{"critical":{}}`)
+	sig := reg.addArtifactManifest(t, CosignSimpleSigningType, payload, nil)
+	reg.configBlobs[digest.FromBytes(payload).String()] = []byte(`{"critical":{"tampered":true}}`)
+	reg.addManifest(fmt.Sprintf("sha256-%s.sig", img.manifest.digest.Encoded()), sig)
+
+	image, err := ParseImage(ParseImageOptions{Name: reg.imageName("1.0.0")})
+	require.NoError(t, err)
+
+	_, err = newTestRegistryClient(t, Options{}).Signatures(image, img.manifest.digest)
+	require.Error(t, err)
+}
@@ -5,11 +5,10 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/crazy-max/diun/v4/internal/matcher"
-	"github.com/pkg/errors"
-	"go.podman.io/image/v5/docker"
 	"golang.org/x/mod/semver"
 )
 
@@ -21,32 +20,52 @@ type Tags struct {
 	NotIncluded int
 	Excluded    int
 	Artifacts   int
+	Variants    int
 	Total       int
 }
 
 // TagsOptions holds docker tags image options
 type TagsOptions struct {
-	Image   Image
-	Max     int
-	Sort    SortTag
-	Include []string
-	Exclude []string
+	Image     Image
+	Max       int
+	Sort      SortTag
+	SortRegex string
+	Include   []string
+	Exclude   []string
+	Variant   bool
+
+	// Cache holds the tags listing of a previous run. If set, it is
+	// revalidated with conditional requests and updated in place. The
+	// registry is not queried if it has been fetched less than CacheTTL ago.
+	Cache    *TagsCache
+	CacheTTL time.Duration
 }
 
 // Tags returns tags of a Docker repository
 func (c *Client) Tags(opts TagsOptions) (*Tags, error) {
-	ctx, cancel := c.timeoutContext()
-	defer cancel()
-
-	imgRef, err := ImageReference(opts.Image.String())
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot parse reference")
-	}
-
-	tags, err := docker.GetRepositoryTags(ctx, c.sysCtx, imgRef)
-	if err != nil {
-		return nil, err
+	cache := opts.Cache
+	if cache == nil || opts.CacheTTL <= 0 || time.Since(cache.Fetched) >= opts.CacheTTL {
+		if err := c.withMirrors(opts.Image, func(client *Client, image Image) error {
+			return client.do(func() error {
+				ctx, cancel := client.timeoutContext()
+				defer cancel()
+				var err error
+				cache, err = client.listTags(ctx, image, opts.Cache)
+				if _, ok := httpStatus(err); err != nil && !ok && ctx.Err() == nil {
+					// registry not reachable over HTTPS with the settings of
+					// the client (e.g. plain HTTP or registries.conf)
+					cache, err = client.repositoryTags(ctx, image)
+				}
+				return err
+			})
+		}); err != nil {
+			return nil, err
+		}
+		if opts.Cache != nil {
+			*opts.Cache = *cache
+		}
 	}
+	tags := cache.Tags()
 
 	res := &Tags{
 		NotIncluded: 0,
@@ -54,8 +73,10 @@ func (c *Client) Tags(opts TagsOptions) (*Tags, error) {
 		Total:       len(tags),
 	}
 
-	// Sort tags
-	tags = SortTags(tags, opts.Sort)
+	// Only compare against tags of the same variant if the image tag is
+	// versioned (e.g. 1.2-alpine)
+	variant, variantOk := TagVariant(opts.Image.Tag)
+	variantOk = variantOk && opts.Variant
 
 	// Filter
 	for _, tag := range tags {
@@ -68,10 +89,26 @@ func (c *Client) Tags(opts TagsOptions) (*Tags, error) {
 		} else if matcher.IsExcluded(tag, opts.Exclude) {
 			res.Excluded++
 			continue
+		} else if v, ok := TagVariant(tag); variantOk && (!ok || v != variant) {
+			res.Variants++
+			continue
 		}
 		res.List = append(res.List, tag)
 	}
 
+	// Sort tags
+	switch opts.Sort {
+	case SortTagCreated:
+		res.List = c.sortTagsCreated(opts.Image, res.List)
+	case SortTagRegex:
+		var err error
+		if res.List, err = SortTagsRegex(res.List, opts.SortRegex); err != nil {
+			return nil, err
+		}
+	default:
+		res.List = SortTags(res.List, opts.Sort)
+	}
+
 	if opts.Max > 0 && len(res.List) >= opts.Max {
 		res.List = res.List[:opts.Max]
 	}
@@ -116,6 +153,8 @@ func SortTags(tags []string, sortTag SortTag) []string {
 			return strings.Compare(tags[i], tags[j]) < 0
 		})
 		return tags
+	case SortTagCalver:
+		return sortTagsCalver(tags)
 	default:
 		return tags
 	}
@@ -130,6 +169,9 @@ const (
 	SortTagReverse         = SortTag("reverse")
 	SortTagLexicographical = SortTag("lexicographical")
 	SortTagSemver          = SortTag("semver")
+	SortTagCalver          = SortTag("calver")
+	SortTagCreated         = SortTag("created")
+	SortTagRegex           = SortTag("regex")
 )
 
 // SortTagTypes is the list of available sort tag types
@@ -138,6 +180,9 @@ var SortTagTypes = []SortTag{
 	SortTagReverse,
 	SortTagLexicographical,
 	SortTagSemver,
+	SortTagCalver,
+	SortTagCreated,
+	SortTagRegex,
 }
 
 // Valid checks sort tag type is valid
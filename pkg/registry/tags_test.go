@@ -1,10 +1,16 @@
 package registry
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/types"
 )
 
 func TestTags(t *testing.T) {
@@ -300,3 +306,173 @@ func TestTagsSort(t *testing.T) {
 		})
 	}
 }
+
+func TestTagsCache(t *testing.T) {
+	registry := newTestRegistry(t, "acme/diun")
+	registry.addTagsPage("", []string{"1.0.0", "1.1.0"}, `</v2/acme/diun/tags/list?page=2>; rel="next"`)
+	registry.addTagsPage("2", []string{"2.0.0"}, "")
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: registry.imageName("1.0.0"),
+	})
+	require.NoError(t, err)
+
+	client := newTestRegistryClient(t, Options{})
+	cache := &TagsCache{}
+	tags, err := client.Tags(TagsOptions{Image: image, Cache: cache})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "1.1.0", "2.0.0"}, tags.List)
+	require.Len(t, cache.Pages, 2)
+	assert.NotEmpty(t, cache.Pages[0].ETag)
+	assert.Equal(t, "https://"+registry.host()+"/v2/acme/diun/tags/list", cache.Endpoint)
+	assert.Equal(t, "https://"+registry.host()+"/v2/acme/diun/tags/list?page=2", cache.Pages[0].Next)
+
+	// pages not modified are reused
+	registry.addTagsPage("2", []string{"2.0.0", "2.1.0"}, "")
+	tags, err = client.Tags(TagsOptions{Image: image, Cache: cache})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "1.1.0", "2.0.0", "2.1.0"}, tags.List)
+	assert.Equal(t, 2, registry.requestCount("GET", "/v2/acme/diun/tags/list"))
+
+	// registry is not queried within the TTL
+	registry.addTagsPage("2", []string{"3.0.0"}, "")
+	tags, err = client.Tags(TagsOptions{Image: image, Cache: cache, CacheTTL: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "1.1.0", "2.0.0", "2.1.0"}, tags.List)
+	assert.Equal(t, 2, registry.requestCount("GET", "/v2/acme/diun/tags/list"))
+
+	// pages of a listing fetched from another endpoint are not reused
+	cache.Endpoint = "https://mirror.example.com/v2/acme/diun/tags/list"
+	tags, err = client.Tags(TagsOptions{Image: image, Cache: cache})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "1.1.0", "3.0.0"}, tags.List)
+	assert.Equal(t, "https://"+registry.host()+"/v2/acme/diun/tags/list", cache.Endpoint)
+}
+
+func TestNextLink(t *testing.T) {
+	next, err := nextLink("https://registry.test/v2/acme/diun/tags/list", `</v2/acme/diun/tags/list?last=1.0.0>; rel="next"`)
+	require.NoError(t, err)
+	assert.Equal(t, "https://registry.test/v2/acme/diun/tags/list?last=1.0.0", next)
+
+	next, err = nextLink("https://registry.test/v2/acme/diun/tags/list", `<https://registry.test/v2/acme/diun/tags/list?last=1.0.0>; rel="next"`)
+	require.NoError(t, err)
+	assert.Equal(t, "https://registry.test/v2/acme/diun/tags/list?last=1.0.0", next)
+
+	next, err = nextLink("https://registry.test/v2/acme/diun/tags/list", "")
+	require.NoError(t, err)
+	assert.Empty(t, next)
+
+	_, err = nextLink("https://registry.test/v2/acme/diun/tags/list", `<https://evil.test/v2/acme/diun/tags/list?last=1.0.0>; rel="next"`)
+	require.EqualError(t, err, `next link "https://evil.test/v2/acme/diun/tags/list?last=1.0.0" is not on registry host registry.test`)
+
+	_, err = nextLink("https://registry.test/v2/acme/diun/tags/list", `<http://registry.test/v2/acme/diun/tags/list?last=1.0.0>; rel="next"`)
+	require.Error(t, err)
+}
+
+func TestTagsNextLinkOtherHost(t *testing.T) {
+	var stolen int
+	other := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stolen++
+		_, _ = w.Write([]byte(`{"tags":["2.0.0"]}`))
+	}))
+	defer other.Close()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			_, _ = w.Write([]byte(`{"access_token":"secret"}`))
+		case "/v2/acme/diun/tags/list":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="registry.test"`, r.Host))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/v2/acme/diun/tags/list?page=2>; rel="next"`, other.URL))
+				_, _ = w.Write([]byte(`{"tags":["1.0.0"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"tags":["1.1.0"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: strings.TrimPrefix(server.URL, "https://") + "/acme/diun:1.0.0",
+	})
+	require.NoError(t, err)
+
+	_, _ = newTestRegistryClient(t, Options{
+		Auth: types.DockerAuthConfig{IdentityToken: "identity"},
+	}).Tags(TagsOptions{Image: image})
+	assert.Zero(t, stolen)
+}
+
+func TestTagsAuthorizationSharedAcrossPages(t *testing.T) {
+	var tokens int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokens++
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "identity", r.PostForm.Get("refresh_token"))
+			assert.Equal(t, "repository:acme/diun:pull", r.PostForm.Get("scope"))
+			_, _ = w.Write([]byte(`{"access_token":"secret"}`))
+		case "/v2/acme/diun/tags/list":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="registry.test"`, r.Host))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", `</v2/acme/diun/tags/list?page=2>; rel="next"`)
+				_, _ = w.Write([]byte(`{"tags":["1.0.0"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"tags":["2.0.0"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: strings.TrimPrefix(server.URL, "https://") + "/acme/diun:1.0.0",
+	})
+	require.NoError(t, err)
+
+	tags, err := newTestRegistryClient(t, Options{
+		Auth: types.DockerAuthConfig{IdentityToken: "identity"},
+	}).Tags(TagsOptions{Image: image})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "2.0.0"}, tags.List)
+	assert.Equal(t, 1, tokens)
+}
+
+func TestTagsPlainHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/acme/diun/tags/list":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"name":"acme/diun","tags":["1.0.0","1.1.0"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: strings.TrimPrefix(server.URL, "http://") + "/acme/diun:1.0.0",
+	})
+	require.NoError(t, err)
+
+	tags, err := newTestRegistryClient(t, Options{}).Tags(TagsOptions{Image: image})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "1.1.0"}, tags.List)
+}
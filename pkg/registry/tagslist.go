@@ -0,0 +1,175 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.podman.io/image/v5/docker"
+)
+
+// maxTagsPages is the maximum number of pages of a tags listing
+const maxTagsPages = 10000
+
+// TagsCache holds the tags listing of a repository along with the state of
+// its pages, so they can be revalidated through conditional requests
+type TagsCache struct {
+	Endpoint string     `json:"endpoint,omitempty"`
+	Pages    []TagsPage `json:"pages,omitempty"`
+	Fetched  time.Time  `json:"fetched"`
+}
+
+// TagsPage holds a page of a tags listing
+type TagsPage struct {
+	URL  string   `json:"url"`
+	ETag string   `json:"etag,omitempty"`
+	Next string   `json:"next,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Tags returns the tags of all pages
+func (tc *TagsCache) Tags() []string {
+	var tags []string
+	for _, page := range tc.Pages {
+		tags = append(tags, page.Tags...)
+	}
+	return tags
+}
+
+// tagsEndpoint returns the URL of the tags listing of a repository
+func tagsEndpoint(image Image) string {
+	return fmt.Sprintf("https://%s/v2/%s/tags/list", registryHost(image.Domain), image.Path)
+}
+
+// listTags lists the tags of a repository following the Link header of each
+// page. Pages of the previous listing are sent with their ETag and reused if
+// the registry answers they are not modified, as long as the previous listing
+// was fetched from the same endpoint.
+func (c *Client) listTags(ctx context.Context, image Image, prev *TagsCache) (*TagsCache, error) {
+	hc, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &TagsCache{
+		Endpoint: tagsEndpoint(image),
+		Fetched:  time.Now().UTC(),
+	}
+
+	prevPages := make(map[string]TagsPage)
+	if prev != nil && prev.Endpoint == cache.Endpoint {
+		for _, page := range prev.Pages {
+			prevPages[page.URL] = page
+		}
+	}
+
+	var authorization string
+	next := cache.Endpoint
+	for next != "" {
+		if len(cache.Pages) >= maxTagsPages {
+			return nil, errors.Errorf("too many pages of tags (%d)", maxTagsPages)
+		}
+		page, err := c.tagsPage(ctx, hc, image, next, prevPages[next], &authorization)
+		if err != nil {
+			return nil, err
+		}
+		cache.Pages = append(cache.Pages, page)
+		next = page.Next
+	}
+
+	return cache, nil
+}
+
+// repositoryTags lists the tags of a repository through containers/image,
+// which honors registries.conf, plain HTTP registries and certificates
+// directories, but does not support conditional requests
+func (c *Client) repositoryTags(ctx context.Context, image Image) (*TagsCache, error) {
+	ref, err := ImageReference(image.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse reference")
+	}
+	tags, err := docker.GetRepositoryTags(ctx, c.sysCtx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &TagsCache{
+		Endpoint: tagsEndpoint(image),
+		Pages:    []TagsPage{{Tags: tags}},
+		Fetched:  time.Now().UTC(),
+	}, nil
+}
+
+// tagsPage fetches a page of a tags listing. The authorization is shared
+// across pages, which are all on the host of the registry.
+func (c *Client) tagsPage(ctx context.Context, hc *http.Client, image Image, pageURL string, prev TagsPage, authorization *string) (TagsPage, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	if prev.ETag != "" {
+		header.Set("If-None-Match", prev.ETag)
+	}
+	resp, err := c.authorizedRequest(ctx, hc, image, pageURL, header, authorization)
+	if err != nil {
+		return TagsPage{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if prev.URL != "" {
+			return prev, nil
+		}
+		return TagsPage{}, errors.New("unexpected not modified tags page")
+	default:
+		return TagsPage{}, errors.Wrap(docker.UnexpectedHTTPStatusError{StatusCode: resp.StatusCode}, "cannot list tags")
+	}
+
+	var body struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return TagsPage{}, errors.Wrap(err, "cannot decode tags list")
+	}
+
+	page := TagsPage{
+		URL:  pageURL,
+		ETag: resp.Header.Get("ETag"),
+		Tags: body.Tags,
+	}
+	if page.Next, err = nextLink(pageURL, resp.Header.Get("Link")); err != nil {
+		return TagsPage{}, err
+	}
+	return page, nil
+}
+
+// nextLink returns the absolute URL of the next page from a Link header
+// (e.g. </v2/foo/tags/list?last=bar>; rel="next"). Next pages on another
+// scheme or host are rejected so the registry credentials are not sent there.
+func nextLink(pageURL string, link string) (string, error) {
+	for value := range strings.SplitSeq(link, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(value), ";")
+		if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+			continue
+		}
+		target = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(target), "<"), ">")
+		base, err := url.Parse(pageURL)
+		if err != nil {
+			return "", errors.Wrap(err, "cannot parse tags page URL")
+		}
+		ref, err := url.Parse(target)
+		if err != nil {
+			return "", errors.Wrapf(err, "cannot parse next link %q", target)
+		}
+		next := base.ResolveReference(ref)
+		if next.Scheme != base.Scheme || next.Host != base.Host {
+			return "", errors.Errorf("next link %q is not on registry host %s", target, base.Host)
+		}
+		return next.String(), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,255 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	podmanmanifest "go.podman.io/image/v5/manifest"
+	"go.podman.io/image/v5/pkg/blobinfocache/none"
+	"go.podman.io/image/v5/types"
+)
+
+// calverRe matches calendar versions such as 2024.10.1, 24.04 or v2024-10-01
+var calverRe = regexp.MustCompile(`^v?((?:\d{4}|\d{2})(?:[._-]\d+)+)(.*)$`)
+
+// buildNumberRe matches build numbers such as r4 in 1.2.3-r4
+var buildNumberRe = regexp.MustCompile(`^r?\d+$`)
+
+// sortTagsCalver sorts calendar versioned tags in descending order. Other
+// tags are moved to the end and keep their order.
+func sortTagsCalver(tags []string) []string {
+	parts := make(map[string][]int, len(tags))
+	for _, tag := range tags {
+		if m := calverRe.FindStringSubmatch(tag); m != nil {
+			for s := range strings.FieldsFuncSeq(m[1], func(r rune) bool {
+				return r == '.' || r == '-' || r == '_'
+			}) {
+				n, _ := strconv.Atoi(s)
+				parts[tag] = append(parts[tag], n)
+			}
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		pi, oki := parts[tags[i]]
+		pj, okj := parts[tags[j]]
+		if !oki || !okj {
+			return oki
+		}
+		for k := 0; k < len(pi) && k < len(pj); k++ {
+			if pi[k] != pj[k] {
+				return pi[k] > pj[k]
+			}
+		}
+		if len(pi) != len(pj) {
+			return len(pi) > len(pj)
+		}
+		return strings.Compare(tags[i], tags[j]) < 0
+	})
+	return tags
+}
+
+// SortTagsRegex sorts tags in descending order of the named capture groups of
+// a regular expression, compared in order of appearance. Numeric values are
+// compared as numbers. Tags not matching the expression are moved to the end
+// and keep their order.
+func SortTagsRegex(tags []string, expr string) ([]string, error) {
+	if expr == "" {
+		return tags, errors.New("sort regex required")
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return tags, errors.Wrap(err, "cannot compile sort regex")
+	}
+
+	var groups []int
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			groups = append(groups, i)
+		}
+	}
+	if len(groups) == 0 {
+		return tags, errors.Errorf("sort regex %q has no named capture group", expr)
+	}
+
+	values := make(map[string][]string, len(tags))
+	for _, tag := range tags {
+		if m := re.FindStringSubmatch(tag); m != nil {
+			for _, g := range groups {
+				values[tag] = append(values[tag], m[g])
+			}
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		vi, oki := values[tags[i]]
+		vj, okj := values[tags[j]]
+		if !oki || !okj {
+			return oki
+		}
+		for k := range vi {
+			if c := compareValues(vi[k], vj[k]); c != 0 {
+				return c > 0
+			}
+		}
+		return strings.Compare(tags[i], tags[j]) < 0
+	})
+	return tags, nil
+}
+
+// compareValues compares two version components, as numbers if both are
+func compareValues(a, b string) int {
+	na, erra := strconv.ParseUint(a, 10, 64)
+	nb, errb := strconv.ParseUint(b, 10, 64)
+	if erra == nil && errb == nil {
+		switch {
+		case na > nb:
+			return 1
+		case na < nb:
+			return -1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// createdSortLimit bounds the number of tags inspected by the created sort
+const createdSortLimit = 100
+
+// sortTagsCreated sorts tags by creation date, newest first. Only the config
+// of the last createdSortLimit tags is fetched and tags that cannot be
+// inspected are moved to the end.
+func (c *Client) sortTagsCreated(image Image, tags []string) []string {
+	inspected := tags
+	if len(inspected) > createdSortLimit {
+		inspected = inspected[len(inspected)-createdSortLimit:]
+	}
+
+	created := make(map[string]time.Time, len(inspected))
+	for _, tag := range inspected {
+		img, err := ParseImage(ParseImageOptions{
+			Name: fmt.Sprintf("%s/%s:%s", image.Domain, image.Path, tag),
+		})
+		if err != nil {
+			continue
+		}
+		var t *time.Time
+		if err := c.withMirrors(img, func(client *Client, img Image) error {
+			return client.do(func() (err error) {
+				t, err = client.created(img)
+				return err
+			})
+		}); err == nil && t != nil {
+			created[tag] = *t
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return created[tags[i]].After(created[tags[j]])
+	})
+	return tags
+}
+
+// created returns the creation date of an image read from the created field
+// of its config, or from the created annotation of an artifact manifest. Only
+// the manifest and the config blob are fetched.
+func (c *Client) created(image Image) (*time.Time, error) {
+	ctx, cancel := c.timeoutContext()
+	defer cancel()
+
+	ref, err := ImageReference(image.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse reference")
+	}
+	src, err := ref.NewImageSource(ctx, c.sysCtx)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	raw, mimeType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if isManifestList(mimeType) {
+		list, err := podmanmanifest.ListFromBlob(raw, mimeType)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse manifest list")
+		}
+		instance, err := list.ChooseInstance(c.sysCtx)
+		if err != nil {
+			return nil, errors.Wrap(err, "error choosing image instance")
+		}
+		if raw, mimeType, err = src.GetManifest(ctx, &instance); err != nil {
+			return nil, err
+		}
+	}
+	m, err := podmanmanifest.FromBlob(raw, mimeType)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse manifest")
+	}
+
+	config := m.ConfigInfo()
+	switch config.MediaType {
+	case imgspecv1.MediaTypeImageConfig, podmanmanifest.DockerV2Schema2ConfigMediaType:
+	default:
+		var artifact imgspecv1.Manifest
+		if err := json.Unmarshal(raw, &artifact); err != nil {
+			return nil, errors.Wrap(err, "cannot decode manifest")
+		}
+		if t, err := time.Parse(time.RFC3339, artifact.Annotations[imgspecv1.AnnotationCreated]); err == nil {
+			return &t, nil
+		}
+		return nil, nil
+	}
+
+	rc, _, err := src.GetBlob(ctx, types.BlobInfo{Digest: config.Digest, Size: config.Size}, none.NoCache)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get image config")
+	}
+	defer rc.Close()
+	var cfg struct {
+		Created *time.Time `json:"created"`
+	}
+	if err := json.NewDecoder(io.LimitReader(rc, maxArtifactBlobSize)).Decode(&cfg); err != nil {
+		return nil, errors.Wrap(err, "cannot decode image config")
+	}
+	return cfg.Created, nil
+}
+
+// TagVariant returns the variant of a versioned tag, e.g. alpine for
+// 1.2-alpine3.19. Build numbers such as r4 and versions of the variant are
+// ignored. It returns false if the tag does not start with a version.
+func TagVariant(tag string) (string, bool) {
+	tag = strings.TrimPrefix(tag, "v")
+	if tag == "" || !unicode.IsDigit(rune(tag[0])) {
+		return "", false
+	}
+	rest := strings.TrimLeftFunc(tag, func(r rune) bool {
+		return unicode.IsDigit(r) || r == '.'
+	})
+
+	var variant []string
+	for seg := range strings.FieldsFuncSeq(rest, func(r rune) bool {
+		return r == '-' || r == '_'
+	}) {
+		if buildNumberRe.MatchString(seg) {
+			continue
+		}
+		if seg = strings.TrimRightFunc(seg, func(r rune) bool {
+			return unicode.IsDigit(r) || r == '.'
+		}); seg != "" {
+			variant = append(variant, seg)
+		}
+	}
+	return strings.Join(variant, "-"), true
+}
@@ -0,0 +1,150 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortTagsCalver(t *testing.T) {
+	tags := []string{"latest", "2023.12.1", "2024.1.0", "24.04", "2024.10.1", "2024.10", "v2024-02-01", "4.20.1", "edge"}
+	assert.Equal(t, []string{"2024.10.1", "2024.10", "v2024-02-01", "2024.1.0", "2023.12.1", "24.04", "latest", "4.20.1", "edge"}, SortTags(tags, SortTagCalver))
+}
+
+func TestSortTagsRegex(t *testing.T) {
+	tags := []string{"latest", "v1.2.3-r4", "v1.2.3-r10", "v1.10.0-r1", "v1.2.3-r9", "v1.9.9-r1"}
+	sorted, err := SortTagsRegex(tags, `^v(?P<major>\d+)\.(?P<minor>\d+)\.(?P<patch>\d+)-r(?P<build>\d+)$`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.10.0-r1", "v1.9.9-r1", "v1.2.3-r10", "v1.2.3-r9", "v1.2.3-r4", "latest"}, sorted)
+
+	_, err = SortTagsRegex(tags, `^v(\d+)$`)
+	require.ErrorContains(t, err, "no named capture group")
+	_, err = SortTagsRegex(tags, "")
+	require.ErrorContains(t, err, "sort regex required")
+}
+
+func TestTagVariant(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		expected string
+		ok       bool
+	}{
+		{tag: "1.2", expected: "", ok: true},
+		{tag: "v1.2.3-r4", expected: "", ok: true},
+		{tag: "1.2-alpine", expected: "alpine", ok: true},
+		{tag: "1.2-alpine3.19", expected: "alpine", ok: true},
+		{tag: "7.2.4-bookworm", expected: "bookworm", ok: true},
+		{tag: "3.12-slim-bookworm", expected: "slim-bookworm", ok: true},
+		{tag: "latest", expected: "", ok: false},
+		{tag: "alpine-5.0", expected: "", ok: false},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.tag, func(t *testing.T) {
+			variant, ok := TagVariant(tt.tag)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, variant)
+		})
+	}
+}
+
+func TestTagsVariant(t *testing.T) {
+	registry := newTestRegistry(t, "acme/diun")
+	registry.addTagsPage("", []string{"latest", "1.2", "1.2-alpine", "1.3-alpine3.20", "1.3", "1.3-bookworm", "alpine"}, "")
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: registry.imageName("1.2-alpine"),
+	})
+	require.NoError(t, err)
+
+	tags, err := newTestRegistryClient(t, Options{}).Tags(TagsOptions{
+		Image:   image,
+		Sort:    SortTagSemver,
+		Variant: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{
+		List:     []string{"1.3-alpine3.20", "1.2-alpine"},
+		Variants: 5,
+		Total:    7,
+	}, tags)
+}
+
+func TestTagsSortCreated(t *testing.T) {
+	registry := newTestRegistry(t, "acme/charts/app")
+	registry.addTagsPage("", []string{"1.0.0", "1.1.0", "0.9.0", "broken"}, "")
+	for tag, created := range map[string]string{
+		"1.0.0": "2026-05-01T00:00:00Z",
+		"1.1.0": "2026-04-01T00:00:00Z",
+		"0.9.0": "2026-05-20T00:00:00Z",
+	} {
+		registry.addManifest(tag, registry.addHelmChart(t, map[string]string{
+			imgspecv1.AnnotationCreated: created,
+		}))
+	}
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: registry.imageName("1.0.0"),
+	})
+	require.NoError(t, err)
+
+	tags, err := newTestRegistryClient(t, Options{Artifacts: true}).Tags(TagsOptions{
+		Image: image,
+		Sort:  SortTagCreated,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.9.0", "1.0.0", "1.1.0", "broken"}, tags.List)
+}
+
+func TestTagsSortCreatedImageConfig(t *testing.T) {
+	registry := newTestRegistry(t, "acme/app")
+	registry.addTagsPage("", []string{"1.0.0", "1.1.0"}, "")
+	img := newTestRegistryImage(t, imgspecv1.Platform{OS: "linux", Architecture: "amd64"}, "", nil)
+	registry.addImage("1.0.0", img)
+	registry.addManifest("1.1.0", registry.addHelmChart(t, map[string]string{
+		imgspecv1.AnnotationCreated: "2026-06-01T00:00:00Z",
+	}))
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: registry.imageName("1.0.0"),
+	})
+	require.NoError(t, err)
+
+	tags, err := newTestRegistryClient(t, Options{}).Tags(TagsOptions{
+		Image: image,
+		Sort:  SortTagCreated,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.1.0", "1.0.0"}, tags.List)
+	assert.Equal(t, 0, registry.requestCount(http.MethodHead, "/v2/acme/app/manifests/1.0.0"))
+	assert.Equal(t, 1, registry.requestCount(http.MethodGet, "/v2/acme/app/blobs/"+img.config.digest.String()))
+}
+
+func TestTagsSortCreatedLimit(t *testing.T) {
+	registry := newTestRegistry(t, "acme/app")
+	tags := make([]string, 0, createdSortLimit+1)
+	for i := range createdSortLimit + 1 {
+		tags = append(tags, fmt.Sprintf("build-%03d", i))
+	}
+	registry.addTagsPage("", tags, "")
+	registry.addManifest(tags[len(tags)-1], registry.addHelmChart(t, map[string]string{
+		imgspecv1.AnnotationCreated: "2026-06-01T00:00:00Z",
+	}))
+
+	image, err := ParseImage(ParseImageOptions{
+		Name: registry.imageName(tags[0]),
+	})
+	require.NoError(t, err)
+
+	res, err := newTestRegistryClient(t, Options{}).Tags(TagsOptions{
+		Image: image,
+		Sort:  SortTagCreated,
+		Max:   2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{tags[len(tags)-1], tags[0]}, res.List)
+	assert.Equal(t, 0, registry.requestCount(http.MethodGet, "/v2/acme/app/manifests/"+tags[0]))
+}
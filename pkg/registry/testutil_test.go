@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	digest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
 	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
 	podmanmanifest "go.podman.io/image/v5/manifest"
@@ -27,8 +28,11 @@ type testRegistry struct {
 	tagLinks    map[string]string
 	manifests   map[string]testRegistryBlob
 	configBlobs map[string][]byte
+	referrers   map[string][]imgspecv1.Descriptor
 }
 
+var specsVersioned = specs.Versioned{SchemaVersion: 2}
+
 type testRegistryBlob struct {
 	mediaType string
 	body      []byte
@@ -52,6 +56,7 @@ func newTestRegistry(t *testing.T, repo string) *testRegistry {
 		tagLinks:    map[string]string{},
 		manifests:   map[string]testRegistryBlob{},
 		configBlobs: map[string][]byte{},
+		referrers:   map[string][]imgspecv1.Descriptor{},
 	}
 	r.server = httptest.NewTLSServer(http.HandlerFunc(r.handle))
 	t.Cleanup(r.server.Close)
@@ -82,6 +87,16 @@ func (r *testRegistry) addManifest(ref string, manifest testRegistryBlob) {
 	r.manifests[manifest.digest.String()] = manifest
 }
 
+func (r *testRegistry) addReferrer(subject digest.Digest, artifactType string, manifest testRegistryBlob) {
+	r.manifests[manifest.digest.String()] = manifest
+	r.referrers[subject.String()] = append(r.referrers[subject.String()], imgspecv1.Descriptor{
+		MediaType:    manifest.mediaType,
+		ArtifactType: artifactType,
+		Digest:       manifest.digest,
+		Size:         int64(len(manifest.body)),
+	})
+}
+
 func (r *testRegistry) requestCount(method, path string) int {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -100,6 +115,8 @@ func (r *testRegistry) handle(w http.ResponseWriter, req *http.Request) {
 		r.handleManifest(w, req)
 	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, fmt.Sprintf("/v2/%s/blobs/", r.repo)):
 		r.handleBlob(w, req)
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, fmt.Sprintf("/v2/%s/referrers/", r.repo)):
+		r.handleReferrers(w, req)
 	default:
 		r.t.Errorf("unexpected registry request: %s %s", req.Method, req.URL.RequestURI())
 		http.NotFound(w, req)
@@ -127,6 +144,12 @@ func (r *testRegistry) handleTags(w http.ResponseWriter, req *http.Request) {
 	if link := r.tagLinks[page]; link != "" {
 		w.Header().Set("Link", link)
 	}
+	etag := fmt.Sprintf("%q", digest.FromString(strings.Join(tags, ",")).Encoded())
+	w.Header().Set("ETag", etag)
+	if req.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]any{
 		"name": r.repo,
@@ -145,7 +168,9 @@ func (r *testRegistry) handleManifest(w http.ResponseWriter, req *http.Request)
 	ref := strings.TrimPrefix(req.URL.Path, fmt.Sprintf("/v2/%s/manifests/", r.repo))
 	manifest, ok := r.manifests[ref]
 	if !ok {
-		http.NotFound(w, req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`))
 		return
 	}
 
@@ -172,6 +197,62 @@ func (r *testRegistry) handleBlob(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+func (r *testRegistry) handleReferrers(w http.ResponseWriter, req *http.Request) {
+	subject := strings.TrimPrefix(req.URL.Path, fmt.Sprintf("/v2/%s/referrers/", r.repo))
+	descriptors, ok := r.referrers[subject]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	manifests := []imgspecv1.Descriptor{}
+	for _, desc := range descriptors {
+		if artifactType := req.URL.Query().Get("artifactType"); artifactType == "" || desc.ArtifactType == artifactType {
+			manifests = append(manifests, desc)
+		}
+	}
+	w.Header().Set("Content-Type", imgspecv1.MediaTypeImageIndex)
+	if err := json.NewEncoder(w).Encode(imgspecv1.Index{
+		Versioned: specsVersioned,
+		MediaType: imgspecv1.MediaTypeImageIndex,
+		Manifests: manifests,
+	}); err != nil {
+		r.t.Errorf("cannot encode referrers response: %v", err)
+	}
+}
+
+// addArtifactManifest stores the layer of an artifact manifest holding a
+// single layer and returns the manifest without tagging it
+func (r *testRegistry) addArtifactManifest(t *testing.T, mediaType string, payload []byte, annotations map[string]string) testRegistryBlob {
+	t.Helper()
+
+	emptyConfig := []byte("{}")
+	r.configBlobs[digest.FromBytes(emptyConfig).String()] = emptyConfig
+	r.configBlobs[digest.FromBytes(payload).String()] = payload
+
+	body, err := json.Marshal(imgspecv1.Manifest{
+		Versioned: specsVersioned,
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config: imgspecv1.Descriptor{
+			MediaType: imgspecv1.MediaTypeEmptyJSON,
+			Digest:    digest.FromBytes(emptyConfig),
+			Size:      int64(len(emptyConfig)),
+		},
+		Layers: []imgspecv1.Descriptor{{
+			MediaType:   mediaType,
+			Digest:      digest.FromBytes(payload),
+			Size:        int64(len(payload)),
+			Annotations: annotations,
+		}},
+	})
+	require.NoError(t, err)
+
+	return testRegistryBlob{
+		mediaType: imgspecv1.MediaTypeImageManifest,
+		body:      body,
+		digest:    digest.FromBytes(body),
+	}
+}
+
 func newTestRegistryClient(t *testing.T, opts Options) *Client {
 	t.Helper()
 